@@ -0,0 +1,71 @@
+package graceful
+
+import (
+	"context"
+)
+
+// attachment is a context returned by AttachContext.
+type attachment struct {
+	cancel context.CancelCauseFunc
+}
+
+// AttachContext returns a context derived from ctx that is canceled when
+// shutdown begins, before managed goroutines are canceled, with ErrDraining
+// as its cause. It is meant for third-party code that only accepts a context
+// and cannot be started with Go. Contexts attached after shutdown began are
+// returned already canceled.
+//
+// The number of attached contexts that are not yet done is reported in Stats
+// and in the status report.
+//
+// Example:
+//
+//	ctx := manager.AttachContext(context.Background())
+//	go client.Subscribe(ctx, topic, handle)
+func (m *Manager) AttachContext(ctx context.Context) context.Context {
+	ctx, cancel := context.WithCancelCause(ctx)
+	a := &attachment{cancel: cancel}
+
+	m.attachMu.Lock()
+	if m.attachStopped {
+		m.attachMu.Unlock()
+		cancel(ErrDraining)
+		return ctx
+	}
+	if m.attached == nil {
+		m.attached = make(map[*attachment]struct{})
+	}
+	m.attached[a] = struct{}{}
+	m.attachMu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		m.attachMu.Lock()
+		delete(m.attached, a)
+		m.attachMu.Unlock()
+	}()
+	return ctx
+}
+
+// attachedContexts returns the number of attached contexts not yet done.
+func (m *Manager) attachedContexts() int {
+	m.attachMu.Lock()
+	defer m.attachMu.Unlock()
+	return len(m.attached)
+}
+
+// cancelAttached cancels every attached context and makes later ones start
+// canceled.
+func (m *Manager) cancelAttached() {
+	m.attachMu.Lock()
+	m.attachStopped = true
+	attached := make([]*attachment, 0, len(m.attached))
+	for a := range m.attached {
+		attached = append(attached, a)
+	}
+	m.attachMu.Unlock()
+
+	for _, a := range attached {
+		a.cancel(ErrDraining)
+	}
+}
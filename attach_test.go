@@ -0,0 +1,47 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestAttachContext 测试附加的上下文在排空开始时被取消
+func TestAttachContext(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	ctx := m.AttachContext(context.Background())
+	parent, cancelParent := context.WithCancel(context.Background())
+	released := m.AttachContext(parent)
+	if n := m.Stats().AttachedContexts; n != 2 {
+		t.Errorf("应有2个附加上下文，实际为%d", n)
+	}
+
+	cancelParent()
+	<-released.Done()
+	deadline := time.Now().Add(time.Second)
+	for m.Stats().AttachedContexts != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := m.Stats().AttachedContexts; n != 1 {
+		t.Errorf("父上下文取消后应剩1个附加上下文，实际为%d", n)
+	}
+
+	var canceledDuringDrain bool
+	m.OnDrain(func(context.Context) error {
+		canceledDuringDrain = ctx.Err() != nil
+		return nil
+	})
+	m.Shutdown()
+
+	if !canceledDuringDrain {
+		t.Error("排空函数运行时附加上下文应已取消")
+	}
+	if !errors.Is(context.Cause(ctx), ErrDraining) {
+		t.Errorf("取消原因应为ErrDraining，实际为%v", context.Cause(ctx))
+	}
+	if late := m.AttachContext(context.Background()); late.Err() == nil {
+		t.Error("关闭后附加的上下文应已取消")
+	}
+}
@@ -0,0 +1,153 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBarrierUnsupported is returned by NewHostBarrier on platforms without
+// flock advisory file locks, such as Windows, AIX and Solaris.
+var ErrBarrierUnsupported = errors.New("graceful: host barrier is not supported on this platform")
+
+// HostBarrier orders the shutdown of cooperating processes on one host, such as
+// an application and its sidecars. Each process takes a position; at shutdown
+// a process waits until every process with a lower position has finished
+// shutting down before it starts draining itself.
+//
+// Each running process holds an exclusive lock on a file in a shared
+// directory. Because the operating system drops the lock when a process exits,
+// a process that crashes never blocks the ones after it.
+//
+// HostBarrier implements DrainCoordinator and is used with WithDrainCoordinator.
+type HostBarrier struct {
+	dir      string
+	position int
+	name     string // Path of the lock file
+	file     *os.File
+	once     sync.Once
+}
+
+// NewHostBarrier joins the barrier in dir at the given position. Processes
+// with lower positions shut down first; processes sharing a position do not
+// wait for each other. dir is created if it does not exist.
+//
+// Example:
+//
+//	// App: position 0. Proxy sidecar: position 1, shuts down after the app.
+//	barrier, err := graceful.NewHostBarrier("/run/myservice", 0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	manager := graceful.New(graceful.WithDrainCoordinator(barrier, 20*time.Second))
+func NewHostBarrier(dir string, position int) (*HostBarrier, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	// The file is locked under a temporary name and then renamed into place,
+	// so that a peer never finds it unlocked and removes it as stale
+	f, err := os.CreateTemp(dir, fmt.Sprintf("%d.*.tmp", position))
+	if err != nil {
+		return nil, err
+	}
+	// Peers running as other users must be able to open the file to test it
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	if err := lockFile(f, true, false); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	name := filepath.Join(dir, fmt.Sprintf("%d.%d.lock", position, os.Getpid()))
+	if err := os.Rename(f.Name(), name); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &HostBarrier{dir: dir, position: position, name: name, file: f}, nil
+}
+
+// Acquire waits until every process with a lower position has released the
+// barrier or ctx is done. The returned function releases this process's own
+// position.
+func (b *HostBarrier) Acquire(ctx context.Context) (func(), error) {
+	ticker := time.NewTicker(time.Millisecond * 50)
+	defer ticker.Stop()
+
+	for {
+		waiting, err := b.lowerHeld()
+		if err != nil {
+			return nil, err
+		}
+		if !waiting {
+			return b.Release, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Release gives up this process's position so that processes with higher
+// positions can proceed. It is safe to call more than once.
+func (b *HostBarrier) Release() {
+	b.once.Do(func() {
+		os.Remove(b.name)
+		b.file.Close()
+	})
+}
+
+// lowerHeld reports whether any process with a lower position still holds its
+// lock. Lock files left behind by processes that exited are removed.
+func (b *HostBarrier) lowerHeld() (bool, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return false, err
+	}
+
+	held := false
+	for _, e := range entries {
+		position, ok := lockPosition(e.Name())
+		if !ok || position >= b.position {
+			continue
+		}
+		name := filepath.Join(b.dir, e.Name())
+		f, err := os.Open(name)
+		if err != nil {
+			// Removed by its owner in the meantime
+			continue
+		}
+		if lockFile(f, false, true) != nil {
+			held = true
+		} else {
+			// The owner exited without releasing
+			os.Remove(name)
+		}
+		f.Close()
+	}
+	return held, nil
+}
+
+// lockPosition parses the position from a lock file name.
+func lockPosition(name string) (int, bool) {
+	if !strings.HasSuffix(name, ".lock") {
+		return 0, false
+	}
+	prefix, _, ok := strings.Cut(name, ".")
+	if !ok {
+		return 0, false
+	}
+	position, err := strconv.Atoi(prefix)
+	return position, err == nil
+}
@@ -0,0 +1,10 @@
+//go:build !unix || aix || (solaris && !illumos)
+
+package graceful
+
+import "os"
+
+// lockFile reports ErrBarrierUnsupported on platforms without flock.
+func lockFile(f *os.File, exclusive, nonBlocking bool) error {
+	return ErrBarrierUnsupported
+}
@@ -0,0 +1,21 @@
+//go:build unix && !aix && (!solaris || illumos)
+
+package graceful
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile places an advisory lock on f. exclusive selects an exclusive rather
+// than a shared lock, and nonBlocking makes it fail instead of waiting.
+func lockFile(f *os.File, exclusive, nonBlocking bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if nonBlocking {
+		how |= syscall.LOCK_NB
+	}
+	return syscall.Flock(int(f.Fd()), how)
+}
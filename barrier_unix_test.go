@@ -0,0 +1,94 @@
+//go:build unix && !aix && (!solaris || illumos)
+
+package graceful
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHostBarrier 测试较高位置的进程等待较低位置的进程
+func TestHostBarrier(t *testing.T) {
+	dir := t.TempDir()
+
+	app, err := NewHostBarrier(dir, 0)
+	if err != nil {
+		t.Fatalf("加入屏障失败: %v", err)
+	}
+	sidecar, err := NewHostBarrier(dir, 1)
+	if err != nil {
+		t.Fatalf("加入屏障失败: %v", err)
+	}
+
+	release, err := app.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("最低位置的进程应立即获得许可，实际为%v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	if _, err := sidecar.Acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf("较低位置的进程未释放时应等待，实际为%v", err)
+	}
+
+	release()
+	if _, err := sidecar.Acquire(context.Background()); err != nil {
+		t.Errorf("较低位置的进程释放后应获得许可，实际为%v", err)
+	}
+	sidecar.Release()
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("释放后锁文件应被删除，实际剩余%d个", len(entries))
+	}
+}
+
+// TestHostBarrierStaleLock 测试已退出进程遗留的锁文件不会阻塞
+func TestHostBarrierStaleLock(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "0.99999.lock")
+	if err := os.WriteFile(stale, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewHostBarrier(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := b.Acquire(ctx); err != nil {
+		t.Errorf("遗留的锁文件不应阻塞，实际为%v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("遗留的锁文件应被清理")
+	}
+}
+
+// TestHostBarrierLockedBeforeVisible 测试锁文件出现在目录中时已被锁定
+func TestHostBarrierLockedBeforeVisible(t *testing.T) {
+	dir := t.TempDir()
+	b, err := NewHostBarrier(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Release()
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 || filepath.Ext(entries[0].Name()) != ".lock" {
+		t.Fatalf("应只剩下一个锁文件，实际为%v", entries)
+	}
+	f, err := os.Open(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if lockFile(f, false, true) == nil {
+		t.Error("锁文件出现在目录中时应已被锁定")
+	}
+}
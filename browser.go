@@ -0,0 +1,26 @@
+package graceful
+
+// WithBrowserLifecycle returns an Option that drives the manager from browser
+// page lifecycle events in Go WebAssembly applications (GOOS=js), where no OS
+// signals are delivered:
+//
+//   - visibilitychange to hidden runs the flush functions registered with
+//     OnFlush in the background, since a hidden tab may be discarded without
+//     further notice;
+//   - pagehide and beforeunload run a full shutdown and make Wait return.
+//
+// Browsers do not wait for asynchronous work while a page unloads, and the
+// shutdown runs inside the event handler, so hooks called at unload must not
+// use asynchronous JavaScript APIs such as fetch (net/http); persist state
+// with localStorage or navigator.sendBeacon instead.
+//
+// Outside GOOS=js this option has no effect.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithBrowserLifecycle())
+func WithBrowserLifecycle() Option {
+	return func(m *Manager) {
+		m.browserLifecycle = true
+	}
+}
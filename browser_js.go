@@ -0,0 +1,29 @@
+//go:build js
+
+package graceful
+
+import "syscall/js"
+
+// handleBrowserLifecycle registers listeners for page lifecycle events.
+func (m *Manager) handleBrowserLifecycle() {
+	window, document := js.Global(), js.Global().Get("document")
+	if window.IsUndefined() || document.IsUndefined() {
+		// Not running in a browser, e.g. under Node.js
+		return
+	}
+
+	document.Call("addEventListener", "visibilitychange", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if document.Get("visibilityState").String() == "hidden" {
+			go m.runFlushers()
+		}
+		return nil
+	}))
+
+	unload := js.FuncOf(func(this js.Value, args []js.Value) any {
+		// Blocking here keeps the page alive until shutdown completes
+		m.shutdown()
+		return nil
+	})
+	window.Call("addEventListener", "pagehide", unload)
+	window.Call("addEventListener", "beforeunload", unload)
+}
@@ -0,0 +1,6 @@
+//go:build !js
+
+package graceful
+
+// handleBrowserLifecycle is a no-op outside WebAssembly browser builds.
+func (m *Manager) handleBrowserLifecycle() {}
@@ -0,0 +1,103 @@
+package graceful
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventBudgetExceeded is emitted at startup when the declared drain times do
+// not fit in the shutdown timeout.
+const EventBudgetExceeded EventType = "budget_exceeded"
+
+// BudgetError reports that the drain times declared with NeedsDrainTime add up
+// to more than the shutdown timeout.
+type BudgetError struct {
+	Needed    time.Duration            // Sum of the declared drain times
+	Available time.Duration            // Shutdown timeout
+	Declared  map[string]time.Duration // Drain time declared by each component
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("graceful: components need %v to drain but the shutdown timeout is %v", e.Needed, e.Available)
+}
+
+// BudgetPolicy controls what happens at startup when the declared drain times
+// do not fit in the shutdown timeout.
+type BudgetPolicy int
+
+const (
+	// BudgetWarn logs a warning and emits EventBudgetExceeded. This is the
+	// default.
+	BudgetWarn BudgetPolicy = iota
+	// BudgetFail additionally shuts down instead of waiting; Run exits with
+	// the StartupFailure code.
+	BudgetFail
+)
+
+// WithBudgetPolicy returns an Option that sets what happens when the declared
+// drain times exceed the shutdown timeout.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithBudgetPolicy(graceful.BudgetFail))
+func WithBudgetPolicy(policy BudgetPolicy) Option {
+	return func(m *Manager) {
+		m.budgetPolicy = policy
+	}
+}
+
+// NeedsDrainTime declares how long a component expects to need for draining.
+// Declaring again for the same component replaces the previous value. Wait and
+// Run check the declared times against the shutdown timeout before waiting, so
+// a budget that cannot fit is reported at startup instead of during the first
+// real termination.
+//
+// Declared times are added up, which is exact for shutdown hooks since they
+// run one after another and conservative for goroutines, which drain
+// concurrently.
+//
+// Example:
+//
+//	manager.NeedsDrainTime("kafka-consumer", 10*time.Second)
+func (m *Manager) NeedsDrainTime(component string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.drainTimes == nil {
+		m.drainTimes = make(map[string]time.Duration)
+	}
+	m.drainTimes[component] = d
+}
+
+// CheckDrainBudget returns a *BudgetError if the declared drain times add up
+// to more than the shutdown timeout, and nil otherwise.
+func (m *Manager) CheckDrainBudget() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var needed time.Duration
+	declared := make(map[string]time.Duration, len(m.drainTimes))
+	for name, d := range m.drainTimes {
+		needed += d
+		declared[name] = d
+	}
+	if needed <= m.timeout {
+		return nil
+	}
+	return &BudgetError{Needed: needed, Available: m.timeout, Declared: declared}
+}
+
+// checkBudget applies the budget policy. It returns the budget error if the
+// caller should shut down instead of waiting.
+func (m *Manager) checkBudget() error {
+	err := m.CheckDrainBudget()
+	if err == nil {
+		return nil
+	}
+
+	m.emit(Event{Type: EventBudgetExceeded, Err: err})
+	m.logf("warning: %v", err)
+	if m.budgetPolicy == BudgetFail {
+		return err
+	}
+	return nil
+}
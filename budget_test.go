@@ -0,0 +1,55 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCheckDrainBudget 测试声明的drain时间超过超时时间时报错
+func TestCheckDrainBudget(t *testing.T) {
+	m := New(WithTimeout(time.Second * 10))
+
+	m.NeedsDrainTime("consumer", time.Second*4)
+	m.NeedsDrainTime("db", time.Second*5)
+	if err := m.CheckDrainBudget(); err != nil {
+		t.Errorf("预算足够时不应返回错误，实际为%v", err)
+	}
+
+	m.NeedsDrainTime("db", time.Second*7)
+	var budgetErr *BudgetError
+	if err := m.CheckDrainBudget(); !errors.As(err, &budgetErr) {
+		t.Fatalf("预算不足时应返回*BudgetError，实际为%v", err)
+	}
+	if budgetErr.Needed != time.Second*11 || budgetErr.Available != time.Second*10 {
+		t.Errorf("需要11s、可用10s，实际为%v和%v", budgetErr.Needed, budgetErr.Available)
+	}
+}
+
+// TestBudgetPolicy 测试预算策略的警告与快速失败
+func TestBudgetPolicy(t *testing.T) {
+	logger := &recordingLogger{}
+	warn := New(WithTimeout(time.Second), WithLogger(logger))
+	warn.NeedsDrainTime("slow", time.Second*2)
+	if _, err := warn.startup(context.Background(), nil); err != nil {
+		t.Errorf("警告模式不应返回错误，实际为%v", err)
+	}
+	if len(logger.lines) != 2 {
+		t.Errorf("应记录警告和启动摘要2条日志，实际为%d条", len(logger.lines))
+	}
+
+	fail := New(WithTimeout(time.Second), WithBudgetPolicy(BudgetFail))
+	fail.NeedsDrainTime("slow", time.Second*2)
+
+	done := make(chan struct{})
+	go func() {
+		fail.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("快速失败模式下Wait应立即返回")
+	}
+}
@@ -0,0 +1,58 @@
+package graceful
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// managerKey is the context key under which the manager stores itself in the
+// contexts it hands out.
+type managerKey struct{}
+
+// RemainingBudget returns how much time is left for work running with ctx: the
+// earlier of the context deadline and, once shutdown has begun, the end of
+// the shutdown timeout of the manager ctx derives from. It reports false if
+// neither applies, meaning the work is not bounded yet. Cleanup code and retry
+// loops use it to attempt only what fits before the deadline.
+//
+// Example:
+//
+//	for {
+//		left, ok := graceful.RemainingBudget(ctx)
+//		if ok && left < attemptTimeout {
+//			return errNoTimeLeft
+//		}
+//		if err := commit(ctx); err == nil {
+//			return nil
+//		}
+//	}
+func RemainingBudget(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if m, _ := ctx.Value(managerKey{}).(*Manager); m != nil {
+		if d := m.drainDeadline.Load(); d != 0 {
+			drain := time.Unix(0, d)
+			if !ok || drain.Before(deadline) {
+				deadline, ok = drain, true
+			}
+		}
+	}
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// WithJitter returns a Backoff that randomizes the delays of b by up to
+// fraction in either direction, so that instances retrying against the same
+// dependency during a rollout do not do so in lockstep.
+//
+// Example:
+//
+//	backoff := graceful.WithJitter(graceful.ExponentialBackoff(100*time.Millisecond, time.Second), 0.2)
+func WithJitter(b Backoff, fraction float64) Backoff {
+	return func(attempt int) time.Duration {
+		d := b(attempt)
+		return d + time.Duration((rand.Float64()*2-1)*fraction*float64(d))
+	}
+}
@@ -0,0 +1,45 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestRemainingBudget 测试任务上下文在关闭开始后可查询剩余时间
+func TestRemainingBudget(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	if _, ok := RemainingBudget(context.Background()); ok {
+		t.Error("没有截止时间的上下文不应有预算")
+	}
+
+	results := make(chan time.Duration, 2)
+	m.CtxGo(func(ctx context.Context) {
+		if _, ok := RemainingBudget(ctx); ok {
+			t.Error("关闭开始前任务不应受预算限制")
+		}
+		<-ctx.Done()
+		left, ok := RemainingBudget(ctx)
+		if !ok {
+			t.Error("关闭开始后应能查询剩余时间")
+		}
+		results <- left
+	})
+	time.Sleep(time.Millisecond * 20)
+	m.Shutdown()
+
+	if left := <-results; left <= 0 || left > time.Second {
+		t.Errorf("剩余时间应在关闭超时内，实际为%v", left)
+	}
+}
+
+// TestWithJitter 测试抖动在指定比例内
+func TestWithJitter(t *testing.T) {
+	b := WithJitter(ConstantBackoff(time.Second), 0.2)
+	for i := 1; i <= 100; i++ {
+		if d := b(i); d < time.Millisecond*800 || d > time.Millisecond*1200 {
+			t.Fatalf("延迟超出抖动范围: %v", d)
+		}
+	}
+}
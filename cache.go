@@ -0,0 +1,81 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrClientClosing is returned when an operation is attempted on a managed
+// client after shutdown has started closing it.
+var ErrClientClosing = errors.New("graceful: client is closing")
+
+// CacheClient guards a cache client, such as *redis.Client,
+// *redis.ClusterClient or *memcache.Client, so that it is closed only after
+// the commands issued through it have completed.
+type CacheClient struct {
+	guard closeGuard
+}
+
+// ManageCache registers a cache client to be closed by a shutdown hook. Because
+// hooks run after managed goroutines have exited, workers that depend on the
+// cache never see it closed underneath them. Before closing, the hook waits,
+// bounded by the remaining shutdown timeout, for commands and pipelines
+// issued through Do to finish.
+//
+// Register the client right after creating it so that hooks registered later,
+// such as ones that write final state to the cache, run before it is closed.
+//
+// Example:
+//
+//	rdb := redis.NewClient(opts)
+//	cache := manager.ManageCache(rdb)
+//	err := cache.Do(func() error {
+//		_, err := rdb.Pipelined(ctx, func(p redis.Pipeliner) error {
+//			p.Incr(ctx, "hits")
+//			return nil
+//		})
+//		return err
+//	})
+func (m *Manager) ManageCache(client io.Closer) *CacheClient {
+	c := &CacheClient{guard: closeGuard{closers: []io.Closer{client}}}
+	m.OnShutdown(c.guard.close)
+	return c
+}
+
+// Do runs fn as a tracked operation. It returns ErrClientClosing without
+// calling fn once the client has started closing.
+func (c *CacheClient) Do(fn func() error) error {
+	return c.guard.do(fn)
+}
+
+// InFlight returns the number of operations currently running through Do.
+func (c *CacheClient) InFlight() int {
+	return c.guard.inFlight.len()
+}
+
+// closeGuard closes a set of clients once the operations tracked through it
+// have completed.
+type closeGuard struct {
+	closers  []io.Closer
+	inFlight inFlight
+}
+
+// do runs fn as a tracked operation unless closing has begun.
+func (g *closeGuard) do(fn func() error) error {
+	if !g.inFlight.begin() {
+		return ErrClientClosing
+	}
+	defer g.inFlight.end()
+	return fn()
+}
+
+// close waits for in-flight operations and closes the clients in order. The
+// clients are closed even if waiting times out.
+func (g *closeGuard) close(ctx context.Context) error {
+	errs := []error{g.inFlight.close(ctx)}
+	for _, c := range g.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,48 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestManageCache 测试缓存客户端在进行中命令结束后关闭
+func TestManageCache(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	client := &closerRecorder{}
+	cache := m.ManageCache(client)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_ = cache.Do(func() error {
+			close(started)
+			<-release
+			if client.closed {
+				t.Error("进行中命令结束前不应关闭客户端")
+			}
+			return nil
+		})
+	}()
+	<-started
+
+	if cache.InFlight() != 1 {
+		t.Errorf("进行中命令数应为1，实际为%d", cache.InFlight())
+	}
+
+	m.OnShutdown(func(ctx context.Context) error {
+		// 后注册的钩子先执行，此时客户端仍可使用
+		close(release)
+		return nil
+	})
+
+	m.Shutdown()
+
+	if !client.closed {
+		t.Error("关闭时应关闭缓存客户端")
+	}
+	if err := cache.Do(func() error { return nil }); err != ErrClientClosing {
+		t.Errorf("关闭后应返回ErrClientClosing，实际为%v", err)
+	}
+}
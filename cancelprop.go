@@ -0,0 +1,35 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// WithCancelPropagationDelay returns an Option that waits d after readiness
+// turns false and the drain functions and drain strategy have run, before
+// managed goroutines are canceled. Requests admitted a moment before the
+// signal get a fair chance to complete before the contexts of the workers
+// and clients they depend on are canceled. The delay counts against the
+// shutdown timeout.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithCancelPropagationDelay(200 * time.Millisecond))
+func WithCancelPropagationDelay(d time.Duration) Option {
+	return func(m *Manager) {
+		m.cancelDelay = d
+	}
+}
+
+// delayCancel waits for the cancellation propagation delay, bounded by ctx.
+func (m *Manager) delayCancel(ctx context.Context) {
+	if m.cancelDelay <= 0 {
+		return
+	}
+	timer := time.NewTimer(m.cancelDelay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,39 @@
+package graceful
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWithCancelPropagationDelay 测试排空后延迟取消工作协程
+func TestWithCancelPropagationDelay(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithCancelPropagationDelay(time.Millisecond*50))
+
+	var drainedAt, canceledAt atomic.Int64
+	m.OnDrain(func(ctx context.Context) error {
+		drainedAt.Store(time.Now().UnixNano())
+		return nil
+	})
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		canceledAt.Store(time.Now().UnixNano())
+	})
+	m.Shutdown()
+
+	if d := time.Duration(canceledAt.Load() - drainedAt.Load()); d < time.Millisecond*50 {
+		t.Errorf("排空与取消之间应至少间隔50ms，实际为%v", d)
+	}
+}
+
+// TestWithCancelPropagationDelayTimeout 测试延迟受关闭超时限制
+func TestWithCancelPropagationDelayTimeout(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond*50), WithCancelPropagationDelay(time.Hour))
+
+	start := time.Now()
+	m.Shutdown()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("延迟应受关闭超时限制，耗时%v", elapsed)
+	}
+}
@@ -0,0 +1,75 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Child creates a nested manager for one subsystem, such as the HTTP
+// servers, the queue consumers or the cron jobs, configured with its own
+// options, for example its own timeout. Its contexts derive from the
+// parent's, and it logs to the parent's logger unless given WithLogger.
+//
+// The child is shut down when the parent's shutdown begins, concurrently with
+// the parent's own drain, and the parent waits for the child's shutdown to
+// complete, bounded by the parent's timeout, together with its own
+// goroutines, so one top-level Wait or Run drives every subsystem. A child
+// can also be shut down on its own with Shutdown. Only the parent should
+// call Wait or Run; options acting on the whole process, such as
+// WithIgnoredSignals, take effect as with New.
+//
+// Example:
+//
+//	consumers := manager.Child("consumers", graceful.WithTimeout(10*time.Second))
+//	consumers.CtxGo(consumeOrders)
+//	manager.Wait() // Also shuts down consumers
+func (m *Manager) Child(name string, opts ...Option) *Manager {
+	inherit := func(child *Manager) {
+		child.name = name
+		child.logger = m.logger
+	}
+	child := newManager(m.lifetime, append([]Option{inherit}, opts...))
+
+	m.mu.Lock()
+	m.children = append(m.children, child)
+	m.mu.Unlock()
+	return child
+}
+
+// shutdownChildren starts shutting down the child managers and returns a
+// function that waits for them until ctx is done, reporting whether they
+// all completed.
+func (m *Manager) shutdownChildren() func(ctx context.Context) bool {
+	m.mu.Lock()
+	children := append([]*Manager(nil), m.children...)
+	m.mu.Unlock()
+	if len(children) == 0 {
+		return func(context.Context) bool { return true }
+	}
+
+	var wg sync.WaitGroup
+	for _, child := range children {
+		wg.Add(1)
+		go func(child *Manager) {
+			defer wg.Done()
+			if err := child.Shutdown(); err != nil && !errors.Is(err, ErrAlreadyShutdown) {
+				m.logf("child manager %s: %v", child.name, err)
+			}
+		}(child)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	return func(ctx context.Context) bool {
+		select {
+		case <-done:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
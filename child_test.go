@@ -0,0 +1,98 @@
+package graceful
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestChildShutdown 测试父管理器关闭时等待子管理器的goroutine退出
+func TestChildShutdown(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	child := m.Child("consumers")
+
+	var exited atomic.Bool
+	child.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 30)
+		exited.Store(true)
+	})
+
+	var exitedAtHook bool
+	m.OnShutdown(func(ctx context.Context) error {
+		exitedAtHook = exited.Load()
+		return nil
+	})
+	if err := m.Shutdown(); err != nil {
+		t.Errorf("关闭不应返回错误，实际为%v", err)
+	}
+	if !exitedAtHook {
+		t.Error("父管理器的关闭钩子应在子管理器的goroutine退出后运行")
+	}
+	if child.Context().Err() == nil {
+		t.Error("子管理器应已关闭")
+	}
+	if err := child.Shutdown(); err != ErrAlreadyShutdown {
+		t.Errorf("子管理器应已关闭过，实际为%v", err)
+	}
+}
+
+// TestChildTimeout 测试子管理器使用自己的超时
+func TestChildTimeout(t *testing.T) {
+	logger := &recordingLogger{}
+	m := New(WithTimeout(time.Second), WithLogger(logger))
+	child := m.Child("cron", WithTimeout(time.Millisecond*30))
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+	child.Go(func() { <-stuck })
+
+	start := time.Now()
+	if err := m.Shutdown(); err != nil {
+		t.Errorf("子管理器超时不应使父管理器超时，实际为%v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Millisecond*500 {
+		t.Errorf("子管理器应在自己的超时后结束，实际耗时%v", elapsed)
+	}
+	found := false
+	for _, line := range logger.lines {
+		if strings.Contains(line, "child manager cron") && strings.Contains(line, "timed out") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("应记录子管理器超时，实际日志为%v", logger.lines)
+	}
+}
+
+// TestChildDryRun 测试演练计划列出子管理器
+func TestChildDryRun(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.Child("consumers", WithTimeout(time.Millisecond*200))
+
+	for _, step := range m.DryRunShutdown().Steps {
+		if step.Phase == "children" {
+			if step.Name != "shut down child manager consumers" || step.Budget != time.Millisecond*200 {
+				t.Errorf("子管理器步骤不正确: %+v", step)
+			}
+			return
+		}
+	}
+	t.Error("演练计划应包含子管理器")
+}
+
+// TestChildContext 测试子管理器的上下文派生自父管理器
+func TestChildContext(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	child := m.Child("http")
+
+	if got, _ := child.Context().Value(managerKey{}).(*Manager); got != child {
+		t.Error("子管理器的上下文应指向子管理器")
+	}
+	m.Shutdown()
+	if child.Context().Err() == nil {
+		t.Error("父管理器关闭时子管理器的上下文应被取消")
+	}
+}
@@ -0,0 +1,53 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// DrainCoordinator limits how many instances of a service drain at the same
+// time. Implementations are typically a counting semaphore with K slots kept
+// in a shared store such as etcd, Redis or Consul, keyed by service name.
+// Slots should be held under a lease or TTL so that an instance that dies
+// while draining does not keep its slot forever.
+type DrainCoordinator interface {
+	// Acquire blocks until this instance may start draining or ctx is done.
+	// On success it returns a function that gives the slot back.
+	Acquire(ctx context.Context) (release func(), err error)
+}
+
+// WithDrainCoordinator returns an Option that makes shutdown wait for
+// permission from c before draining, for at most maxWait. If no slot becomes
+// available in time, or Acquire fails, the instance drains anyway rather than
+// risk being killed without any cleanup. The slot is released once the
+// shutdown hooks have run.
+//
+// The wait happens before the shutdown timeout starts, so the platform's
+// grace period must cover maxWait plus the timeout.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithDrainCoordinator(etcdSemaphore, 30*time.Second))
+func WithDrainCoordinator(c DrainCoordinator, maxWait time.Duration) Option {
+	return func(m *Manager) {
+		m.coordinator = c
+		m.coordinatorWait = maxWait
+	}
+}
+
+// acquireDrainSlot waits for permission to drain and returns the function that
+// releases it. The returned function is never nil.
+func (m *Manager) acquireDrainSlot() (release func()) {
+	if m.coordinator == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.coordinatorWait)
+	defer cancel()
+
+	release, err := m.coordinator.Acquire(ctx)
+	if err != nil || release == nil {
+		return func() {}
+	}
+	return release
+}
@@ -0,0 +1,88 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// semaphore 是用于测试的进程内DrainCoordinator
+type semaphore chan struct{}
+
+func (s semaphore) Acquire(ctx context.Context) (func(), error) {
+	select {
+	case s <- struct{}{}:
+		return func() { <-s }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// TestDrainCoordinator 测试同时只有K个实例进行drain
+func TestDrainCoordinator(t *testing.T) {
+	sem := make(semaphore, 1)
+
+	first := New(WithTimeout(time.Second), WithDrainCoordinator(sem, time.Second))
+	second := New(WithTimeout(time.Second), WithDrainCoordinator(sem, time.Second))
+
+	release := make(chan struct{})
+	holding := make(chan struct{})
+	first.OnShutdown(func(ctx context.Context) error {
+		close(holding)
+		<-release
+		return nil
+	})
+	go first.Shutdown()
+	<-holding
+
+	drained := make(chan struct{})
+	second.OnDrain(func(ctx context.Context) error {
+		close(drained)
+		return nil
+	})
+	go second.Shutdown()
+
+	select {
+	case <-drained:
+		t.Fatal("第一个实例释放前第二个实例不应开始drain")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(release)
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("第一个实例释放后第二个实例应开始drain")
+	}
+}
+
+type failingCoordinator struct{}
+
+func (failingCoordinator) Acquire(ctx context.Context) (func(), error) {
+	return nil, errors.New("后端不可用")
+}
+
+// TestDrainCoordinatorFallback 测试无法获得许可时仍然执行关闭
+func TestDrainCoordinatorFallback(t *testing.T) {
+	sem := make(semaphore, 1)
+	sem <- struct{}{}
+
+	for _, c := range []DrainCoordinator{sem, failingCoordinator{}} {
+		m := New(WithTimeout(time.Second), WithDrainCoordinator(c, time.Millisecond*20))
+		hooked := false
+		m.OnShutdown(func(ctx context.Context) error {
+			hooked = true
+			return nil
+		})
+
+		start := time.Now()
+		m.Shutdown()
+		if !hooked {
+			t.Error("无法获得许可时仍应执行关闭钩子")
+		}
+		if d := time.Since(start); d > time.Millisecond*200 {
+			t.Errorf("等待许可应受maxWait限制，实际等待了%v", d)
+		}
+	}
+}
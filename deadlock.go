@@ -0,0 +1,195 @@
+package graceful
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EventDeadlockSuspected is emitted when the shutdown timeout expires while
+// the deadlock detector enabled with WithDeadlockDetection has seen managed
+// goroutines blocked without progress.
+const EventDeadlockSuspected EventType = "deadlock_suspected"
+
+// StuckGoroutine is a managed goroutine that stayed blocked in the same place
+// for the whole drain.
+type StuckGoroutine struct {
+	ID    string // Goroutine ID as printed in stack traces
+	State string // Wait reason, such as "chan receive" or "sync.Mutex.Lock"
+	Stack string // Stack trace of the goroutine
+}
+
+// DeadlockError lists the managed goroutines that were blocked on channels or
+// locks with an unchanged stack in every sample taken while draining. Such
+// goroutines are usually waiting on each other, or on something that only
+// stops at shutdown.
+type DeadlockError struct {
+	Goroutines []StuckGoroutine
+}
+
+func (e *DeadlockError) Error() string {
+	return "graceful: " + e.summary()
+}
+
+// summary lists the blocked goroutines with their wait reasons.
+func (e *DeadlockError) summary() string {
+	states := make([]string, len(e.Goroutines))
+	for i, g := range e.Goroutines {
+		states[i] = fmt.Sprintf("goroutine %s [%s]", g.ID, g.State)
+	}
+	return fmt.Sprintf("%d managed goroutines blocked during shutdown: %s", len(e.Goroutines), strings.Join(states, ", "))
+}
+
+// WithDeadlockDetection returns an Option that samples goroutine stacks every
+// interval while shutdown waits for managed goroutines. If the shutdown
+// timeout expires, the managed goroutines that were blocked on channels or
+// locks with the same stack in every sample are logged and reported in an
+// EventDeadlockSuspected event, with their stacks, and attached to the
+// TimeoutError returned by Shutdown and to the ShutdownReport. Sampling stops
+// the world briefly, so pick an interval of a fraction of a second or more.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithDeadlockDetection(500 * time.Millisecond))
+func WithDeadlockDetection(interval time.Duration) Option {
+	return func(m *Manager) {
+		m.deadlockInterval = interval
+	}
+}
+
+// managedFrame identifies the stack frame of goroutines started by Go.
+var managedFrame = funcName((*Manager).Go) + ".func"
+
+// sampledGoroutine is a goroutine as seen in consecutive stack samples.
+type sampledGoroutine struct {
+	StuckGoroutine
+	samples int // Consecutive samples with an unchanged, blocked stack
+}
+
+// detectDeadlocks samples goroutine stacks until the returned function is
+// called, which returns the managed goroutines that stayed blocked.
+func (m *Manager) detectDeadlocks() (stop func() []StuckGoroutine) {
+	if m.deadlockInterval <= 0 {
+		return func() []StuckGoroutine { return nil }
+	}
+
+	seen := make(map[string]*sampledGoroutine)
+	sample := func() {
+		current := blockedGoroutines()
+		for id, g := range current {
+			if prev, ok := seen[id]; ok && prev.Stack == g.Stack {
+				prev.samples++
+				continue
+			}
+			seen[id] = &sampledGoroutine{StuckGoroutine: g, samples: 1}
+		}
+		for id := range seen {
+			if _, ok := current[id]; !ok {
+				delete(seen, id)
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(m.deadlockInterval)
+		defer ticker.Stop()
+		sample()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	}()
+
+	return func() []StuckGoroutine {
+		close(done)
+		<-finished
+		sample()
+		var stuck []StuckGoroutine
+		for _, g := range seen {
+			if g.samples >= 2 {
+				stuck = append(stuck, g.StuckGoroutine)
+			}
+		}
+		// Goroutine IDs are decimal, so shorter IDs sort first
+		sort.Slice(stuck, func(i, j int) bool {
+			a, b := stuck[i].ID, stuck[j].ID
+			if len(a) != len(b) {
+				return len(a) < len(b)
+			}
+			return a < b
+		})
+		return stuck
+	}
+}
+
+// reportDeadlock logs and emits the goroutines found by the detector, and
+// returns them as an error, or nil if there are none.
+func (m *Manager) reportDeadlock(stuck []StuckGoroutine) *DeadlockError {
+	if len(stuck) == 0 {
+		return nil
+	}
+	err := &DeadlockError{Goroutines: stuck}
+	m.logf("%d managed goroutines blocked during shutdown:\n%s", len(stuck), formatStacks(stuck))
+	m.emit(Event{Type: EventDeadlockSuspected, Err: err})
+	return err
+}
+
+// blockedGoroutines returns the managed goroutines that are blocked on a
+// channel or lock, keyed by goroutine ID.
+func blockedGoroutines() map[string]StuckGoroutine {
+	buf := make([]byte, 1<<20)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+
+	blocked := make(map[string]StuckGoroutine)
+	for _, block := range bytes.Split(buf, []byte("\n\n")) {
+		// The last goroutine of the dump keeps the trailing newline
+		header, stack, ok := strings.Cut(strings.TrimRight(string(block), "\n"), "\n")
+		if !ok || !strings.Contains(stack, managedFrame) {
+			continue
+		}
+		// Header looks like "goroutine 18 [chan receive, 2 minutes]:"
+		id, rest, ok := strings.Cut(strings.TrimPrefix(header, "goroutine "), " [")
+		if !ok {
+			continue
+		}
+		state, _, _ := strings.Cut(strings.TrimSuffix(rest, "]:"), ",")
+		if !blockingState(state) {
+			continue
+		}
+		blocked[id] = StuckGoroutine{ID: id, State: state, Stack: stack}
+	}
+	return blocked
+}
+
+// blockingState reports whether a goroutine wait reason means it is blocked
+// on a channel or lock.
+func blockingState(state string) bool {
+	return strings.HasPrefix(state, "chan ") || strings.HasPrefix(state, "select") ||
+		strings.HasPrefix(state, "semacquire") || strings.HasPrefix(state, "sync.")
+}
+
+// formatStacks renders goroutines like a stack dump.
+func formatStacks(gs []StuckGoroutine) string {
+	var b strings.Builder
+	for _, g := range gs {
+		fmt.Fprintf(&b, "goroutine %s [%s]:\n%s\n", g.ID, g.State, g.Stack)
+	}
+	return b.String()
+}
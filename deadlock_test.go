@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDeadlockDetection 测试关闭超时时报告互相阻塞的受管理goroutine
+func TestDeadlockDetection(t *testing.T) {
+	var deadlock *DeadlockError
+	m := New(
+		WithTimeout(time.Millisecond*200),
+		WithDeadlockDetection(time.Millisecond*20),
+		WithEventHandler(func(e Event) {
+			if e.Type == EventDeadlockSuspected {
+				deadlock = e.Err.(*DeadlockError)
+			}
+		}),
+	)
+
+	a, b := make(chan struct{}), make(chan struct{})
+	defer close(a)
+	defer close(b)
+	// 两个任务互相等待对方的通道，且忽略取消
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		<-b
+	})
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		<-a
+	})
+	// 正常退出的任务不应被报告
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+
+	err := m.Shutdown()
+	if deadlock == nil {
+		t.Fatal("应报告疑似死锁")
+	}
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || timeout.Deadlock != deadlock {
+		t.Errorf("超时错误应附带死锁分析，实际为%v", err)
+	} else if !strings.Contains(err.Error(), "2 managed goroutines blocked") {
+		t.Errorf("超时错误信息应包含阻塞的goroutine，实际为%q", err)
+	}
+	if r := m.report(time.Now(), true); len(r.Blocked) != 2 {
+		t.Errorf("关闭报告应包含2个阻塞的goroutine，实际为%d", len(r.Blocked))
+	}
+	if len(deadlock.Goroutines) != 2 {
+		t.Errorf("应报告2个阻塞的goroutine，实际为%d: %v", len(deadlock.Goroutines), deadlock)
+	}
+	for i, g := range deadlock.Goroutines {
+		if g.State != "chan receive" {
+			t.Errorf("阻塞状态应为chan receive，实际为%q", g.State)
+		}
+		if i > 0 {
+			prev, _ := strconv.Atoi(deadlock.Goroutines[i-1].ID)
+			if id, _ := strconv.Atoi(g.ID); id < prev {
+				t.Errorf("阻塞的goroutine应按ID排序，实际为%s在%d之后", g.ID, prev)
+			}
+		}
+	}
+}
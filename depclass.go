@@ -0,0 +1,148 @@
+package graceful
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DependencyClass groups shutdown hooks by the kind of dependency they
+// release, so that each kind gets its own share of the shutdown timeout.
+type DependencyClass string
+
+// Common dependency classes. Any other name can be used as well.
+const (
+	ClassNetwork     DependencyClass = "network"      // Connection pools, service discovery
+	ClassDisk        DependencyClass = "disk"         // Local files, embedded databases
+	ClassExternalAPI DependencyClass = "external-api" // Third-party services
+)
+
+// classLimits holds the budget and concurrency of a dependency class.
+type classLimits struct {
+	budget      time.Duration // Time limit of all hooks of the class; zero means the shutdown timeout
+	concurrency int           // Hooks of the class run at once
+}
+
+// WithClassBudget returns an Option that limits the shutdown hooks of a
+// dependency class to budget in total, running up to concurrency of them at
+// once, so that one slow dependency cannot use up the time other classes
+// need. A concurrency below 1 runs the hooks one at a time. The budget starts
+// when the hooks of the class are reached and is not cut short by the
+// shutdown timeout, so a class still gets its time after goroutines used up
+// the timeout. Hooks that are still running when the budget is used up are
+// abandoned, and hooks not yet started are skipped; both are recorded in the
+// shutdown report.
+//
+// Example:
+//
+//	manager := graceful.New(
+//		graceful.WithClassBudget(graceful.ClassExternalAPI, 3*time.Second, 4),
+//		graceful.WithClassBudget(graceful.ClassDisk, 10*time.Second, 1),
+//	)
+func WithClassBudget(class DependencyClass, budget time.Duration, concurrency int) Option {
+	return func(m *Manager) {
+		if m.classes == nil {
+			m.classes = make(map[DependencyClass]classLimits)
+		}
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		m.classes[class] = classLimits{budget: budget, concurrency: concurrency}
+	}
+}
+
+// OnShutdownClass registers a shutdown hook of the given dependency class.
+// All hooks of a class run together, at the position in the hook order of
+// the first of them, within the budget and concurrency set by
+// WithClassBudget. Hooks of a class without limits behave like hooks
+// registered with OnShutdown.
+//
+// Example:
+//
+//	manager.OnShutdownClass(graceful.ClassExternalAPI, registry.Deregister)
+//	manager.OnShutdownClass(graceful.ClassDisk, wal.Sync)
+func (m *Manager) OnShutdownClass(class DependencyClass, f func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{fn: f, class: class})
+}
+
+// runClass runs the hooks of one dependency class within its limits. Once
+// the budget is used up it stops waiting, like WithHookTimeout does for a
+// single hook: hooks still running are abandoned in the background, hooks
+// not started yet are skipped, and both are recorded as abandoned.
+func (m *Manager) runClass(ctx context.Context, limits classLimits, hooks []hook) {
+	start := time.Now()
+	if limits.budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(detachedContext{ctx}, limits.budget)
+		defer cancel()
+	}
+
+	sem := make(chan struct{}, limits.concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	running := make(map[int]string) // Names of started hooks that have not returned, by index
+	next := 0
+	for ; next < len(hooks); next++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		mu.Lock()
+		running[next] = funcName(hooks[next].fn)
+		mu.Unlock()
+		wg.Add(1)
+		go func(i int, h hook) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_ = m.runHook(ctx, h)
+			mu.Lock()
+			delete(running, i)
+			mu.Unlock()
+		}(next, hooks[next])
+	}
+	if next == len(hooks) && runBounded(ctx, wg.Wait) {
+		return
+	}
+
+	after := time.Since(start).Round(time.Millisecond)
+	mu.Lock()
+	stragglers := make([]int, 0, len(running))
+	for i := range running {
+		stragglers = append(stragglers, i)
+	}
+	sort.Ints(stragglers)
+	for _, i := range stragglers {
+		m.abandonHook(running[i], after)
+	}
+	mu.Unlock()
+	for _, h := range hooks[next:] {
+		m.abandonHook(funcName(h.fn), after)
+	}
+}
+
+// detachedContext keeps the values of a context but not its deadline or
+// cancellation, so that a class budget is not cut short by the shutdown
+// timeout.
+type detachedContext struct{ context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// classBudget returns the budget of a hook for the dry run.
+func (m *Manager) classBudget(h hook) time.Duration {
+	budget := m.timeout
+	if limits, ok := m.classes[h.class]; ok && limits.budget > 0 {
+		budget = limits.budget
+	}
+	if m.hookTimeout > 0 && m.hookTimeout < budget {
+		budget = m.hookTimeout
+	}
+	return budget
+}
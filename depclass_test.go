@@ -0,0 +1,123 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestClassBudget 测试慢的依赖类别不会耗尽其他类别的时间
+func TestClassBudget(t *testing.T) {
+	m := New(
+		WithTimeout(time.Millisecond*300),
+		WithClassBudget(ClassExternalAPI, time.Millisecond*50, 2),
+	)
+
+	// 钩子按注册的逆序运行，磁盘类别在外部API类别之后
+	var diskErr error
+	var diskRemaining time.Duration
+	m.OnShutdownClass(ClassDisk, func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		diskRemaining = time.Until(deadline)
+		diskErr = ctx.Err()
+		return nil
+	})
+
+	var mu sync.Mutex
+	var apiStarted int
+	for i := 0; i < 2; i++ {
+		m.OnShutdownClass(ClassExternalAPI, func(ctx context.Context) error {
+			mu.Lock()
+			apiStarted++
+			mu.Unlock()
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+	start := time.Now()
+	m.Shutdown()
+
+	mu.Lock()
+	if apiStarted != 2 {
+		t.Errorf("外部API类别的钩子应并发运行，实际运行%d个", apiStarted)
+	}
+	mu.Unlock()
+	if elapsed := time.Since(start); elapsed > time.Millisecond*200 {
+		t.Errorf("外部API类别应受自身预算限制，耗时%v", elapsed)
+	}
+	if diskErr != nil || diskRemaining < time.Millisecond*100 {
+		t.Errorf("磁盘类别应保留剩余时间，剩余%v，错误%v", diskRemaining, diskErr)
+	}
+}
+
+// TestClassBudgetAbandonsStragglers 测试忽略上下文的钩子超出类别预算后被放弃，不占用其他类别的时间
+func TestClassBudgetAbandonsStragglers(t *testing.T) {
+	m := New(
+		WithTimeout(time.Second),
+		WithClassBudget(ClassExternalAPI, time.Millisecond*50, 1),
+	)
+
+	diskRan := make(chan time.Time, 1)
+	m.OnShutdownClass(ClassDisk, func(ctx context.Context) error {
+		diskRan <- time.Now()
+		return nil
+	})
+	release := make(chan struct{})
+	defer close(release)
+	skipped := false
+	m.OnShutdownClass(ClassExternalAPI, func(ctx context.Context) error {
+		skipped = true
+		return nil
+	})
+	m.OnShutdownClass(ClassExternalAPI, func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	m.Shutdown()
+
+	select {
+	case ran := <-diskRan:
+		if elapsed := ran.Sub(start); elapsed > time.Millisecond*300 {
+			t.Errorf("磁盘类别应在外部API类别预算用尽后运行，等待了%v", elapsed)
+		}
+	default:
+		t.Fatal("磁盘类别的钩子应运行")
+	}
+	if skipped {
+		t.Error("预算用尽后不应再启动同类别的钩子")
+	}
+	if n := len(m.abandoned()); n != 2 {
+		t.Errorf("应记录2个被放弃的钩子，实际为%d", n)
+	}
+}
+
+// TestClassBudgetAfterTimeout 测试goroutine耗尽关闭超时后，有预算的类别仍在自身预算内运行钩子
+func TestClassBudgetAfterTimeout(t *testing.T) {
+	m := New(
+		WithTimeout(time.Millisecond*50),
+		WithClassBudget(ClassDisk, time.Millisecond*200, 1),
+	)
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+	m.Go(func() { <-stuck })
+
+	var diskErr error
+	diskRan := false
+	m.OnShutdownClass(ClassDisk, func(ctx context.Context) error {
+		diskRan = true
+		diskErr = ctx.Err()
+		return nil
+	})
+	m.Shutdown()
+
+	if !diskRan || diskErr != nil {
+		t.Errorf("关闭超时后磁盘类别的钩子应在自身预算内运行，运行%v，错误%v", diskRan, diskErr)
+	}
+	if n := len(m.abandoned()); n != 0 {
+		t.Errorf("不应放弃钩子，实际放弃%d个", n)
+	}
+}
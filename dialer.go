@@ -0,0 +1,175 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DialRefusedError is returned by Dialer once shutdown has begun. It wraps
+// ErrDraining, so errors.Is(err, graceful.ErrDraining) reports true.
+type DialRefusedError struct {
+	Network string
+	Address string
+}
+
+func (e *DialRefusedError) Error() string {
+	return fmt.Sprintf("graceful: refusing to dial %s %s while draining", e.Network, e.Address)
+}
+
+// Unwrap returns ErrDraining.
+func (e *DialRefusedError) Unwrap() error {
+	return ErrDraining
+}
+
+// Dialer creates outbound connections while the application is running and
+// refuses to once shutdown begins, so that retry loops do not keep
+// re-establishing connections to dependencies during the last seconds of
+// shutdown. Use its DialContext in HTTP transports, database drivers and
+// other clients that accept a dial function.
+type Dialer struct {
+	base      *net.Dialer
+	idleAfter time.Duration
+
+	mu       sync.Mutex
+	draining bool
+	conns    map[*dialedConn]struct{}
+}
+
+// Dialer returns a managed dialer based on base, or on a zero net.Dialer if
+// base is nil. When shutdown begins, it stops dialing and closes the
+// connections that have not been read from or written to for idleAfter;
+// connections still open when the shutdown hooks run are closed then.
+//
+// Example:
+//
+//	dialer := manager.Dialer(&net.Dialer{Timeout: 5 * time.Second}, time.Second)
+//	transport := &http.Transport{DialContext: dialer.DialContext}
+func (m *Manager) Dialer(base *net.Dialer, idleAfter time.Duration) *Dialer {
+	if base == nil {
+		base = &net.Dialer{}
+	}
+	d := &Dialer{base: base, idleAfter: idleAfter, conns: make(map[*dialedConn]struct{})}
+	m.OnDrain(d.drain)
+	m.OnShutdown(d.close)
+	return d
+}
+
+// DialContext connects to the address on the named network, like
+// net.Dialer.DialContext. It returns a *DialRefusedError once shutdown has
+// begun.
+func (d *Dialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	d.mu.Lock()
+	draining := d.draining
+	d.mu.Unlock()
+	if draining {
+		return nil, &DialRefusedError{Network: network, Address: address}
+	}
+
+	conn, err := d.base.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	dc := &dialedConn{Conn: conn, dialer: d}
+	dc.touch()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		// Shutdown began while dialing
+		_ = conn.Close()
+		return nil, &DialRefusedError{Network: network, Address: address}
+	}
+	d.conns[dc] = struct{}{}
+	return dc, nil
+}
+
+// Dial connects to the address on the named network.
+func (d *Dialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// Conns returns the number of open connections created by the dialer.
+func (d *Dialer) Conns() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.conns)
+}
+
+// drain stops dialing and closes idle connections.
+func (d *Dialer) drain(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	var idle []*dialedConn
+	for c := range d.conns {
+		if c.idleFor() >= d.idleAfter {
+			idle = append(idle, c)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, c := range idle {
+		_ = c.Close()
+	}
+	return nil
+}
+
+// close closes every remaining connection.
+func (d *Dialer) close(ctx context.Context) error {
+	d.mu.Lock()
+	conns := make([]*dialedConn, 0, len(d.conns))
+	for c := range d.conns {
+		conns = append(conns, c)
+	}
+	d.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.Close()
+	}
+	return nil
+}
+
+// dialedConn records when it was last used.
+type dialedConn struct {
+	net.Conn
+	dialer *Dialer
+	last   atomic.Int64 // Unix nanoseconds of the last read or write
+	once   sync.Once
+}
+
+// Read implements net.Conn.
+func (c *dialedConn) Read(b []byte) (int, error) {
+	c.touch()
+	defer c.touch()
+	return c.Conn.Read(b)
+}
+
+// Write implements net.Conn.
+func (c *dialedConn) Write(b []byte) (int, error) {
+	c.touch()
+	defer c.touch()
+	return c.Conn.Write(b)
+}
+
+// Close closes the connection and forgets it.
+func (c *dialedConn) Close() error {
+	c.once.Do(func() {
+		c.dialer.mu.Lock()
+		delete(c.dialer.conns, c)
+		c.dialer.mu.Unlock()
+	})
+	return c.Conn.Close()
+}
+
+// touch records activity on the connection.
+func (c *dialedConn) touch() {
+	c.last.Store(time.Now().UnixNano())
+}
+
+// idleFor returns the time since the connection was last used.
+func (c *dialedConn) idleFor() time.Duration {
+	return time.Since(time.Unix(0, c.last.Load()))
+}
@@ -0,0 +1,66 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestDialer 测试排空开始后拒绝新连接并关闭空闲连接
+func TestDialer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	m := New(WithTimeout(time.Second))
+	d := m.Dialer(nil, time.Millisecond*50)
+
+	idle, err := d.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	time.Sleep(time.Millisecond * 60)
+	busy, err := d.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	if d.Conns() != 2 {
+		t.Errorf("应有2个连接，实际为%d", d.Conns())
+	}
+
+	var busyOpenDuringDrain bool
+	m.OnDrain(func(ctx context.Context) error {
+		busyOpenDuringDrain = d.Conns() == 1
+		return nil
+	})
+	m.Shutdown()
+
+	if !busyOpenDuringDrain {
+		t.Error("排空时应只关闭空闲连接")
+	}
+	if _, err := idle.Write([]byte("x")); err == nil {
+		t.Error("空闲连接应已关闭")
+	}
+	if _, err := busy.Write([]byte("x")); err == nil {
+		t.Error("关闭钩子运行后所有连接应已关闭")
+	}
+
+	_, err = d.Dial("tcp", ln.Addr().String())
+	var refused *DialRefusedError
+	if !errors.As(err, &refused) || !errors.Is(err, ErrDraining) {
+		t.Errorf("排空后应返回DialRefusedError，实际为%v", err)
+	}
+}
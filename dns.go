@@ -0,0 +1,106 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// HostResolver looks up the addresses of a host. *net.Resolver satisfies it.
+type HostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// DNSDeregistrationDelay makes shutdown wait multiple times ttl before it
+// stops intake, for environments that route to instances through DNS rather
+// than a load balancer with health checks: clients keep resolving the
+// instance until cached records expire, so it keeps serving in the meantime.
+// The wait happens first thing in shutdown, after readiness turns false and
+// while listeners are still open, and is bounded by the shutdown timeout.
+//
+// Example:
+//
+//	// Remove the record from DNS, then give resolvers two TTLs to notice
+//	manager.OnDrain(dns.RemoveSelf)
+//	manager.DNSDeregistrationDelay(30*time.Second, 2)
+func (m *Manager) DNSDeregistrationDelay(ttl time.Duration, multiple float64) {
+	delay := time.Duration(float64(ttl) * multiple)
+	m.addDeregistration(func(ctx context.Context) error {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	})
+}
+
+// AwaitDNSRemoval makes shutdown poll host every interval before it stops
+// intake, until ip no longer appears among its addresses or the host no
+// longer exists, like DNSDeregistrationDelay but finishing as soon as the
+// record is gone. A nil resolver uses net.DefaultResolver. Lookup failures
+// other than a missing host are retried.
+//
+// Example:
+//
+//	manager.AwaitDNSRemoval(nil, "api.internal.example.com", podIP, time.Second)
+func (m *Manager) AwaitDNSRemoval(resolver HostResolver, host string, ip net.IP, interval time.Duration) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	m.addDeregistration(func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if removed(ctx, resolver, host, ip) {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
+// removed reports whether ip is no longer among the addresses of host.
+func removed(ctx context.Context, resolver HostResolver, host string, ip net.IP) bool {
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return true
+	}
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if addr.IP.Equal(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// addDeregistration registers a function run at the start of shutdown.
+func (m *Manager) addDeregistration(f func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deregistrations = append(m.deregistrations, f)
+}
+
+// awaitDeregistration runs the deregistration delays in order and clears
+// them.
+func (m *Manager) awaitDeregistration(ctx context.Context) {
+	m.mu.Lock()
+	deregistrations := m.deregistrations
+	m.deregistrations = nil
+	m.mu.Unlock()
+
+	for _, f := range deregistrations {
+		_ = f(ctx)
+	}
+}
@@ -0,0 +1,72 @@
+package graceful
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeResolver 模拟DNS记录，在指定次数查询后移除实例地址
+type fakeResolver struct {
+	mu      sync.Mutex
+	lookups int
+	until   int
+}
+
+func (r *fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	addrs := []net.IPAddr{{IP: net.ParseIP("10.0.0.2")}}
+	if r.lookups < r.until {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP("10.0.0.1")})
+	}
+	return addrs, nil
+}
+
+// TestDNSDeregistrationDelay 测试关闭在停止接收前等待TTL的倍数
+func TestDNSDeregistrationDelay(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.DNSDeregistrationDelay(time.Millisecond*40, 1.5)
+
+	var canceledAfter atomic.Int64
+	start := time.Now()
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		canceledAfter.Store(int64(time.Since(start)))
+	})
+	m.Shutdown()
+
+	if d := time.Duration(canceledAfter.Load()); d < time.Millisecond*60 {
+		t.Errorf("工作协程应在延迟结束后才被取消，实际为%v", d)
+	}
+}
+
+// TestDNSDeregistrationDelayTimeout 测试延迟受关闭超时限制
+func TestDNSDeregistrationDelayTimeout(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond * 50))
+	m.DNSDeregistrationDelay(time.Hour, 2)
+
+	start := time.Now()
+	m.Shutdown()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("延迟应受关闭超时限制，耗时%v", elapsed)
+	}
+}
+
+// TestAwaitDNSRemoval 测试关闭轮询DNS直到实例地址消失
+func TestAwaitDNSRemoval(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	r := &fakeResolver{until: 3}
+	m.AwaitDNSRemoval(r, "api.example.com", net.ParseIP("10.0.0.1"), time.Millisecond*10)
+	m.Shutdown()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lookups != 3 {
+		t.Errorf("应查询3次直到地址消失，实际为%d", r.lookups)
+	}
+}
@@ -0,0 +1,45 @@
+package graceful
+
+import (
+	"runtime"
+)
+
+// WithDrainPriority returns an Option that runs the shutdown sequence on a
+// goroutine locked to its own OS thread and, where the platform allows it,
+// sets that thread's nice value to nice, so that a CPU-saturated process
+// still makes progress through its drain instead of being starved by the work
+// it is trying to stop. Negative values raise the priority and usually need
+// CAP_SYS_NICE; if the priority cannot be changed, the failure is logged and
+// the drain runs on the locked thread regardless. Thread priorities are only
+// supported on Linux.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithDrainPriority(-10))
+func WithDrainPriority(nice int) Option {
+	return func(m *Manager) {
+		m.drainNice = &nice
+	}
+}
+
+// runPrioritized calls f, on a dedicated high-priority thread if
+// WithDrainPriority was given.
+func (m *Manager) runPrioritized(f func()) {
+	if m.drainNice == nil {
+		f()
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// The thread is discarded when the goroutine exits while locked, so
+		// its priority does not leak to other goroutines
+		runtime.LockOSThread()
+		if err := setThreadPriority(*m.drainNice); err != nil {
+			m.logf("raising drain priority failed: %v", err)
+		}
+		f()
+	}()
+	<-done
+}
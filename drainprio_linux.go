@@ -0,0 +1,12 @@
+//go:build linux
+
+package graceful
+
+import (
+	"syscall"
+)
+
+// setThreadPriority sets the nice value of the calling thread.
+func setThreadPriority(nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, syscall.Gettid(), nice)
+}
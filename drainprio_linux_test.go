@@ -0,0 +1,31 @@
+//go:build linux
+
+package graceful
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestDrainPriority 测试关闭流程在设置了优先级的线程上运行
+func TestDrainPriority(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithDrainPriority(5))
+
+	var prio int
+	var err error
+	m.OnShutdown(func(ctx context.Context) error {
+		// 内核返回20减去nice值
+		prio, err = syscall.Getpriority(syscall.PRIO_PROCESS, syscall.Gettid())
+		return nil
+	})
+	m.Shutdown()
+
+	if err != nil {
+		t.Fatalf("读取优先级失败: %v", err)
+	}
+	if prio != 15 {
+		t.Errorf("关闭线程的nice值应为5，实际为%d", 20-prio)
+	}
+}
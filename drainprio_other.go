@@ -0,0 +1,13 @@
+//go:build !linux
+
+package graceful
+
+import (
+	"errors"
+	"runtime"
+)
+
+// setThreadPriority reports that thread priorities are not supported.
+func setThreadPriority(nice int) error {
+	return errors.New("thread priorities are not supported on " + runtime.GOOS)
+}
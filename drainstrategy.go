@@ -0,0 +1,154 @@
+package graceful
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// DrainStrategy decides how running tasks are stopped at shutdown. Drain
+// receives the tasks started with CtxGo that are still running, in start
+// order, and a context that expires with the shutdown timeout. Tasks it
+// leaves running, and goroutines started with Go, are canceled together once
+// it returns.
+//
+// Implement it for ordering requirements the built-in strategies do not
+// cover; DrainStrategyFunc adapts a plain function.
+type DrainStrategy interface {
+	Drain(ctx context.Context, tasks []*Task)
+}
+
+// DrainStrategyFunc adapts a function to the DrainStrategy interface.
+type DrainStrategyFunc func(ctx context.Context, tasks []*Task)
+
+// Drain calls f(ctx, tasks).
+func (f DrainStrategyFunc) Drain(ctx context.Context, tasks []*Task) {
+	f(ctx, tasks)
+}
+
+// WithDrainStrategy returns an Option that sets how tasks are stopped at
+// shutdown. Without one, all goroutines are canceled at once, which is what
+// DrainSimultaneous does as well.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithDrainStrategy(graceful.DrainReverse()))
+func WithDrainStrategy(s DrainStrategy) Option {
+	return func(m *Manager) {
+		m.drainStrategy = s
+	}
+}
+
+// DrainSimultaneous returns a strategy that cancels every task at once and
+// waits for them to return.
+func DrainSimultaneous() DrainStrategy {
+	return DrainStrategyFunc(func(ctx context.Context, tasks []*Task) {
+		for _, t := range tasks {
+			t.Cancel(nil)
+		}
+		for _, t := range tasks {
+			if !waitTask(ctx, t, 0) {
+				return
+			}
+		}
+	})
+}
+
+// DrainReverse returns a strategy that stops tasks one at a time in reverse
+// start order, waiting for each to return before canceling the next, so that
+// tasks started later, which may depend on earlier ones, stop first.
+func DrainReverse() DrainStrategy {
+	return DrainWithTaskBudget(0)
+}
+
+// DrainWithTaskBudget returns a strategy that stops tasks one at a time in
+// reverse start order, giving each at most budget to return before moving on
+// to the next, so that one slow task cannot use up the whole shutdown
+// timeout. A budget of zero or less waits for each task until the shutdown
+// timeout expires.
+func DrainWithTaskBudget(budget time.Duration) DrainStrategy {
+	return DrainStrategyFunc(func(ctx context.Context, tasks []*Task) {
+		for i := len(tasks) - 1; i >= 0; i-- {
+			tasks[i].Cancel(nil)
+			if !waitTask(ctx, tasks[i], budget) {
+				return
+			}
+		}
+	})
+}
+
+// DrainPhased returns a strategy that stops tasks in phases: tasks for which
+// phase returns the lowest number are canceled together first, and the next
+// phase starts once they have all returned.
+//
+// Example:
+//
+//	// Stop ingestion before the workers that process its output
+//	graceful.DrainPhased(func(t *graceful.Task) int {
+//		if strings.HasPrefix(t.Name(), "ingest") {
+//			return 0
+//		}
+//		return 1
+//	})
+func DrainPhased(phase func(t *Task) int) DrainStrategy {
+	return DrainStrategyFunc(func(ctx context.Context, tasks []*Task) {
+		groups := make(map[int][]*Task)
+		var order []int
+		for _, t := range tasks {
+			p := phase(t)
+			if _, ok := groups[p]; !ok {
+				order = append(order, p)
+			}
+			groups[p] = append(groups[p], t)
+		}
+		sort.Ints(order)
+		for _, p := range order {
+			DrainSimultaneous().Drain(ctx, groups[p])
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	})
+}
+
+// waitTask waits for t to return, for at most budget if it is positive. It
+// returns false if ctx expired first.
+func waitTask(ctx context.Context, t *Task, budget time.Duration) bool {
+	var timeout <-chan time.Time
+	if budget > 0 {
+		timer := time.NewTimer(budget)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-t.Done():
+	case <-timeout:
+	case <-ctx.Done():
+		return false
+	}
+	return true
+}
+
+// liveTasks returns the running tasks in start order.
+func (m *Manager) liveTasks() []*Task {
+	m.mu.Lock()
+	tasks := make([]*Task, 0, len(m.live))
+	for t := range m.live {
+		tasks = append(tasks, t)
+	}
+	m.mu.Unlock()
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].seq < tasks[j].seq })
+	return tasks
+}
+
+// namedLiveTasks returns the names of the named tasks still running, in the
+// order they were started.
+func (m *Manager) namedLiveTasks() []string {
+	var names []string
+	for _, t := range m.liveTasks() {
+		if t.name != "" {
+			names = append(names, t.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,80 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// startRecorded 启动n个任务，每个任务退出时记录自己的名称
+func startRecorded(m *Manager, n int) func() string {
+	var mu sync.Mutex
+	var stopped []string
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("task%d", i)
+		m.CtxGo(func(ctx context.Context) {
+			<-ctx.Done()
+			mu.Lock()
+			stopped = append(stopped, name)
+			mu.Unlock()
+		}, WithName(name))
+	}
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return strings.Join(stopped, " ")
+	}
+}
+
+// TestDrainReverse 测试按启动顺序的逆序逐个停止任务
+func TestDrainReverse(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithDrainStrategy(DrainReverse()))
+	stopped := startRecorded(m, 3)
+
+	m.Shutdown()
+	if got := stopped(); got != "task2 task1 task0" {
+		t.Errorf("停止顺序应为逆序，实际为%q", got)
+	}
+}
+
+// TestDrainPhased 测试分阶段停止任务
+func TestDrainPhased(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithDrainStrategy(DrainPhased(func(t *Task) int {
+		if t.Name() == "task1" {
+			return 0
+		}
+		return 1
+	})))
+	stopped := startRecorded(m, 3)
+
+	m.Shutdown()
+	if got := stopped(); !strings.HasPrefix(got, "task1 ") {
+		t.Errorf("task1应在第一阶段停止，实际顺序为%q", got)
+	}
+}
+
+// TestDrainWithTaskBudget 测试单个任务超出预算时继续停止下一个任务
+func TestDrainWithTaskBudget(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithDrainStrategy(DrainWithTaskBudget(time.Millisecond*50)))
+
+	stopped := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+	m.CtxGo(func(ctx context.Context) {
+		// 忽略取消，直到第一个任务停止
+		<-stopped
+	})
+
+	begin := time.Now()
+	if m.waitForGoroutines() {
+		t.Fatal("不应超时")
+	}
+	if elapsed := time.Since(begin); elapsed < time.Millisecond*50 || elapsed > time.Millisecond*500 {
+		t.Errorf("应在约50ms后继续停止下一个任务，实际耗时%v", elapsed)
+	}
+}
@@ -0,0 +1,98 @@
+package graceful
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// PlanStep is one step of a shutdown plan returned by DryRunShutdown.
+type PlanStep struct {
+	Phase  string        // "coordinate", "deregister", "drain", "handoff", "phase", "strategy", "cancel", "children", "write-behind", "hooks", "cleanup", "flush" or "telemetry"
+	Name   string        // Name of the function run, or a description of the step
+	Budget time.Duration // Budget of the phase; steps of one phase share it
+}
+
+// ShutdownPlan lists the steps a shutdown would take, in order.
+type ShutdownPlan struct {
+	Steps []PlanStep
+}
+
+// DryRunShutdown returns the exact order and budgets a shutdown would use
+// with the current configuration, without running or canceling anything.
+// Use it to validate lifecycle configuration in staging and in tests.
+//
+// Example:
+//
+//	fmt.Print(manager.DryRunShutdown())
+func (m *Manager) DryRunShutdown() ShutdownPlan {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var steps []PlanStep
+	if m.coordinator != nil {
+		steps = append(steps, PlanStep{Phase: "coordinate", Name: "acquire drain slot", Budget: m.coordinatorWait})
+	}
+	for _, f := range m.deregistrations {
+		steps = append(steps, PlanStep{Phase: "deregister", Name: funcName(f), Budget: m.timeout})
+	}
+	for _, f := range m.drainers {
+		steps = append(steps, PlanStep{Phase: "drain", Name: funcName(f), Budget: m.timeout})
+	}
+	for _, f := range m.handoffs {
+		steps = append(steps, PlanStep{Phase: "handoff", Name: funcName(f), Budget: m.handoffWindow()})
+	}
+	steps = append(steps, m.phaseSteps()...)
+	if m.drainStrategy != nil {
+		steps = append(steps, PlanStep{
+			Phase:  "strategy",
+			Name:   fmt.Sprintf("stop %d tasks with %T", len(m.live), m.drainStrategy),
+			Budget: m.timeout,
+		})
+	}
+	steps = append(steps, PlanStep{
+		Phase:  "cancel",
+		Name:   fmt.Sprintf("cancel and wait for %d tasks", m.started),
+		Budget: m.timeout,
+	})
+	for _, child := range m.children {
+		steps = append(steps, PlanStep{Phase: "children", Name: "shut down child manager " + child.name, Budget: child.timeout})
+	}
+	steps = append(steps, m.writeBehindSteps()...)
+	for _, h := range orderHooks(m.hooks) {
+		steps = append(steps, PlanStep{Phase: "hooks", Name: funcName(h.fn), Budget: m.classBudget(h)})
+	}
+	if len(m.tempPaths) > 0 {
+		steps = append(steps, PlanStep{Phase: "cleanup", Name: fmt.Sprintf("remove %d temporary paths", len(m.tempPaths))})
+	}
+	for _, f := range m.flushers {
+		steps = append(steps, PlanStep{Phase: "flush", Name: funcName(f), Budget: m.flushTimeout})
+	}
+	for _, p := range m.telemetry {
+		steps = append(steps, PlanStep{Phase: "telemetry", Name: fmt.Sprintf("%T", p), Budget: m.telemetryTimeout})
+	}
+	return ShutdownPlan{Steps: steps}
+}
+
+// String formats the plan with one step per line.
+func (p ShutdownPlan) String() string {
+	var b strings.Builder
+	for i, s := range p.Steps {
+		fmt.Fprintf(&b, "%d. %-10s %s", i+1, s.Phase, s.Name)
+		if s.Budget > 0 {
+			fmt.Fprintf(&b, " (budget %v)", s.Budget)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// funcName returns the name of the function f, such as "main.main.func1".
+func funcName(f any) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "unknown"
+}
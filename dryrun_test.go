@@ -0,0 +1,48 @@
+package graceful
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDryRunShutdown 测试演练关闭按真实顺序列出步骤且不执行任何函数
+func TestDryRunShutdown(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithFlushTimeout(time.Millisecond*200))
+
+	ran := false
+	run := func(ctx context.Context) error {
+		ran = true
+		return nil
+	}
+	m.OnFlush(run)
+	m.OnShutdown(run)
+	m.OnShutdownPriority(10, run)
+	m.OnDrain(run)
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+	defer m.Shutdown()
+
+	plan := m.DryRunShutdown()
+	if ran {
+		t.Fatal("演练关闭不应执行任何函数")
+	}
+
+	var phases []string
+	for _, s := range plan.Steps {
+		phases = append(phases, s.Phase)
+	}
+	want := "drain cancel hooks hooks flush"
+	if got := strings.Join(phases, " "); got != want {
+		t.Errorf("阶段顺序应为%q，实际为%q", want, got)
+	}
+	if plan.Steps[len(plan.Steps)-1].Budget != time.Millisecond*200 {
+		t.Errorf("刷新阶段预算应为200ms，实际为%v", plan.Steps[len(plan.Steps)-1].Budget)
+	}
+	if m.Context().Err() != nil {
+		t.Error("演练关闭不应取消上下文")
+	}
+	if !strings.Contains(plan.String(), "TestDryRunShutdown") {
+		t.Errorf("计划应包含函数名称，实际为: %s", plan)
+	}
+}
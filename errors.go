@@ -0,0 +1,69 @@
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrTimeout is returned by Shutdown, Wait and Stop, wrapped in a
+	// *TimeoutError, when managed goroutines were still running when the
+	// shutdown timeout expired.
+	ErrTimeout = errors.New("graceful: shutdown timed out")
+
+	// ErrAlreadyShutdown is returned by Shutdown and Wait when the manager has
+	// already been shut down by an earlier call.
+	ErrAlreadyShutdown = errors.New("graceful: already shut down")
+
+	// ErrStartupFailed is wrapped by the errors Start and Wait return when a
+	// start hook, a startup check or a warm-up task failed, together with the
+	// error that caused the failure.
+	ErrStartupFailed = errors.New("graceful: startup failed")
+)
+
+// TaskError reports that a managed task failed. Unwrap returns the error the
+// task returned.
+type TaskError struct {
+	Name string // Task name, or function name for unnamed tasks
+	Err  error  // Error returned by the task
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("graceful: task %s failed: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the error returned by the task.
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError reports that the shutdown timeout expired while managed
+// goroutines were still running. It wraps ErrTimeout, so errors.Is(err,
+// ErrTimeout) reports whether a shutdown timed out.
+type TimeoutError struct {
+	Stuck    int            // Managed goroutines still running when the timeout expired
+	Tasks    []string       // Names of the named tasks among them
+	Deadlock *DeadlockError // Goroutines found blocked by WithDeadlockDetection, if any
+}
+
+func (e *TimeoutError) Error() string {
+	s := fmt.Sprintf("%v with %d goroutines still running", ErrTimeout, e.Stuck)
+	if len(e.Tasks) > 0 {
+		s += fmt.Sprintf(" (tasks: %s)", strings.Join(e.Tasks, ", "))
+	}
+	if e.Deadlock != nil {
+		s += "; " + e.Deadlock.summary()
+	}
+	return s
+}
+
+// Unwrap returns ErrTimeout.
+func (e *TimeoutError) Unwrap() error {
+	return ErrTimeout
+}
+
+// startupFailed wraps err with ErrStartupFailed.
+func startupFailed(err error) error {
+	return fmt.Errorf("%w: %w", ErrStartupFailed, err)
+}
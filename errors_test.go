@@ -0,0 +1,90 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestShutdownErrors 测试Shutdown返回的错误
+func TestShutdownErrors(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond * 50))
+	stuck := make(chan struct{})
+	defer close(stuck)
+	m.Go(func() { <-stuck })
+
+	if err := m.Shutdown(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("超时时应返回ErrTimeout，实际为%v", err)
+	}
+	if err := m.Shutdown(); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("重复关闭应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+	if err := m.Stop(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Stop应报告关闭超时，实际为%v", err)
+	}
+}
+
+// TestShutdownUnblocksWait 测试Shutdown使阻塞的Wait返回
+func TestShutdownUnblocksWait(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.Go(func() {})
+
+	waited := make(chan error, 1)
+	go func() { waited <- m.Wait() }()
+	time.Sleep(time.Millisecond * 20)
+
+	if err := m.Shutdown(); err != nil {
+		t.Fatalf("关闭失败: %v", err)
+	}
+	select {
+	case err := <-waited:
+		if !errors.Is(err, ErrAlreadyShutdown) {
+			t.Errorf("Wait应返回ErrAlreadyShutdown，实际为%v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown后Wait应返回")
+	}
+}
+
+// TestWaitStartupFailed 测试预热任务失败时Wait返回的错误
+func TestWaitStartupFailed(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	cause := errors.New("预热失败")
+	m.GoWarmup(func(ctx context.Context) error { return cause })
+
+	err := m.Wait()
+	if !errors.Is(err, ErrStartupFailed) || !errors.Is(err, cause) {
+		t.Errorf("应返回包装ErrStartupFailed的错误，实际为%v", err)
+	}
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.Name == "" {
+		t.Errorf("应包含TaskError，实际为%v", err)
+	}
+}
+
+// TestShutdownTimeoutError 测试超时错误报告仍在运行的goroutine数量
+func TestShutdownTimeoutError(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond * 50))
+	stuck := make(chan struct{})
+	defer close(stuck)
+	m.Go(func() { <-stuck })
+	m.CtxGo(func(ctx context.Context) { <-stuck }, WithName("indexer"))
+	m.Go(func() {})
+
+	err := m.Shutdown()
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("超时时应返回*TimeoutError，实际为%v", err)
+	}
+	if timeoutErr.Stuck != 2 {
+		t.Errorf("应报告2个未退出的goroutine，实际为%d", timeoutErr.Stuck)
+	}
+	if len(timeoutErr.Tasks) != 1 || timeoutErr.Tasks[0] != "indexer" {
+		t.Errorf("应报告未退出的命名任务，实际为%v", timeoutErr.Tasks)
+	}
+	if !strings.Contains(err.Error(), "2 goroutines still running") {
+		t.Errorf("错误信息应包含未退出的数量，实际为%q", err.Error())
+	}
+}
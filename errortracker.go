@@ -0,0 +1,74 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFlushIncomplete is returned by flush adapters when the underlying client
+// could not deliver all pending events within the flush budget.
+var ErrFlushIncomplete = errors.New("graceful: flush did not complete in time")
+
+// FlushWithTimeout adapts a flush function that takes a timeout and reports
+// whether all events were delivered, such as sentry.Flush, (*sentry.Hub).Flush
+// or (*sentry.Client).Flush. The timeout passed to flush is the time remaining
+// until the context deadline, so the client never outlives the flush budget.
+//
+// Example:
+//
+//	manager.OnFlush(graceful.FlushWithTimeout(sentry.Flush))
+func FlushWithTimeout(flush func(timeout time.Duration) bool) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		timeout := time.Duration(0)
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+		if timeout <= 0 {
+			return ErrFlushIncomplete
+		}
+		if !flush(timeout) {
+			return ErrFlushIncomplete
+		}
+		return nil
+	}
+}
+
+// FlushBlocking adapts a flush function that blocks until all events have been
+// delivered, such as rollbar.Wait or rollbar.Close. If the context expires
+// first, the adapter stops waiting and returns ErrFlushIncomplete; the flush
+// function keeps running in the background until the process exits.
+//
+// Error trackers that deliver asynchronously without a flush call, such as
+// Bugsnag, should be configured for synchronous delivery during shutdown
+// instead.
+//
+// Example:
+//
+//	manager.OnFlush(graceful.FlushBlocking(rollbar.Wait))
+func FlushBlocking(wait func()) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if !runBounded(ctx, wait) {
+			return ErrFlushIncomplete
+		}
+		return nil
+	}
+}
+
+// runBounded calls f in a new goroutine and waits for it to return or for ctx
+// to be done, whichever happens first. It reports whether f returned. If ctx
+// wins, f is left running in the background.
+func runBounded(ctx context.Context, f func()) bool {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
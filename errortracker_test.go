@@ -0,0 +1,56 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestFlushWithTimeout 测试带超时参数的flush适配器
+func TestFlushWithTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	var got time.Duration
+	err := FlushWithTimeout(func(timeout time.Duration) bool {
+		got = timeout
+		return true
+	})(ctx)
+	if err != nil {
+		t.Errorf("flush成功时不应返回错误，实际为%v", err)
+	}
+	if got <= 0 || got > time.Millisecond*100 {
+		t.Errorf("传入的超时时间应为剩余预算，实际为%v", got)
+	}
+
+	err = FlushWithTimeout(func(timeout time.Duration) bool {
+		return false
+	})(ctx)
+	if !errors.Is(err, ErrFlushIncomplete) {
+		t.Errorf("flush未完成时应返回ErrFlushIncomplete，实际为%v", err)
+	}
+}
+
+// TestFlushBlocking 测试阻塞式flush适配器在超时后放弃等待
+func TestFlushBlocking(t *testing.T) {
+	err := FlushBlocking(func() {})(context.Background())
+	if err != nil {
+		t.Errorf("flush完成时不应返回错误，实际为%v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	start := time.Now()
+	err = FlushBlocking(func() { <-release })(ctx)
+	if !errors.Is(err, ErrFlushIncomplete) {
+		t.Errorf("flush超时时应返回ErrFlushIncomplete，实际为%v", err)
+	}
+	if time.Since(start) > time.Millisecond*200 {
+		t.Errorf("flush超时后应立即返回，实际等待了%v", time.Since(start))
+	}
+}
@@ -0,0 +1,68 @@
+package graceful
+
+import (
+	"os"
+	"time"
+)
+
+// EventType identifies a lifecycle event emitted by the manager.
+type EventType string
+
+const (
+	// EventPaused is emitted when the manager pauses periodic tasks and intake.
+	EventPaused EventType = "paused"
+	// EventResumed is emitted when the manager resumes after a pause.
+	EventResumed EventType = "resumed"
+
+	// EventCanceled is emitted during shutdown once the contexts of managed
+	// goroutines have been canceled, before the manager waits for the
+	// goroutines to exit.
+	EventCanceled EventType = "canceled"
+	// EventGoroutinesExited is emitted once managed goroutines have exited,
+	// or the timeout expired with Err set to ErrTimeout, before shutdown
+	// hooks run.
+	EventGoroutinesExited EventType = "goroutines_exited"
+)
+
+// Event describes something that happened during the manager's lifecycle.
+// Fields that do not apply to an event type are left at their zero value.
+type Event struct {
+	Type   EventType // What happened
+	Time   time.Time // When it happened
+	Signal os.Signal // Signal that caused the event, if any
+	Err    error     // Error associated with the event, if any
+
+	Summary   *Summary         // Startup summary, for EventStarted
+	Rehearsal *RehearsalReport // Rehearsal report, for EventRehearsed
+	Frozen    time.Duration    // Time the process was frozen, for EventThawed
+	Child     *ChildExit       // Exit status of the reaped child, for EventChildExited
+}
+
+// WithEventHandler returns an Option that sets a function to receive lifecycle
+// events. The handler is called synchronously from the goroutine that caused
+// the event, so it should return quickly. Tests can rely on this to
+// interleave assertions with the shutdown sequence deterministically: a
+// handler that blocks on EventCanceled or EventGoroutinesExited holds
+// shutdown at that point until it returns.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithEventHandler(func(e graceful.Event) {
+//		log.Printf("lifecycle event: %s", e.Type)
+//	}))
+func WithEventHandler(handler func(Event)) Option {
+	return func(m *Manager) {
+		m.eventHandler = handler
+	}
+}
+
+// emit delivers an event to the configured handler, if any.
+func (m *Manager) emit(e Event) {
+	if m.eventHandler == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	m.eventHandler(e)
+}
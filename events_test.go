@@ -0,0 +1,68 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestShutdownSyncPoints 测试通过事件在关闭序列的固定位置插入断言
+func TestShutdownSyncPoints(t *testing.T) {
+	var exited, hookRan atomic.Bool
+	var seen []EventType
+	m := New(WithTimeout(time.Second), WithEventHandler(func(e Event) {
+		switch e.Type {
+		case EventCanceled:
+			if hookRan.Load() {
+				t.Error("取消后、等待前关闭钩子不应已运行")
+			}
+		case EventGoroutinesExited:
+			if e.Err != nil || !exited.Load() {
+				t.Errorf("等待结束时goroutine应已退出，错误为%v", e.Err)
+			}
+			if hookRan.Load() {
+				t.Error("等待结束后、钩子运行前关闭钩子不应已运行")
+			}
+		default:
+			return
+		}
+		seen = append(seen, e.Type)
+	}))
+
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		exited.Store(true)
+	})
+	m.OnShutdown(func(ctx context.Context) error {
+		hookRan.Store(true)
+		return nil
+	})
+	m.Shutdown()
+
+	if len(seen) != 2 || seen[0] != EventCanceled || seen[1] != EventGoroutinesExited {
+		t.Errorf("事件顺序应为[canceled goroutines_exited]，实际为%v", seen)
+	}
+	if !hookRan.Load() {
+		t.Error("关闭钩子应已运行")
+	}
+}
+
+// TestShutdownSyncPointsTimeout 测试超时时事件携带ErrTimeout
+func TestShutdownSyncPointsTimeout(t *testing.T) {
+	var exitedErr error
+	m := New(WithTimeout(time.Millisecond*50), WithEventHandler(func(e Event) {
+		if e.Type == EventGoroutinesExited {
+			exitedErr = e.Err
+		}
+	}))
+	stuck := make(chan struct{})
+	defer close(stuck)
+	m.Go(func() { <-stuck })
+
+	m.Shutdown()
+	if !errors.Is(exitedErr, ErrTimeout) {
+		t.Errorf("超时时事件应携带ErrTimeout，实际为%v", exitedErr)
+	}
+}
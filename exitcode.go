@@ -0,0 +1,180 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// exit terminates the process. It is a variable so tests can intercept it.
+var exit = os.Exit
+
+// ExitCodes maps the outcome of a run to the process exit code used by Run.
+// When several outcomes apply, the most severe one wins, in this order:
+// StartupFailure, Timeout, the reason codes, TaskError, then the signal codes,
+// then Clean.
+type ExitCodes struct {
+	Clean          int // All goroutines exited within the timeout
+	Timeout        int // The shutdown timeout expired with goroutines still running
+	TaskError      int // A managed task reported an error
+	StartupFailure int // A start function passed to Run returned an error
+
+	// SignalOffset, when non-zero, makes an otherwise clean shutdown that was
+	// triggered by signal N exit with SignalOffset+N. Use 128 to follow the
+	// shell convention (SIGTERM exits with 143).
+	SignalOffset int
+
+	// Signals overrides the code for clean shutdowns triggered by specific
+	// signals. It takes precedence over SignalOffset.
+	Signals map[os.Signal]int
+
+	// Reasons sets the code for shutdowns started with ShutdownWithReason,
+	// by reason. Reasons not listed exit with TaskError if they carry an
+	// error, and with Clean otherwise.
+	Reasons map[string]int
+}
+
+// DefaultExitCodes returns the exit code policy used when WithExitCodes is not
+// given: 0 for a clean shutdown (including signal-triggered ones), 1 for task
+// errors, 2 for a timed-out shutdown and 3 for a startup failure.
+func DefaultExitCodes() ExitCodes {
+	return ExitCodes{
+		Clean:          0,
+		TaskError:      1,
+		Timeout:        2,
+		StartupFailure: 3,
+	}
+}
+
+// WithExitCodes returns an Option that sets the exit code policy used by Run.
+//
+// Example:
+//
+//	codes := graceful.DefaultExitCodes()
+//	codes.SignalOffset = 128
+//	manager := graceful.New(graceful.WithExitCodes(codes))
+func WithExitCodes(codes ExitCodes) Option {
+	return func(m *Manager) {
+		m.exitCodes = codes
+	}
+}
+
+// outcome summarizes how a run ended.
+type outcome struct {
+	signal         os.Signal       // Signal that triggered shutdown, if any
+	timedOut       bool            // Shutdown timeout expired
+	taskError      bool            // A task reported an error
+	startupFailure bool            // A start function failed
+	reason         *ShutdownReason // Reason given to ShutdownWithReason, if any
+}
+
+// code returns the exit code for the outcome according to the policy.
+func (c ExitCodes) code(o outcome) int {
+	switch {
+	case o.startupFailure:
+		return c.StartupFailure
+	case o.timedOut:
+		return c.Timeout
+	}
+
+	if o.reason != nil {
+		if code, ok := c.Reasons[o.reason.Reason]; ok {
+			return code
+		}
+		if o.reason.Err != nil {
+			return c.TaskError
+		}
+	}
+	if o.taskError {
+		return c.TaskError
+	}
+
+	if o.signal != nil {
+		if code, ok := c.Signals[o.signal]; ok {
+			return code
+		}
+		if n, ok := signalNumber(o.signal); ok && c.SignalOffset != 0 {
+			return c.SignalOffset + n
+		}
+	}
+	return c.Clean
+}
+
+// Run runs the hooks registered with OnStart, calls each start function in
+// order with the manager's context, then blocks until a monitored signal is
+// received (restarting in-process on the signals given to
+// WithRestartSignals), shuts down gracefully and exits the process with the
+// code the exit code policy assigns to the outcome. If a start hook, start
+// function or warm-up task fails before the manager reports ready, or a
+// start function fails on restart, Run shuts down immediately and exits with
+// the StartupFailure code. A component that stops the manager after startup,
+// such as a server registered with HTTPServer whose Serve fails, makes Run
+// exit with the TaskError code instead.
+//
+// Run never returns. Use Wait to keep control of the process after shutdown.
+//
+// Example:
+//
+//	func main() {
+//		manager := graceful.New()
+//		manager.Run(func(ctx context.Context) error {
+//			return startServer(ctx, manager)
+//		})
+//	}
+func (m *Manager) Run(start ...func(ctx context.Context) error) {
+	m.mu.Lock()
+	m.starts = append(m.starts, start...)
+	m.mu.Unlock()
+
+	sigCh, stop := m.notifySignals(append(append([]os.Signal(nil), m.signals...), m.restartSignals...))
+	defer stop()
+
+	if err := m.runStartHooks(); err != nil {
+		m.exit(outcome{startupFailure: true, timedOut: m.shutdownTimedOut()})
+		return
+	}
+
+	m.beginGeneration()
+	ctx := m.Context()
+	for _, f := range start {
+		if err := f(ctx); err != nil {
+			m.logf("start function %s failed: %v", funcName(f), err)
+			m.exit(outcome{startupFailure: true, timedOut: m.shutdownTimedOut()})
+			return
+		}
+	}
+
+	sig, err := m.startup(context.Background(), sigCh)
+	if err != nil {
+		// Startup checks or warm-up tasks failed
+		m.logf("startup failed: %v", err)
+		m.exit(outcome{startupFailure: true, timedOut: m.shutdownTimedOut()})
+		return
+	}
+	if sig == nil {
+		sig, err = m.waitShutdownSignal(sigCh)
+	}
+	if err != nil {
+		// A restart failed to start again, or a component such as a server
+		// failed after startup
+		m.logf("stopping after failure: %v", err)
+		m.exit(outcome{
+			startupFailure: errors.Is(err, ErrStartupFailed),
+			taskError:      true,
+			timedOut:       m.shutdownTimedOut(),
+		})
+		return
+	}
+	m.exit(outcome{signal: sig, timedOut: m.shutdownTimedOut()})
+}
+
+// exit runs the final functions and terminates the process with the code
+// for the given outcome.
+func (m *Manager) exit(o outcome) {
+	o.taskError = o.taskError || len(m.taskErrors()) > 0
+	o.reason = m.reason.Load()
+	code := m.exitCodes.code(o)
+	m.runFinal(code)
+	m.flushOutput()
+	exit(code)
+}
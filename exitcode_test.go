@@ -0,0 +1,101 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestExitCodes 测试结果到退出码的映射
+func TestExitCodes(t *testing.T) {
+	// 使用默认信号，Plan 9上它们是没有编号的note
+	signals := defaultSignals()
+	interrupt, term := signals[0], signals[1]
+	offset := 0
+	if n, ok := signalNumber(term); ok {
+		offset = 128 + n
+	}
+
+	codes := DefaultExitCodes()
+	codes.SignalOffset = 128
+	codes.Signals = map[os.Signal]int{interrupt: 0}
+
+	tests := []struct {
+		name     string
+		outcome  outcome
+		expected int
+	}{
+		{"正常退出", outcome{}, 0},
+		{"超时", outcome{timedOut: true, signal: term}, 2},
+		{"任务错误", outcome{taskError: true}, 1},
+		{"启动失败优先", outcome{startupFailure: true, timedOut: true}, 3},
+		{"信号偏移", outcome{signal: term}, offset},
+		{"信号覆盖", outcome{signal: interrupt}, 0},
+	}
+
+	for _, tt := range tests {
+		if got := codes.code(tt.outcome); got != tt.expected {
+			t.Errorf("%s: 退出码应为%d，实际为%d", tt.name, tt.expected, got)
+		}
+	}
+
+	if got := DefaultExitCodes().code(outcome{signal: term}); got != 0 {
+		t.Errorf("默认策略下信号触发的正常关闭退出码应为0，实际为%d", got)
+	}
+}
+
+// TestRunStartupFailure 测试启动函数失败时以StartupFailure退出码退出
+func TestRunStartupFailure(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	logger := &recordingLogger{}
+	m := New(WithLogger(logger))
+	stopped := make(chan struct{})
+	m.Run(func(ctx context.Context) error {
+		m.CtxGo(func(ctx context.Context) {
+			<-ctx.Done()
+			close(stopped)
+		})
+		return errors.New("启动失败")
+	})
+
+	if code != DefaultExitCodes().StartupFailure {
+		t.Errorf("启动失败时退出码应为%d，实际为%d", DefaultExitCodes().StartupFailure, code)
+	}
+	logged := false
+	for _, line := range logger.lines {
+		logged = logged || strings.Contains(line, "启动失败")
+	}
+	if !logged {
+		t.Errorf("退出前应记录启动失败的原因，实际日志为%v", logger.lines)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("启动失败时应关闭已启动的goroutine")
+	}
+}
+
+// TestRunFailureAfterStartup 测试启动完成后组件失败以TaskError退出码退出，而非启动失败
+func TestRunFailureAfterStartup(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	m := New()
+	m.Run(func(ctx context.Context) error {
+		m.Go(func() {
+			<-m.Ready()
+			m.requestStop(errors.New("服务失败"))
+		})
+		return nil
+	})
+
+	if code != DefaultExitCodes().TaskError {
+		t.Errorf("启动后失败时退出码应为%d，实际为%d", DefaultExitCodes().TaskError, code)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package graceful
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestRunSignalExitCode 测试信号触发关闭时使用128+N退出码
+func TestRunSignalExitCode(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	codes := DefaultExitCodes()
+	codes.SignalOffset = 128
+	m := New(WithSignals(syscall.SIGUSR1), WithExitCodes(codes), WithTimeout(time.Second))
+
+	m.Run(func(ctx context.Context) error {
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+		}()
+		return nil
+	})
+
+	if expected := 128 + int(syscall.SIGUSR1); code != expected {
+		t.Errorf("退出码应为%d，实际为%d", expected, code)
+	}
+}
@@ -0,0 +1,43 @@
+package graceful
+
+// WithExitWhenDone returns an Option that makes Wait and Run shut down
+// without waiting for a signal once every managed goroutine has exited, so a
+// program can run either to completion or until it is signaled with one code
+// path, such as a batch job that also has to stop cleanly on SIGTERM. The
+// check applies once Wait or Run is waiting for a signal; goroutines that
+// exit before then do not end the run early. A manager with no goroutines
+// at all still waits, subject to WithNoTasksPolicy.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithExitWhenDone())
+//	manager.CtxGo(processBatch)
+//	manager.Wait() // Returns when processBatch returns, or on SIGTERM
+func WithExitWhenDone() Option {
+	return func(m *Manager) {
+		m.exitWhenDone = true
+	}
+}
+
+// taskExited records that a managed goroutine has exited.
+func (m *Manager) taskExited() {
+	m.managed.Add(-1)
+	if m.exitWhenDone {
+		m.stopIfDone()
+	}
+}
+
+// stopIfDone requests a stop if Wait or Run is waiting for a signal while no
+// managed goroutine is running.
+func (m *Manager) stopIfDone() {
+	if !m.awaitingSignal.Load() || m.managed.Load() != 0 {
+		return
+	}
+	m.mu.Lock()
+	started := m.started
+	m.mu.Unlock()
+	if started > 0 {
+		m.logf("all tasks have finished; shutting down")
+		m.requestStop(nil)
+	}
+}
@@ -0,0 +1,73 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestExitWhenDone 测试所有任务结束后Wait立即返回
+func TestExitWhenDone(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithExitWhenDone())
+
+	release := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) {
+		<-release
+	})
+	m.Go(func() {})
+
+	done := make(chan error, 1)
+	go func() { done <- m.Wait() }()
+
+	select {
+	case <-done:
+		t.Fatal("仍有任务运行时Wait不应返回")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(release)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("任务全部完成后应正常关闭，实际返回%v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("任务全部完成后Wait应立即返回")
+	}
+}
+
+// TestExitWhenDoneAlreadyFinished 测试调用Wait前任务已结束时立即返回
+func TestExitWhenDoneAlreadyFinished(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithExitWhenDone())
+
+	finished := make(chan struct{})
+	m.Go(func() { close(finished) })
+	<-finished
+	waitFor(t, func() bool { return m.managed.Load() == 0 }, "任务应已退出")
+
+	done := make(chan error, 1)
+	go func() { done <- m.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("应正常关闭，实际返回%v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("任务已结束时Wait应立即返回")
+	}
+}
+
+// TestExitWhenDoneNoTasks 测试没有任何任务时仍等待信号
+func TestExitWhenDoneNoTasks(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithExitWhenDone())
+
+	done := make(chan error, 1)
+	go func() { done <- m.Wait() }()
+	select {
+	case <-done:
+		t.Fatal("没有任务时Wait不应返回")
+	case <-time.After(time.Millisecond * 50):
+	}
+	m.requestStop(nil)
+	<-done
+}
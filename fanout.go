@@ -0,0 +1,64 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+)
+
+// FanOut starts n managed workers that call worker for the values received
+// from in and send the results to the returned channel. The output channel is
+// closed exactly once, after every worker has returned: when in is closed and
+// drained, or when shutdown begins.
+//
+// At shutdown the workers stop taking values from in, finish the value they
+// are processing, and drop its result if nobody is receiving any more, so an
+// abandoned output channel cannot block shutdown. The worker context is
+// canceled when shutdown begins.
+//
+// Example:
+//
+//	thumbs := graceful.FanOut(manager, uploads, 4, func(ctx context.Context, u Upload) Thumb {
+//		return render(ctx, u)
+//	})
+//	for t := range thumbs {
+//		store(t)
+//	}
+func FanOut[T, R any](m *Manager, in <-chan T, n int, worker func(ctx context.Context, v T) R) <-chan R {
+	if n < 1 {
+		n = 1
+	}
+	out := make(chan R, n)
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		m.CtxGo(func(ctx context.Context) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				var v T
+				var ok bool
+				select {
+				case v, ok = <-in:
+				case <-ctx.Done():
+					return
+				}
+				if !ok {
+					return
+				}
+
+				r := worker(ctx, v)
+				select {
+				case out <- r:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+
+	m.Go(func() {
+		wg.Wait()
+		close(out)
+	})
+	return out
+}
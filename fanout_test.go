@@ -0,0 +1,46 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestFanOut 测试多个工作者处理所有输入后关闭输出通道
+func TestFanOut(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	in := make(chan int)
+	go func() {
+		for i := 1; i <= 100; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	out := FanOut(m, in, 4, func(ctx context.Context, v int) int { return v * 2 })
+	sum := 0
+	for r := range out {
+		sum += r
+	}
+	if sum != 10100 {
+		t.Errorf("结果之和应为10100，实际为%d", sum)
+	}
+	m.Shutdown()
+}
+
+// TestFanOutShutdown 测试关闭时工作者停止且输出通道被关闭
+func TestFanOutShutdown(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	in := make(chan int)
+	defer close(in)
+
+	out := FanOut(m, in, 2, func(ctx context.Context, v int) int { return v })
+	in <- 1
+
+	// 无人接收结果时关闭也不应被阻塞
+	if m.waitForGoroutines() {
+		t.Fatal("关闭不应超时")
+	}
+	for range out {
+	}
+}
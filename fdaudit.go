@@ -0,0 +1,89 @@
+package graceful
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EventDescriptorsLeaked is emitted during shutdown when the descriptor audit
+// enabled with WithDescriptorAudit finds descriptors that remained open after
+// cleanup.
+const EventDescriptorsLeaked EventType = "descriptors_leaked"
+
+// Descriptor is an open file descriptor of the process.
+type Descriptor struct {
+	FD     int    // Descriptor number
+	Target string // What it refers to, such as a path or "socket:[12345]"
+}
+
+// DescriptorLeakError lists the sockets and files that were open when
+// shutdown began and were still open after cleanup.
+type DescriptorLeakError struct {
+	Descriptors []Descriptor
+}
+
+func (e *DescriptorLeakError) Error() string {
+	targets := make([]string, len(e.Descriptors))
+	for i, d := range e.Descriptors {
+		targets[i] = fmt.Sprintf("%d -> %s", d.FD, d.Target)
+	}
+	return fmt.Sprintf("graceful: %d descriptors still open after cleanup: %s", len(e.Descriptors), strings.Join(targets, ", "))
+}
+
+// WithDescriptorAudit returns an Option that snapshots the process's open file
+// descriptors when shutdown begins and again after shutdown hooks and
+// temporary file cleanup. Sockets and files open in both snapshots are logged
+// and reported in an EventDescriptorsLeaked event, catching leaks that no
+// Go-level tracking sees. The audit reads /proc/self/fd and therefore only
+// works on Linux; elsewhere the option has no effect.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithDescriptorAudit())
+func WithDescriptorAudit() Option {
+	return func(m *Manager) {
+		m.descriptorAudit = true
+	}
+}
+
+// snapshotDescriptors returns the descriptors to audit, or nil if the audit is
+// disabled or unsupported.
+func (m *Manager) snapshotDescriptors() map[int]string {
+	if !m.descriptorAudit {
+		return nil
+	}
+	fds, err := openDescriptors()
+	if err != nil {
+		return nil
+	}
+	audited := make(map[int]string, len(fds))
+	for fd, target := range fds {
+		// Standard streams are expected to stay open
+		if fd > 2 && (strings.HasPrefix(target, "socket:") || strings.HasPrefix(target, "/")) {
+			audited[fd] = target
+		}
+	}
+	return audited
+}
+
+// auditDescriptors reports the descriptors of before that are still open.
+func (m *Manager) auditDescriptors(before map[int]string) {
+	if before == nil {
+		return
+	}
+	after := m.snapshotDescriptors()
+	var leaked []Descriptor
+	for fd, target := range before {
+		if after[fd] == target {
+			leaked = append(leaked, Descriptor{FD: fd, Target: target})
+		}
+	}
+	if len(leaked) == 0 {
+		return
+	}
+	sort.Slice(leaked, func(i, j int) bool { return leaked[i].FD < leaked[j].FD })
+	err := &DescriptorLeakError{Descriptors: leaked}
+	m.logf("%d descriptors still open after cleanup: %v", len(leaked), leaked)
+	m.emit(Event{Type: EventDescriptorsLeaked, Err: err})
+}
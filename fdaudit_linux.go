@@ -0,0 +1,29 @@
+//go:build linux
+
+package graceful
+
+import (
+	"os"
+	"strconv"
+)
+
+// openDescriptors returns the open file descriptors of the process and what
+// they refer to.
+func openDescriptors() (map[int]string, error) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return nil, err
+	}
+	fds := make(map[int]string, len(entries))
+	for _, e := range entries {
+		fd, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		// The descriptor used to read the directory is gone by now
+		if target, err := os.Readlink("/proc/self/fd/" + e.Name()); err == nil {
+			fds[fd] = target
+		}
+	}
+	return fds, nil
+}
@@ -0,0 +1,49 @@
+//go:build linux
+
+package graceful
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDescriptorAudit 测试关闭后仍打开的文件被报告
+func TestDescriptorAudit(t *testing.T) {
+	leakedPath := filepath.Join(t.TempDir(), "leaked")
+	closedPath := filepath.Join(t.TempDir(), "closed")
+	leaked, err := os.Create(leakedPath)
+	if err != nil {
+		t.Fatalf("创建文件失败: %v", err)
+	}
+	defer leaked.Close()
+	closed, err := os.Create(closedPath)
+	if err != nil {
+		t.Fatalf("创建文件失败: %v", err)
+	}
+
+	var leakErr *DescriptorLeakError
+	m := New(WithTimeout(time.Second), WithDescriptorAudit(), WithEventHandler(func(e Event) {
+		if e.Type == EventDescriptorsLeaked {
+			leakErr = e.Err.(*DescriptorLeakError)
+		}
+	}))
+	m.OnShutdown(func(ctx context.Context) error {
+		return closed.Close()
+	})
+	m.Shutdown()
+
+	if leakErr == nil {
+		t.Fatal("应报告仍打开的文件")
+	}
+	msg := leakErr.Error()
+	if !strings.Contains(msg, leakedPath) {
+		t.Errorf("应报告未关闭的文件，实际为: %s", msg)
+	}
+	if strings.Contains(msg, closedPath) {
+		t.Errorf("不应报告关闭钩子中关闭的文件，实际为: %s", msg)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package graceful
+
+import "errors"
+
+// openDescriptors is not supported on this platform.
+func openDescriptors() (map[int]string, error) {
+	return nil, errors.New("graceful: descriptor audit requires /proc/self/fd")
+}
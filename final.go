@@ -0,0 +1,40 @@
+package graceful
+
+// OnExit registers a function that Run calls as the very last step before it
+// terminates the process, with the exit code it is about to use. It runs on
+// every exit path of Run — clean, timed out, startup failure — after the
+// flush phase, so it is the place for a can't-miss final word such as a
+// shutdown summary written to standard error. Functions run in registration
+// order, and a panicking function does not prevent the others or the exit.
+//
+// Keep final functions short: nothing bounds their duration.
+//
+// Example:
+//
+//	manager.OnExit(func(code int) {
+//		fmt.Fprintf(os.Stderr, "shutdown complete, exit code %d\n", code)
+//	})
+func (m *Manager) OnExit(f func(code int)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finals = append(m.finals, f)
+}
+
+// runFinal calls the final functions once, each protected from panics.
+func (m *Manager) runFinal(code int) {
+	m.mu.Lock()
+	finals := m.finals
+	m.finals = nil
+	m.mu.Unlock()
+
+	for _, f := range finals {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					m.logf("exit function panicked: %v", r)
+				}
+			}()
+			f(code)
+		}()
+	}
+}
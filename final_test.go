@@ -0,0 +1,34 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestOnExit 测试退出前在所有退出路径上调用最终函数
+func TestOnExit(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	m := New(WithTimeout(time.Second))
+	var codes []int
+	m.OnExit(func(c int) { panic("最终函数崩溃") })
+	m.OnExit(func(c int) {
+		if code != -1 {
+			t.Error("最终函数应在进程退出前运行")
+		}
+		codes = append(codes, c)
+	})
+	m.Run(func(ctx context.Context) error { return errors.New("启动失败") })
+
+	if len(codes) != 1 || codes[0] != DefaultExitCodes().StartupFailure {
+		t.Errorf("最终函数应收到启动失败的退出码，实际为%v", codes)
+	}
+	if code != DefaultExitCodes().StartupFailure {
+		t.Errorf("崩溃的最终函数不应阻止退出，退出码为%d", code)
+	}
+}
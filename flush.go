@@ -0,0 +1,83 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// WithFlushTimeout returns an Option that sets the budget for the flush phase.
+// The flush phase is the final step of shutdown and starts after managed
+// goroutines have exited or the shutdown timeout has expired, so its budget
+// is independent of the one set by WithTimeout.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithFlushTimeout(2 * time.Second))
+func WithFlushTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.flushTimeout = timeout
+	}
+}
+
+// OnFlush registers a function to run in the flush phase. Flush functions
+// run in registration order and receive a context bounded by the flush
+// timeout. An error returned by one flush function does not prevent the
+// remaining ones from running.
+//
+// The flush phase is intended for delivering data that was recorded while
+// the application was draining, such as buffered logs or error reports.
+//
+// Example:
+//
+//	manager.OnFlush(func(ctx context.Context) error {
+//		return logger.Sync()
+//	})
+func (m *Manager) OnFlush(f func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flushers = append(m.flushers, f)
+}
+
+// flush runs the flush phase of the shutdown that began at began exactly
+// once: first the functions registered with OnFlush, then the metrics push,
+// then the telemetry providers. Telemetry goes last and gets its own budget
+// so that spans and metrics describing the flush itself are still exported.
+func (m *Manager) flush(began time.Time, timedOut bool) {
+	m.flushOnce.Do(func() {
+		m.runFlushers()
+		m.pushMetrics(began, timedOut)
+
+		m.mu.Lock()
+		providers := append([]TelemetryProvider(nil), m.telemetry...)
+		m.mu.Unlock()
+
+		if len(providers) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), m.telemetryTimeout)
+			for _, p := range providers {
+				if err := p.Shutdown(ctx); err != nil {
+					m.logf("telemetry provider %T shutdown failed: %v", p, err)
+				}
+			}
+			cancel()
+		}
+	})
+}
+
+// runFlushers calls the functions registered with OnFlush within the flush
+// budget.
+func (m *Manager) runFlushers() {
+	m.mu.Lock()
+	flushers := append([]func(ctx context.Context) error(nil), m.flushers...)
+	m.mu.Unlock()
+
+	if len(flushers) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), m.flushTimeout)
+	defer cancel()
+	for _, f := range flushers {
+		if err := f(ctx); err != nil {
+			m.logf("flush function %s failed: %v", funcName(f), err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFlushOrder 测试flush函数在goroutine退出后按注册顺序执行
+func TestFlushOrder(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	exited := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		close(exited)
+	})
+
+	var order []int
+	m.OnFlush(func(ctx context.Context) error {
+		select {
+		case <-exited:
+		default:
+			t.Error("flush函数应在goroutine退出后执行")
+		}
+		order = append(order, 1)
+		return errors.New("flush失败")
+	})
+	m.OnFlush(func(ctx context.Context) error {
+		order = append(order, 2)
+		return nil
+	})
+
+	m.Shutdown()
+
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("flush函数执行顺序应为[1 2]，实际为%v", order)
+	}
+}
+
+// TestFlushRunsOnce 测试多次关闭时flush阶段只执行一次
+func TestFlushRunsOnce(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	count := 0
+	m.OnFlush(func(ctx context.Context) error {
+		count++
+		return nil
+	})
+
+	m.Shutdown()
+	m.Shutdown()
+
+	if count != 1 {
+		t.Errorf("flush函数应只执行1次，实际执行了%d次", count)
+	}
+}
+
+// TestFlushTimeout 测试flush阶段使用独立的超时时间
+func TestFlushTimeout(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond*10), WithFlushTimeout(time.Millisecond*50))
+
+	// 模拟一个超时的goroutine，flush阶段仍应获得完整预算
+	m.Go(func() {
+		time.Sleep(time.Millisecond * 200)
+	})
+
+	var remaining time.Duration
+	m.OnFlush(func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Error("flush上下文应设置截止时间")
+		}
+		remaining = time.Until(deadline)
+		return nil
+	})
+
+	m.Shutdown()
+
+	if remaining <= time.Millisecond*20 || remaining > time.Millisecond*50 {
+		t.Errorf("flush阶段剩余时间应接近50ms，实际为%v", remaining)
+	}
+}
+
+// TestFlushErrorsLogged 测试flush函数和遥测provider的错误会被记录
+func TestFlushErrorsLogged(t *testing.T) {
+	logger := &recordingLogger{}
+	m := New(WithTimeout(time.Second), WithLogger(logger))
+	m.OnFlush(func(ctx context.Context) error { return errors.New("刷新失败") })
+	m.ManageTelemetry(providerFunc(func(ctx context.Context) error { return errors.New("导出失败") }))
+	m.Shutdown()
+
+	for _, want := range []string{"刷新失败", "导出失败"} {
+		logged := false
+		for _, line := range logger.lines {
+			logged = logged || strings.Contains(line, want)
+		}
+		if !logged {
+			t.Errorf("应记录错误%q，实际日志为%v", want, logger.lines)
+		}
+	}
+}
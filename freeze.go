@@ -0,0 +1,89 @@
+package graceful
+
+import (
+	"time"
+)
+
+const (
+	// EventFreezing is emitted when the process's cgroup is about to be
+	// frozen, on Linux with cgroup v2.
+	EventFreezing EventType = "freezing"
+	// EventThawed is emitted when the process runs again after being frozen,
+	// with the time it was frozen in Event.Frozen.
+	EventThawed EventType = "thawed"
+)
+
+// WithFreezeDetection returns an Option that checks every interval whether
+// the process is being frozen by the cgroup v2 freezer, as container runtimes
+// do for pauses and checkpoint/restore, and emits EventFreezing and
+// EventThawed. A freeze that was not announced, such as a restore from a
+// checkpoint or a suspended virtual machine, is detected as a gap between
+// checks of more than twice the interval and reported with EventThawed.
+//
+// With pause set, the manager also pauses while frozen as if Pause had been
+// called, so periodic tasks and heartbeats do not fire in a burst, and
+// supervisors do not mistake the frozen time for a hang. Outside Linux or
+// cgroup v2, only the gap detection is available.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithFreezeDetection(time.Second, true))
+func WithFreezeDetection(interval time.Duration, pause bool) Option {
+	return func(m *Manager) {
+		m.freezeInterval = interval
+		m.pauseOnFreeze = pause
+	}
+}
+
+// monitorFreeze checks the freeze state with requested until shutdown
+// begins. requested reports whether a freeze has been requested, and false
+// for ok when the state cannot be read.
+func (m *Manager) monitorFreeze(requested func() (frozen, ok bool)) {
+	go func() {
+		ticker := time.NewTicker(m.freezeInterval)
+		defer ticker.Stop()
+
+		last := time.Now()
+		var frozenAt time.Time
+		paused := false // Whether the freeze paused the manager
+		for {
+			select {
+			case <-m.lifetime.Done():
+				return
+			case <-ticker.C:
+			}
+
+			now := time.Now()
+			gap := now.Sub(last)
+			last = now
+
+			frozen, ok := requested()
+			switch {
+			case ok && frozen && frozenAt.IsZero():
+				frozenAt = now
+				m.logf("process is being frozen")
+				m.emit(Event{Type: EventFreezing})
+				if m.pauseOnFreeze {
+					paused = m.pause(nil)
+				}
+			case !frozenAt.IsZero() && !(ok && frozen):
+				m.thawed(now.Sub(frozenAt), paused)
+				frozenAt, paused = time.Time{}, false
+			case frozenAt.IsZero() && gap > 2*m.freezeInterval:
+				// Nothing was paused for a freeze that is only noticed afterwards
+				m.thawed(gap-m.freezeInterval, false)
+			}
+		}
+	}()
+}
+
+// thawed reports that the process runs again after being frozen for d, and
+// resumes the manager if the freeze paused it, leaving a pause made by Pause
+// or a signal in place.
+func (m *Manager) thawed(d time.Duration, paused bool) {
+	m.logf("process was frozen for %v", d)
+	if paused {
+		m.resume(nil)
+	}
+	m.emit(Event{Type: EventThawed, Frozen: d})
+}
@@ -0,0 +1,41 @@
+//go:build linux
+
+package graceful
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup v2 hierarchy is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// freezeRequested returns a function that reports whether the freezer of the
+// process's cgroup v2 group has been asked to freeze it.
+func freezeRequested() func() (frozen, ok bool) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	group, found := cgroupPath(string(data))
+	if err != nil || !found {
+		return func() (bool, bool) { return false, false }
+	}
+	path := filepath.Join(cgroupRoot, group, "cgroup.freeze")
+	return func() (bool, bool) {
+		state, err := os.ReadFile(path)
+		if err != nil {
+			return false, false
+		}
+		return string(bytes.TrimSpace(state)) == "1", true
+	}
+}
+
+// cgroupPath returns the cgroup v2 path in the contents of /proc/self/cgroup.
+func cgroupPath(contents string) (string, bool) {
+	for _, line := range strings.Split(contents, "\n") {
+		if path, ok := strings.CutPrefix(line, "0::"); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,16 @@
+//go:build linux
+
+package graceful
+
+import "testing"
+
+// TestCgroupPath 测试从/proc/self/cgroup中解析cgroup v2路径
+func TestCgroupPath(t *testing.T) {
+	path, ok := cgroupPath("12:pids:/docker/abc\n0::/system.slice/app.service\n")
+	if !ok || path != "/system.slice/app.service" {
+		t.Errorf("应解析出cgroup v2路径，实际为%q和%v", path, ok)
+	}
+	if _, ok := cgroupPath("12:pids:/docker/abc\n"); ok {
+		t.Error("仅有cgroup v1时不应解析出路径")
+	}
+}
@@ -0,0 +1,91 @@
+package graceful
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMonitorFreeze 测试冻结与解冻时发出事件并暂停管理器
+func TestMonitorFreeze(t *testing.T) {
+	var mu sync.Mutex
+	var events []Event
+	m := New(WithTimeout(time.Second), WithEventHandler(func(e Event) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}))
+	m.freezeInterval = time.Millisecond * 10
+	m.pauseOnFreeze = true
+
+	var frozen atomic.Bool
+	m.monitorFreeze(func() (bool, bool) { return frozen.Load(), true })
+
+	frozen.Store(true)
+	waitFor(t, func() bool { return m.Paused() }, "冻结时应暂停管理器")
+	frozen.Store(false)
+	waitFor(t, func() bool { return !m.Paused() }, "解冻后应恢复管理器")
+	m.Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+	var types []EventType
+	var thawed Event
+	for _, e := range events {
+		if e.Type == EventFreezing || e.Type == EventThawed {
+			types = append(types, e.Type)
+		}
+		if e.Type == EventThawed {
+			thawed = e
+		}
+	}
+	if len(types) != 2 || types[0] != EventFreezing || types[1] != EventThawed {
+		t.Fatalf("应依次发出freezing和thawed事件，实际为%v", types)
+	}
+	if thawed.Frozen <= 0 {
+		t.Errorf("thawed事件应包含冻结时长，实际为%v", thawed.Frozen)
+	}
+}
+
+// TestMonitorFreezeKeepsManualPause 测试解冻不会取消冻结之前由Pause进入的暂停
+func TestMonitorFreezeKeepsManualPause(t *testing.T) {
+	var thaws atomic.Int32
+	m := New(WithTimeout(time.Second), WithEventHandler(func(e Event) {
+		if e.Type == EventThawed {
+			thaws.Add(1)
+		}
+	}))
+	defer m.Shutdown()
+	m.freezeInterval = time.Millisecond * 10
+	m.pauseOnFreeze = true
+
+	m.Pause()
+	var frozen atomic.Bool
+	m.monitorFreeze(func() (bool, bool) { return frozen.Load(), true })
+	frozen.Store(true)
+	time.Sleep(time.Millisecond * 30)
+	frozen.Store(false)
+	waitFor(t, func() bool { return thaws.Load() == 1 }, "解冻后应发出thawed事件")
+	if !m.Paused() {
+		t.Error("解冻不应取消手动暂停")
+	}
+
+	// 事后才发现的冻结没有暂停过管理器，也不应恢复
+	m.thawed(time.Second, false)
+	if !m.Paused() {
+		t.Error("间隔检测到的冻结不应取消手动暂停")
+	}
+}
+
+// waitFor 轮询等待条件成立
+func waitFor(t *testing.T, cond func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
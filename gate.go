@@ -0,0 +1,79 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrDraining is returned by RequestGate.Do once the manager has started
+// draining. The grpcgate interceptors translate it to codes.Unavailable so
+// that clients retry on another instance.
+var ErrDraining = errors.New("graceful: server is draining")
+
+// RequestGate admits incoming requests while the application is running and
+// rejects them once shutdown begins, while counting the requests in progress
+// so that shutdown can wait for them.
+type RequestGate struct {
+	inFlight inFlight
+}
+
+// RequestGate creates a gate that closes when shutdown begins, before managed
+// goroutines are canceled. Shutdown then waits, bounded by the shutdown
+// timeout, for the requests already admitted to finish, so their handlers
+// still have working dependencies.
+//
+// The same gate can be shared by HTTP and gRPC servers. For gRPC, the
+// interceptors in the separate github.com/kingcanfish/graceful/grpcgate
+// module call Do and answer codes.Unavailable once the gate has closed, so
+// this package does not depend on gRPC:
+//
+//	gate := manager.RequestGate()
+//	s := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpcgate.UnaryServerInterceptor(gate)),
+//		grpc.StreamInterceptor(grpcgate.StreamServerInterceptor(gate)),
+//	)
+func (m *Manager) RequestGate() *RequestGate {
+	g := &RequestGate{}
+	m.OnDrain(g.close)
+	return g
+}
+
+// Do runs fn as an admitted request. It returns ErrDraining without calling fn
+// once the gate has closed.
+func (g *RequestGate) Do(fn func() error) error {
+	if !g.inFlight.begin() {
+		return ErrDraining
+	}
+	defer g.inFlight.end()
+	return fn()
+}
+
+// InFlight returns the number of requests currently admitted.
+func (g *RequestGate) InFlight() int {
+	return g.inFlight.len()
+}
+
+// Middleware returns an HTTP handler that passes requests to next while the
+// gate is open and answers 503 Service Unavailable with "Connection: close"
+// once it has closed.
+//
+// Example:
+//
+//	srv := &http.Server{Handler: gate.Middleware(mux)}
+func (g *RequestGate) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !g.inFlight.begin() {
+			w.Header().Set("Connection", "close")
+			http.Error(w, ErrDraining.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		defer g.inFlight.end()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// close rejects new requests and waits for the admitted ones.
+func (g *RequestGate) close(ctx context.Context) error {
+	return g.inFlight.close(ctx)
+}
@@ -0,0 +1,74 @@
+package graceful
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRequestGate 测试关闭开始后拒绝新请求并等待进行中的请求
+func TestRequestGate(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	gate := m.RequestGate()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		_ = gate.Do(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+		close(finished)
+	}()
+	<-started
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		m.Shutdown()
+		close(shutdownDone)
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+	if err := gate.Do(func() error { return nil }); err != ErrDraining {
+		t.Errorf("drain开始后应返回ErrDraining，实际为%v", err)
+	}
+
+	select {
+	case <-shutdownDone:
+		t.Fatal("进行中请求完成前不应结束关闭")
+	default:
+	}
+
+	close(release)
+	<-finished
+	<-shutdownDone
+}
+
+// TestRequestGateMiddleware 测试HTTP中间件在drain后返回503
+func TestRequestGateMiddleware(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	gate := m.RequestGate()
+	handler := gate.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("运行期间应正常处理请求，实际状态码为%d", rec.Code)
+	}
+
+	m.Shutdown()
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("drain后应返回503，实际状态码为%d", rec.Code)
+	}
+	if rec.Header().Get("Connection") != "close" {
+		t.Error("drain后应设置Connection: close")
+	}
+}
@@ -0,0 +1,76 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+)
+
+// WithCancelOnError returns an Option that makes the manager shut down when
+// a goroutine started with GoErr fails, like an errgroup.Group created with
+// errgroup.WithContext: the first failure cancels every other goroutine,
+// and a pending Wait or Run returns.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithCancelOnError(true))
+func WithCancelOnError(cancel bool) Option {
+	return func(m *Manager) {
+		m.cancelOnError = cancel
+	}
+}
+
+// GoErr starts f as a managed task, like CtxGo, and records the error it
+// returns as a *TaskError. Shutdown, Wait and Stop return the recorded
+// errors joined with their own, and Run exits with the TaskError code, so a
+// failing worker no longer exits silently while the rest of the application
+// keeps running. Errors matching context.Canceled that a task returns after
+// its context was canceled are not recorded, since they report the shutdown
+// rather than a failure.
+//
+// Example:
+//
+//	manager.GoErr(func(ctx context.Context) error {
+//		return consumer.Run(ctx)
+//	}, graceful.WithName("consumer"))
+//	if err := manager.Wait(); err != nil {
+//		log.Fatal(err)
+//	}
+func (m *Manager) GoErr(f func(ctx context.Context) error, opts ...TaskOption) *Task {
+	return m.CtxGo(func(ctx context.Context) {
+		if err := f(ctx); err != nil {
+			m.taskFailed(ctx, funcName(f), err)
+		}
+	}, opts...)
+}
+
+// taskFailed records the error returned by the task owning ctx, which runs
+// the function named fn.
+func (m *Manager) taskFailed(ctx context.Context, fn string, err error) {
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		return
+	}
+	name := fn
+	if t, ok := ctx.Value(taskKey{}).(*Task); ok && t.name != "" {
+		name = t.name
+	}
+	taskErr := &TaskError{Name: name, Err: err}
+	m.logf("%v", taskErr)
+
+	m.mu.Lock()
+	m.taskErrs = append(m.taskErrs, taskErr)
+	first := len(m.taskErrs) == 1
+	m.mu.Unlock()
+
+	if first && m.cancelOnError {
+		m.errorShutdown.Store(true)
+		m.requestStop(nil)
+		go m.shutdown()
+	}
+}
+
+// taskErrors returns the errors recorded from tasks started with GoErr.
+func (m *Manager) taskErrors() []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]error(nil), m.taskErrs...)
+}
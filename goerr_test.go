@@ -0,0 +1,56 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestGoErr 测试任务返回的错误被收集并由Shutdown返回
+func TestGoErr(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	boom := errors.New("boom")
+
+	task := m.GoErr(func(ctx context.Context) error { return boom }, WithName("worker"))
+	<-task.Done()
+	// 关闭导致的取消错误不应被记录
+	m.GoErr(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := m.Shutdown()
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) || taskErr.Name != "worker" || !errors.Is(err, boom) {
+		t.Fatalf("Shutdown应返回任务错误，实际为%v", err)
+	}
+	if n := len(m.taskErrors()); n != 1 {
+		t.Errorf("应只记录1个错误，实际为%d", n)
+	}
+}
+
+// TestWithCancelOnError 测试首个任务失败时自动关闭
+func TestWithCancelOnError(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithCancelOnError(true))
+	boom := errors.New("boom")
+
+	canceled := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		close(canceled)
+	})
+	m.GoErr(func(ctx context.Context) error { return boom })
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("任务失败后应取消其他协程")
+	}
+	if err := m.Wait(); !errors.Is(err, boom) || errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("Wait应返回任务错误，实际为%v", err)
+	}
+	if err := m.Shutdown(); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("任务失败触发关闭后再次关闭应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+}
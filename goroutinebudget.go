@@ -0,0 +1,82 @@
+package graceful
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// EventGoroutineBudgetExceeded is emitted when the number of goroutines rises
+// above the budget set with WithGoroutineBudget, with a
+// *GoroutineBudgetError in Event.Err.
+const EventGoroutineBudgetExceeded EventType = "goroutine_budget_exceeded"
+
+// GoroutineBudgetError reports that the process runs more goroutines than its
+// budget. The deltas are relative to when the monitor started, split between
+// goroutines started through the manager and all others.
+type GoroutineBudgetError struct {
+	Budget         int // Configured budget
+	Goroutines     int // Goroutines in the process
+	Managed        int // Goroutines started through the manager
+	ManagedDelta   int // Change in managed goroutines since the monitor started
+	UnmanagedDelta int // Change in other goroutines since the monitor started
+}
+
+func (e *GoroutineBudgetError) Error() string {
+	return fmt.Sprintf("graceful: %d goroutines exceed the budget of %d (managed %+d, unmanaged %+d since start)",
+		e.Goroutines, e.Budget, e.ManagedDelta, e.UnmanagedDelta)
+}
+
+// WithGoroutineBudget returns an Option that samples the goroutine count every
+// interval and logs a warning and emits EventGoroutineBudgetExceeded when it
+// rises above budget. The alert fires again only after the count has dropped
+// back within the budget, so a steady leak is reported once rather than on
+// every sample. Growth is attributed to managed and unmanaged goroutines, an
+// early warning for the leaks that later make shutdown time out.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithGoroutineBudget(5000, 10*time.Second))
+func WithGoroutineBudget(budget int, interval time.Duration) Option {
+	return func(m *Manager) {
+		m.goroutineBudget = budget
+		m.goroutineInterval = interval
+	}
+}
+
+// monitorGoroutines samples the goroutine count until shutdown begins.
+func (m *Manager) monitorGoroutines() {
+	baseTotal, baseManaged := runtime.NumGoroutine(), int(m.managed.Load())
+
+	go func() {
+		ticker := time.NewTicker(m.goroutineInterval)
+		defer ticker.Stop()
+		exceeded := false
+		for {
+			select {
+			case <-m.lifetime.Done():
+				return
+			case <-ticker.C:
+			}
+
+			total, managed := runtime.NumGoroutine(), int(m.managed.Load())
+			if total <= m.goroutineBudget {
+				exceeded = false
+				continue
+			}
+			if exceeded {
+				continue
+			}
+			exceeded = true
+			err := &GoroutineBudgetError{
+				Budget:         m.goroutineBudget,
+				Goroutines:     total,
+				Managed:        managed,
+				ManagedDelta:   managed - baseManaged,
+				UnmanagedDelta: (total - managed) - (baseTotal - baseManaged),
+			}
+			m.logf("warning: %v", err)
+			m.emit(Event{Type: EventGoroutineBudgetExceeded, Err: err})
+		}
+	}()
+}
@@ -0,0 +1,64 @@
+package graceful
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestGoroutineBudget 测试协程数超出预算时发出事件并区分托管协程
+func TestGoroutineBudget(t *testing.T) {
+	events := make(chan Event, 4)
+	budget := runtime.NumGoroutine() + 5
+	m := New(
+		WithGoroutineBudget(budget, time.Millisecond*10),
+		WithEventHandler(func(e Event) {
+			if e.Type == EventGoroutineBudgetExceeded {
+				events <- e
+			}
+		}),
+	)
+
+	stop := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		m.Go(func() { <-stop })
+	}
+	var unmanaged sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		unmanaged.Add(1)
+		go func() {
+			defer unmanaged.Done()
+			<-stop
+		}()
+	}
+
+	var e Event
+	select {
+	case e = <-events:
+	case <-time.After(time.Second):
+		t.Fatal("超出预算时应发出事件")
+	}
+	var budgetErr *GoroutineBudgetError
+	if !errors.As(e.Err, &budgetErr) {
+		t.Fatalf("事件应携带GoroutineBudgetError，实际为%v", e.Err)
+	}
+	// 非托管增量取决于进程中其他测试遗留的协程，只检查托管部分
+	if budgetErr.ManagedDelta != 10 || budgetErr.Managed != 10 {
+		t.Errorf("托管协程增量归属不正确: %+v", budgetErr)
+	}
+	if n := m.Stats().Managed; n != 10 {
+		t.Errorf("应有10个托管协程，实际为%d", n)
+	}
+
+	select {
+	case <-events:
+		t.Error("持续超出预算时不应重复告警")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(stop)
+	unmanaged.Wait()
+	m.Shutdown()
+}
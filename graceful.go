@@ -11,10 +11,11 @@ package graceful
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
-	"os/signal"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,11 +23,183 @@ import (
 // It provides mechanisms to start goroutines, monitor their lifecycle,
 // and ensure they shut down cleanly when the application needs to terminate.
 type Manager struct {
-	ctx        context.Context    // Context for coordinating goroutine lifecycle
+	ctx        context.Context    // Context for the current generation of goroutines
 	cancelFunc context.CancelFunc // Function to cancel the context
-	wg         sync.WaitGroup     // WaitGroup for tracking active goroutines
+	wg         *sync.WaitGroup    // WaitGroup for tracking active goroutines
 	timeout    time.Duration      // Maximum time to wait for goroutines to exit
 	signals    []os.Signal        // OS signals to monitor for shutdown
+
+	lifetime     context.Context    // Canceled only by the final shutdown, not by restarts
+	stopLifetime context.CancelFunc // Function to cancel the lifetime context
+
+	mu               sync.Mutex                        // Guards ctx, cancelFunc, wg and the registration slices below
+	flushTimeout     time.Duration                     // Budget for the flush phase
+	flushers         []func(ctx context.Context) error // Functions run in the flush phase
+	telemetryTimeout time.Duration                     // Budget for flushing telemetry providers
+	telemetry        []TelemetryProvider               // Providers shut down at the end of the flush phase
+	flushOnce        sync.Once                         // Ensures the flush phase runs only once
+
+	eventHandler func(Event)   // Receives lifecycle events
+	jobControl   bool          // Whether SIGTSTP/SIGCONT pause the manager
+	pauseMu      sync.Mutex    // Guards resumed
+	resumed      chan struct{} // Closed on resume; nil when not paused
+
+	exitCodes ExitCodes                         // Maps shutdown outcomes to exit codes
+	starts    []func(ctx context.Context) error // Start functions passed to Run
+
+	generation *generationMarks // Registrations owned by the process; nil until Run calls the start functions
+
+	restartSignals []os.Signal // OS signals that restart the application in-process
+
+	options []Option // Options passed to New, kept for Snapshot
+
+	tasks    map[string]*Task                  // Running named tasks
+	live     map[*Task]struct{}                // Running tasks started with CtxGo
+	seq      uint64                            // Start order of the last registered task
+	hooks    []hook                            // Shutdown hooks in registration order
+	classes  map[DependencyClass]classLimits   // Budgets of the hook dependency classes
+	drainers []func(ctx context.Context) error // Functions run before goroutines are canceled
+
+	hookRetries []HookRetry // Attempts of the hooks registered with OnShutdownRetry
+
+	deregistrations []func(ctx context.Context) error // DNS delays run before intake stops
+
+	cancelDelay time.Duration // Wait between draining and canceling goroutines
+
+	pushURL string // Pushgateway job URL lifecycle metrics are pushed to on exit
+
+	freezeInterval time.Duration // Interval between cgroup freeze checks
+	pauseOnFreeze  bool          // Pause while the process is frozen
+
+	hookTimeout    time.Duration // Per-hook wait after which a hook is abandoned
+	abandonedHooks []string      // Hooks that did not return within the hook timeout
+
+	cancelOnError bool        // Shut down when a GoErr task fails
+	errorShutdown atomic.Bool // Shutdown was triggered by a failed task
+	taskErrs      []error     // Errors returned by GoErr tasks
+
+	reason atomic.Pointer[ShutdownReason] // Reason given to ShutdownWithReason, if any
+
+	phaseTimeouts map[int]time.Duration // Per-phase limits set with WithPhaseTimeout
+
+	childReaper bool              // Reap exited children on SIGCHLD
+	childMu     sync.Mutex        // Guards childExits, childReaped and stopReaper
+	childExits  map[int]ChildExit // Statuses of reaped children not yet claimed
+	childReaped chan struct{}     // Closed and replaced whenever a child is reaped
+	stopReaper  func()            // Stops the reaper started by startReaping, if any
+
+	stateMu      sync.Mutex
+	state        State                  // Current lifecycle milestone
+	reached      [StateStopped + 1]bool // Milestones reached so far
+	stateChanged chan struct{}          // Closed and replaced on every state change
+	done         chan struct{}          // Closed once the shutdown sequence has completed
+
+	writeBehinds []*writeBehind // Flushers run between the drain and the hooks
+
+	handoffs      []func(ctx context.Context, h Handoff) error // State transfers run before goroutines are canceled
+	handoffBudget time.Duration                                // Time limit of the handoffs; zero means half the timeout
+
+	tempPaths []string // Temporary files and directories removed at shutdown
+
+	coordinator     DrainCoordinator // Limits how many instances drain at once
+	coordinatorWait time.Duration    // Maximum time to wait for a drain slot
+
+	logger        Logger        // Receives lifecycle messages
+	noTasksPolicy NoTasksPolicy // What to do when nothing was registered
+	started       int           // Number of goroutines ever started
+
+	addresses []string         // Listen addresses reported in the startup summary
+	listeners []*listenerStats // Connection tracking of listeners created with Listen
+	servers   []*httpServer    // HTTP servers shut down when shutdown begins
+
+	drainTimes   map[string]time.Duration // Drain time declared per component
+	budgetPolicy BudgetPolicy             // What to do when drain times exceed the timeout
+
+	drainStrategy   DrainStrategy // Decides how tasks are stopped; nil cancels all at once
+	descriptorAudit bool          // Whether open descriptors are audited at shutdown
+
+	browserLifecycle bool // Whether browser page events drive the manager (GOOS=js)
+
+	timersMu      sync.Mutex          // Guards timers and timersStopped
+	timers        map[*Timer]struct{} // Pending timers created with AfterFunc
+	timersStopped bool                // Set once shutdown has stopped the timers
+
+	deadlockInterval time.Duration // Stack sampling interval while draining; zero disables
+	drainNice        *int          // Nice value of the drain thread, if set with WithDrainPriority
+
+	streamsMu        sync.Mutex           // Guards streams and streamsFinishing
+	streams          map[*stream]struct{} // Open streams registered with RegisterStream
+	streamsFinishing bool                 // Set once shutdown has finished the streams
+
+	triggers []Trigger // Shutdown sources watched in addition to signals
+
+	tracer       Tracer          // Records task, restart and shutdown spans, if set
+	shutdownSpan context.Context // Context of the shutdown span once started
+
+	middleware []TaskMiddleware // Wraps every task function, outermost first
+
+	attachMu      sync.Mutex               // Guards attached and attachStopped
+	attached      map[*attachment]struct{} // Contexts from AttachContext not yet done
+	attachStopped bool                     // Set once shutdown has canceled them
+
+	startTimeout time.Duration                     // Budget for the start hooks
+	startHooks   []func(ctx context.Context) error // Hooks run by Run before the start functions
+
+	finals []func(code int) // Functions run by Run right before exiting
+
+	stopRequested chan struct{}  // Closed when the manager requests its own shutdown
+	stopErr       error          // Error passed to requestStop
+	stopOnce      sync.Once      // Ensures stopRequested is closed once
+	startCalled   atomic.Bool    // Set by the first call to Start
+	startedUp     atomic.Bool    // Set once Start has completed startup
+	shutdownOnce  sync.Once      // Ensures the shutdown sequence runs once
+	drainDeadline atomic.Int64   // Unix nanoseconds at which the shutdown timeout expires; zero before shutdown
+	timedOut      bool           // Whether the shutdown timed out, set by shutdownOnce
+	stuck         int            // Goroutines still running at the timeout, set by shutdownOnce
+	stuckTasks    []string       // Names of the tasks still running at the timeout
+	deadlock      *DeadlockError // Goroutines seen blocked at the last drain timeout, guarded by mu
+	waitingMu     sync.Mutex     // Guards waitDone
+	waitDone      chan struct{}  // Closed when the running Wait returns; nil if none
+
+	warmups   sync.WaitGroup // Tracks warm-up tasks
+	ready     chan struct{}  // Closed once startup has completed
+	readyOnce sync.Once      // Ensures ready is closed once
+	draining  atomic.Bool    // Set when shutdown begins
+
+	ignoredSignals   []os.Signal   // Signals ignored when the manager is created
+	resetSignals     []os.Signal   // Signals reset to their default behavior
+	signalBuffer     int           // Depth of the internal signal channel
+	coalesceWindow   time.Duration // Window within which duplicate signals are dropped
+	signalsReceived  atomic.Int64  // Signals received from the OS
+	signalsCoalesced atomic.Int64  // Duplicate signals dropped within the window
+	signalsDropped   atomic.Int64  // Signals dropped because the channel was full
+	statusWriter     io.Writer     // Destination of status reports, if enabled
+
+	signalLogMu sync.Mutex     // Guards signalLog
+	signalLog   []SignalRecord // Most recent signals received, oldest first
+
+	managed           atomic.Int64  // Managed goroutines still running
+	goroutineBudget   int           // Goroutine count above which an alert fires
+	goroutineInterval time.Duration // Sampling interval of the goroutine budget; zero disables
+
+	reportStore ReportStore // Persists shutdown reports across restarts, if set
+	reportOnce  sync.Once   // Ensures the report is saved once
+
+	accounting bool                      // Whether tasks are sampled for Stats
+	statsMu    sync.Mutex                // Guards running and finished
+	running    map[*taskAccount]struct{} // Samples of running tasks
+	finished   map[string]*TaskStats     // Accounting of finished tasks by name
+
+	handledSignals map[os.Signal]bool // Signals taken over by HandleSignal
+
+	outputs    []OutputFlusher // Buffered writers flushed as the last step
+	outputSync bool            // Whether redirected standard streams are synced
+
+	name     string     // Name given to Child, for a child manager
+	children []*Manager // Child managers shut down with this one
+
+	exitWhenDone   bool        // Whether Wait and Run return once every task has exited
+	awaitingSignal atomic.Bool // Set while Wait or Run waits for a shutdown signal
 }
 
 // Option defines a function type for configuring Manager instances.
@@ -48,7 +221,7 @@ func WithTimeout(timeout time.Duration) Option {
 
 // WithSignals returns an Option that sets which OS signals the manager should
 // monitor for triggering graceful shutdown. By default, the manager monitors
-// SIGINT and SIGTERM.
+// SIGINT and SIGTERM, or the interrupt and hangup notes on Plan 9.
 //
 // Example:
 //
@@ -65,7 +238,11 @@ func WithSignals(signals ...os.Signal) Option {
 //
 // Default settings:
 // - Timeout: 30 seconds
-// - Signals: SIGINT and SIGTERM
+// - Signals: SIGINT and SIGTERM (the interrupt and hangup notes on Plan 9)
+// - Flush timeout: 5 seconds
+// - Telemetry timeout: 5 seconds
+// - Start timeout: 30 seconds
+// - Exit codes: DefaultExitCodes()
 //
 // Example:
 //
@@ -74,18 +251,55 @@ func WithSignals(signals ...os.Signal) Option {
 //		graceful.WithSignals(syscall.SIGINT, syscall.SIGTERM),
 //	)
 func New(options ...Option) *Manager {
-	ctx, cancel := context.WithCancel(context.Background())
+	return newManager(context.Background(), options)
+}
+
+// newManager creates a manager whose contexts derive from parent.
+func newManager(parent context.Context, options []Option) *Manager {
 	m := &Manager{
-		ctx:        ctx,
-		cancelFunc: cancel,
-		timeout:    time.Second * 30,                             // Default timeout: 30 seconds
-		signals:    []os.Signal{syscall.SIGINT, syscall.SIGTERM}, // Default signals
+		wg:      &sync.WaitGroup{},
+		timeout: time.Second * 30, // Default timeout: 30 seconds
+		signals: defaultSignals(), // Default signals
+
+		flushTimeout:     time.Second * 5,  // Default flush budget: 5 seconds
+		telemetryTimeout: time.Second * 5,  // Default telemetry budget: 5 seconds
+		startTimeout:     time.Second * 30, // Default start hook budget: 30 seconds
+		exitCodes:        DefaultExitCodes(),
+		stopRequested:    make(chan struct{}),
+		ready:            make(chan struct{}),
+		stateChanged:     make(chan struct{}),
+		done:             make(chan struct{}),
+		signalBuffer:     1,
 	}
+	// Contexts handed out by the manager lead back to it, for RemainingBudget
+	m.lifetime, m.stopLifetime = m.withShutdownCause(context.WithValue(parent, managerKey{}, m))
+	m.ctx, m.cancelFunc = m.withShutdownCause(m.lifetime)
 
+	m.options = options
 	for _, option := range options {
 		option(m)
 	}
 
+	m.applySignalPolicy()
+	if m.statusWriter != nil {
+		m.handleStatusSignal()
+	}
+	if m.jobControl {
+		m.handleJobControl()
+	}
+	if m.browserLifecycle {
+		m.handleBrowserLifecycle()
+	}
+	if m.goroutineInterval > 0 {
+		m.monitorGoroutines()
+	}
+	if m.freezeInterval > 0 {
+		m.monitorFreeze(freezeRequested())
+	}
+	if m.childReaper {
+		m.startReaping()
+	}
+
 	return m
 }
 
@@ -101,9 +315,16 @@ func New(options ...Option) *Manager {
 //		// The function will be stopped when manager initiates shutdown
 //	})
 func (m *Manager) Go(f func()) {
-	m.wg.Add(1)
+	m.mu.Lock()
+	wg := m.wg
+	wg.Add(1)
+	m.started++
+	m.mu.Unlock()
+
+	m.managed.Add(1)
 	go func() {
-		defer m.wg.Done()
+		defer wg.Done()
+		defer m.taskExited()
 		f()
 	}()
 }
@@ -113,28 +334,77 @@ func (m *Manager) Go(f func()) {
 // for them to complete or for the timeout to expire.
 //
 // This method is typically called in the main function after starting all
-// goroutines.
+// goroutines. If nothing was registered, or the declared drain times exceed
+// the timeout, the policies set by WithNoTasksPolicy and WithBudgetPolicy may
+// make it shut down without waiting.
+//
+// Wait returns an error wrapping ErrStartupFailed if startup failed, the
+// error that made the manager stop itself, such as a *TaskError, ErrTimeout
+// if goroutines were still running when the timeout expired, or
+// ErrAlreadyShutdown if the manager had already been shut down. Several of
+// these are combined with errors.Join.
 //
 // Example:
 //
 //	func main() {
 //		manager := graceful.New()
 //		// Start goroutines...
-//		manager.Wait() // Block until signal received
+//		if err := manager.Wait(); err != nil { // Block until signal received
+//			log.Print(err)
+//		}
 //	}
-func (m *Manager) Wait() {
-	// Create a signal channel
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, m.signals...)
+func (m *Manager) Wait() error {
+	done := m.beginWait()
+	defer close(done)
 
-	// Wait for signal
-	<-sigCh
+	sigCh, stop := m.notifySignals(m.signals)
+	defer stop()
 
-	// Stop receiving signals
-	signal.Stop(sigCh)
+	var err error
+	if m.startedUp.Load() || m.shutDown() {
+		// Startup already completed, or will never run
+		_, err = m.waitSignal(sigCh)
+	} else if sig, startErr := m.startup(context.Background(), sigCh); startErr != nil {
+		err = startupFailed(startErr)
+	} else if sig == nil {
+		_, err = m.waitSignal(sigCh)
+	}
 
 	// Notify all goroutines to exit and wait for completion
-	m.waitForGoroutines()
+	// Unlike Shutdown, Wait reports a shutdown started by a failed task or
+	// ShutdownWithReason rather than ErrAlreadyShutdown
+	first, timedOut := m.shutdown()
+	if !first && !m.errorShutdown.Load() && m.reason.Load() == nil {
+		return errors.Join(err, ErrAlreadyShutdown)
+	}
+	return errors.Join(err, m.shutdownResult(timedOut))
+}
+
+// waitSignal blocks until a signal is received on sigCh or the manager itself
+// requests a stop, for example because a warm-up task failed. It returns the
+// signal, or the error the stop was requested with.
+func (m *Manager) waitSignal(sigCh <-chan os.Signal) (os.Signal, error) {
+	if m.exitWhenDone {
+		m.awaitingSignal.Store(true)
+		defer m.awaitingSignal.Store(false)
+		m.stopIfDone()
+	}
+
+	select {
+	case sig := <-sigCh:
+		return sig, nil
+	case <-m.stopRequested:
+		return nil, m.stopErr
+	}
+}
+
+// requestStop makes Wait and Run stop waiting and shut down. Only the first
+// request's error is kept.
+func (m *Manager) requestStop(err error) {
+	m.stopOnce.Do(func() {
+		m.stopErr = err
+		close(m.stopRequested)
+	})
 }
 
 // Shutdown initiates graceful shutdown without waiting for signals.
@@ -142,7 +412,19 @@ func (m *Manager) Wait() {
 // or for the timeout to expire.
 //
 // This method is useful when you need to programmatically shut down the
-// application.
+// application. It is safe to call on a manager that was never started with
+// Wait, Run or Start: registered drain functions and hooks run as usual, and
+// the signal handlers, monitors and child reaper New started for options
+// such as WithStatusSignal and WithChildReaper stop. Signals ignored with
+// WithIgnoredSignals stay ignored, since that policy is meant to cover the
+// whole process.
+//
+// Shutdown returns ErrTimeout if goroutines were still running when the
+// timeout expired, and ErrAlreadyShutdown if the manager had already been
+// shut down; a call made while another shutdown is in progress waits for it
+// to complete first and also returns ErrAlreadyShutdown, including when the
+// shutdown was triggered by WithCancelOnError or ShutdownWithReason. The
+// errors of failed GoErr tasks are joined to the result.
 //
 // Example:
 //
@@ -150,26 +432,120 @@ func (m *Manager) Wait() {
 //		// Handle error and shut down
 //		manager.Shutdown()
 //	}
-func (m *Manager) Shutdown() {
+func (m *Manager) Shutdown() error {
 	// Notify all goroutines to exit and wait for completion
-	m.waitForGoroutines()
+	return m.shutdownErr()
 }
 
-// waitForGoroutines handles the graceful shutdown process by canceling
-// the context and waiting for all goroutines to exit or for the timeout
-// to expire.
-func (m *Manager) waitForGoroutines() {
-	// Notify all goroutines to exit
-	m.cancelFunc()
+// shutdown runs the shutdown sequence once; concurrent calls wait for it to
+// complete. It reports whether this call ran it and whether the timeout
+// expired.
+func (m *Manager) shutdown() (first, timedOut bool) {
+	m.shutdownOnce.Do(func() {
+		first = true
+		m.runPrioritized(func() { m.timedOut = m.waitForGoroutines() })
+		m.stopReaping()
+		m.setState(StateStopped)
+		close(m.done)
+		// Let a pending Wait or Run return
+		m.requestStop(nil)
+	})
+	return first, m.timedOut
+}
 
-	// Create a timeout context
+// shutdownErr shuts down and returns the error Shutdown reports.
+func (m *Manager) shutdownErr() error {
+	first, timedOut := m.shutdown()
+	if !first {
+		return ErrAlreadyShutdown
+	}
+	return m.shutdownResult(timedOut)
+}
+
+// shutdownResult returns the errors of the completed shutdown: the timeout,
+// if it expired, joined with the failures.
+func (m *Manager) shutdownResult(timedOut bool) error {
+	var err error
+	if timedOut {
+		err = m.timeoutErr()
+	}
+	return errors.Join(append([]error{err}, m.failures()...)...)
+}
+
+// timeoutErr returns the error reporting the goroutines that were still
+// running when the shutdown timed out.
+func (m *Manager) timeoutErr() error {
+	m.mu.Lock()
+	deadlock := m.deadlock
+	m.mu.Unlock()
+	return &TimeoutError{Stuck: m.stuck, Tasks: m.stuckTasks, Deadlock: deadlock}
+}
+
+// shutdownTimedOut shuts down and reports whether the timeout expired.
+func (m *Manager) shutdownTimedOut() bool {
+	_, timedOut := m.shutdown()
+	return timedOut
+}
+
+// waitForGoroutines handles the graceful shutdown process by running the drain
+// functions, canceling the context and waiting for all goroutines to exit or
+// for the timeout to expire. Shutdown hooks then run with whatever remains of the timeout,
+// followed by the flush phase. It reports whether the timeout expired before
+// all goroutines exited.
+func (m *Manager) waitForGoroutines() (timedOut bool) {
+	// Stop reporting readiness
+	began := time.Now()
+	m.setState(StateDraining)
+	m.draining.Store(true)
+	m.cancelAttached()
+	children := m.shutdownChildren()
+	endSpan := m.startShutdownSpan()
+	descriptors := m.snapshotDescriptors()
+
+	// Wait for our turn if drains are coordinated across instances
+	release := m.acquireDrainSlot()
+
+	// Create a timeout context shared by goroutines and hooks
 	timeoutCtx, cancel := context.WithTimeout(context.Background(), m.timeout)
 	defer cancel()
+	deadline, _ := timeoutCtx.Deadline()
+	m.drainDeadline.Store(deadline.UnixNano())
+
+	// Keep serving until clients stop resolving the instance
+	m.awaitDeregistration(timeoutCtx)
+
+	timedOut = m.stopGeneration(timeoutCtx, children, nil)
+	release()
+
+	// Remove temporary paths even when the timeout was exceeded
+	m.removeTempPaths()
+	m.auditDescriptors(descriptors)
+	endSpan(timedOut)
+
+	// Deliver whatever was recorded during the drain
+	m.flush(began, timedOut)
+	m.saveReport(began, timedOut)
+	m.flushOutput()
+
+	return timedOut
+}
+
+// drain cancels the current generation's context and waits for its
+// goroutines to exit or for timeoutCtx to expire. It reports whether the
+// timeout expired before all goroutines exited.
+func (m *Manager) drain(timeoutCtx context.Context) (timedOut bool) {
+	m.mu.Lock()
+	cancelFunc, wg := m.cancelFunc, m.wg
+	m.mu.Unlock()
+
+	// Notify all goroutines to exit
+	cancelFunc()
+	stopDetector := m.detectDeadlocks()
 
 	// Wait for all goroutines to exit or timeout
 	c := make(chan struct{})
 	go func() {
-		m.wg.Wait()
+		wg.Wait()
 		close(c)
 	}()
 
@@ -178,7 +554,87 @@ func (m *Manager) waitForGoroutines() {
 		// All goroutines have exited
 	case <-timeoutCtx.Done():
 		// Timeout occurred
+		timedOut = true
+	}
+
+	stuck := stopDetector()
+	var deadlock *DeadlockError
+	if timedOut {
+		deadlock = m.reportDeadlock(stuck)
+	}
+	m.mu.Lock()
+	m.deadlock = deadlock
+	m.mu.Unlock()
+	return timedOut
+}
+
+// stopGeneration runs the part of the shutdown sequence that tears down what
+// the start functions passed to Run started: it finishes streams, runs the
+// drain functions and handoffs, stops phased tasks and lets the drain
+// strategy stop the rest, cancels the goroutines and waits for them, then
+// flushes write-behind components and runs the shutdown hooks. Restart and
+// Rehearse run it with a nil children and a timeoutCtx marked by withRestart,
+// and Rehearse passes r to record how long the phases took. The final
+// shutdown passes the wait for its child managers and also stops the timers
+// and the lifetime context, so that no later generation starts. It reports
+// whether the timeout expired before all goroutines exited.
+func (m *Manager) stopGeneration(timeoutCtx context.Context, children func(ctx context.Context) bool, r *RehearsalReport) (timedOut bool) {
+	final := children != nil
+	if r == nil {
+		r = &RehearsalReport{}
+	}
+	phase := time.Now()
+
+	// Let long-lived streams end cleanly, then stop intake while goroutines
+	// can still finish in-flight work
+	m.finishStreams(timeoutCtx)
+	m.runDrainers(timeoutCtx)
+	if final {
+		m.stopTimers()
+	}
+
+	// Hand in-memory state off to peers while workers still serve it
+	m.runHandoffs(timeoutCtx)
+
+	// Stop phased tasks in order, then let the drain strategy stop the rest
+	m.drainPhases(timeoutCtx)
+	if m.drainStrategy != nil {
+		m.drainStrategy.Drain(timeoutCtx, m.liveTasks())
+	}
+
+	// Give requests admitted just before the signal a moment to finish
+	m.delayCancel(timeoutCtx)
+	r.Drain = time.Since(phase)
+
+	phase = time.Now()
+	if final {
+		// Notify all goroutines, including those of future generations, to exit
+		m.stopLifetime()
+		m.emit(Event{Type: EventCanceled})
 	}
+	timedOut = m.drain(timeoutCtx)
+	if final {
+		if !children(timeoutCtx) {
+			timedOut = true
+		}
+		if timedOut {
+			m.stuck, m.stuckTasks = int(m.managed.Load()), m.namedLiveTasks()
+			m.logf("%v", m.timeoutErr())
+			m.emit(Event{Type: EventGoroutinesExited, Err: ErrTimeout})
+		} else {
+			m.emit(Event{Type: EventGoroutinesExited})
+		}
+	}
+	r.Wait = time.Since(phase)
+
+	// Persist buffered writes while their stores are still open
+	phase = time.Now()
+	m.flushWriteBehinds(timeoutCtx)
+
+	// Release resources in hook order
+	m.runHooks(timeoutCtx)
+	r.Hooks = time.Since(phase)
+	return timedOut
 }
 
 // Context returns the manager's context, which is canceled when shutdown
@@ -190,16 +646,19 @@ func (m *Manager) waitForGoroutines() {
 //	ctx := manager.Context()
 //	// Use ctx to create child contexts or pass to functions
 func (m *Manager) Context() context.Context {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.ctx
 }
 
-// CtxGo starts a new managed goroutine. The provided function receives a context
-// that will be canceled when the manager initiates shutdown. Goroutines should
-// monitor this context and exit when it's canceled.
+// CtxGo starts a new managed goroutine. The provided function receives its own
+// context, derived from the manager's context, that will be canceled when the
+// manager initiates shutdown or when the returned Task is canceled. Goroutines
+// should monitor this context and exit when it's canceled.
 //
 // Example:
 //
-//	manager.CtxGo(func(ctx context.Context) {
+//	task := manager.CtxGo(func(ctx context.Context) {
 //		for {
 //			select {
 //			case <-ctx.Done():
@@ -210,8 +669,29 @@ func (m *Manager) Context() context.Context {
 //			}
 //		}
 //	})
-func (m *Manager) CtxGo(f func(ctx context.Context)) {
-	m.Go(func() {
-		f(m.ctx)
-	})
+//	// Stop only this goroutine
+//	task.Cancel(errors.New("no longer needed"))
+func (m *Manager) CtxGo(f func(ctx context.Context), opts ...TaskOption) *Task {
+	var cfg taskConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	t := m.newTask(cfg)
+	m.register(t)
+	m.run(t, f)
+	return t
+}
+
+// GoNamed starts a managed goroutine like CtxGo, named as with WithName. A
+// shutdown that times out logs the names of the tasks that never exited and
+// lists them in the returned *TimeoutError, so naming long-lived goroutines
+// makes a hung shutdown traceable.
+//
+// Example:
+//
+//	manager.GoNamed("order-consumer", consume)
+func (m *Manager) GoNamed(name string, f func(ctx context.Context), opts ...TaskOption) *Task {
+	// Copy opts so that the caller's spare capacity is not written to
+	return m.CtxGo(f, append(opts[:len(opts):len(opts)], WithName(name))...)
 }
@@ -0,0 +1,186 @@
+// Package gracefultest runs a program as a child process to test its graceful
+// shutdown end to end: it sends real signals and reports the exit code, how
+// long the drain took and the shutdown report the program saved.
+//
+// Example:
+//
+//	func TestShutdown(t *testing.T) {
+//		bin := gracefultest.Build(t, "./cmd/server")
+//		p := gracefultest.Start(t, bin)
+//		p.WaitOutput("graceful: started", 10*time.Second)
+//		p.Signal(syscall.SIGTERM)
+//		res := p.Wait(30 * time.Second)
+//		if res.ExitCode != 0 || res.Drain > 5*time.Second {
+//			t.Errorf("unexpected shutdown: %+v", res)
+//		}
+//	}
+package gracefultest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kingcanfish/graceful"
+)
+
+// ReportEnv is the environment variable that holds the path where the child
+// process should save its shutdown report. Programs opt in with
+//
+//	graceful.WithReportStore(graceful.FileReportStore(os.Getenv(gracefultest.ReportEnv)))
+//
+// when the variable is set.
+const ReportEnv = "GRACEFUL_REPORT_FILE"
+
+// Build compiles the main package pkg with the go command and returns the
+// path of the binary, which is removed when the test ends.
+func Build(t testing.TB, pkg string) string {
+	t.Helper()
+	bin := filepath.Join(t.TempDir(), filepath.Base(pkg))
+	out, err := exec.Command("go", "build", "-o", bin, pkg).CombinedOutput()
+	if err != nil {
+		t.Fatalf("gracefultest: building %s failed: %v\n%s", pkg, err, out)
+	}
+	return bin
+}
+
+// Process is a child process started with Start.
+type Process struct {
+	t      testing.TB
+	cmd    *exec.Cmd
+	report string
+	output syncBuffer
+	done   chan struct{}
+	err    error     // Error returned by cmd.Wait, set before done is closed
+	exited time.Time // When the process exited, set before done is closed
+
+	mu       sync.Mutex
+	signaled time.Time // When the last signal was sent
+}
+
+// Result describes how a child process exited.
+type Result struct {
+	ExitCode int                      // Exit code, or -1 if it was killed by a signal
+	Drain    time.Duration            // Time from the last signal sent to the exit
+	Output   string                   // Combined standard output and standard error
+	Report   *graceful.ShutdownReport // Report saved by the process, if any
+}
+
+// Start runs the binary at path with args. Its report path is passed in the
+// ReportEnv environment variable. The process is killed if it is still
+// running when the test ends.
+func Start(t testing.TB, path string, args ...string) *Process {
+	t.Helper()
+	p := &Process{
+		t:      t,
+		cmd:    exec.Command(path, args...),
+		report: filepath.Join(t.TempDir(), "shutdown.json"),
+		done:   make(chan struct{}),
+	}
+	p.cmd.Env = append(os.Environ(), ReportEnv+"="+p.report)
+	p.cmd.Stdout = &p.output
+	p.cmd.Stderr = &p.output
+	if err := p.cmd.Start(); err != nil {
+		t.Fatalf("gracefultest: starting %s failed: %v", path, err)
+	}
+
+	go func() {
+		p.err = p.cmd.Wait()
+		p.exited = time.Now()
+		close(p.done)
+	}()
+	t.Cleanup(func() {
+		select {
+		case <-p.done:
+		default:
+			_ = p.cmd.Process.Kill()
+			<-p.done
+		}
+	})
+	return p
+}
+
+// WaitOutput waits until the output of the process contains s, failing the
+// test if it exits or timeout expires first.
+func (p *Process) WaitOutput(s string, timeout time.Duration) {
+	p.t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !strings.Contains(p.output.String(), s) {
+		select {
+		case <-p.done:
+			p.t.Fatalf("gracefultest: process exited before printing %q:\n%s", s, p.output.String())
+		default:
+		}
+		if time.Now().After(deadline) {
+			p.t.Fatalf("gracefultest: process did not print %q within %v:\n%s", s, timeout, p.output.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Signal sends sig to the process.
+func (p *Process) Signal(sig os.Signal) {
+	p.t.Helper()
+	p.mu.Lock()
+	p.signaled = time.Now()
+	p.mu.Unlock()
+	if err := p.cmd.Process.Signal(sig); err != nil {
+		p.t.Fatalf("gracefultest: sending %v failed: %v", sig, err)
+	}
+}
+
+// Wait waits for the process to exit, killing it and failing the test if it
+// does not exit within timeout.
+func (p *Process) Wait(timeout time.Duration) Result {
+	p.t.Helper()
+	select {
+	case <-p.done:
+	case <-time.After(timeout):
+		_ = p.cmd.Process.Kill()
+		<-p.done
+		p.t.Fatalf("gracefultest: process did not exit within %v:\n%s", timeout, p.output.String())
+	}
+
+	res := Result{ExitCode: p.cmd.ProcessState.ExitCode(), Output: p.output.String()}
+	var exitErr *exec.ExitError
+	if p.err != nil && !errors.As(p.err, &exitErr) {
+		p.t.Fatalf("gracefultest: waiting for the process failed: %v", p.err)
+	}
+	p.mu.Lock()
+	if !p.signaled.IsZero() {
+		res.Drain = p.exited.Sub(p.signaled)
+	}
+	p.mu.Unlock()
+
+	report, err := graceful.FileReportStore(p.report).Load(context.Background())
+	if err != nil {
+		p.t.Fatalf("gracefultest: loading the shutdown report failed: %v", err)
+	}
+	res.Report = report
+	return res
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,49 @@
+//go:build unix
+
+package gracefultest
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestProcess 测试子进程收到信号后的退出码、排空耗时和关闭报告
+func TestProcess(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("需要go命令")
+	}
+	bin := Build(t, "./testdata/app")
+
+	tests := []struct {
+		name     string
+		drain    string
+		exitCode int
+		timedOut bool
+	}{
+		{"正常关闭", "100ms", 0, false},
+		{"超时关闭", "5s", 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Start(t, bin, tt.drain)
+			p.WaitOutput("graceful: started", 10*time.Second)
+			p.Signal(syscall.SIGTERM)
+			res := p.Wait(10 * time.Second)
+
+			if res.ExitCode != tt.exitCode {
+				t.Errorf("退出码应为%d，实际为%d:\n%s", tt.exitCode, res.ExitCode, res.Output)
+			}
+			if res.Drain < 100*time.Millisecond || res.Drain > 3*time.Second {
+				t.Errorf("排空耗时不正确: %v", res.Drain)
+			}
+			if res.Report == nil || res.Report.TimedOut != tt.timedOut {
+				t.Fatalf("关闭报告不正确: %+v", res.Report)
+			}
+			if tt.timedOut && (len(res.Report.AbandonedTasks) != 1 || res.Report.AbandonedTasks[0] != "worker") {
+				t.Errorf("报告应列出被放弃的任务: %+v", res.Report)
+			}
+		})
+	}
+}
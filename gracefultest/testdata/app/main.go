@@ -0,0 +1,26 @@
+// Command app is a program used to test the harness: it runs one task that
+// takes the duration given as its first argument to drain.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/kingcanfish/graceful"
+)
+
+func main() {
+	drain, _ := time.ParseDuration(os.Args[1])
+	manager := graceful.New(
+		graceful.WithTimeout(time.Second),
+		graceful.WithLogger(log.Default()),
+		graceful.WithReportStore(graceful.FileReportStore(os.Getenv("GRACEFUL_REPORT_FILE"))),
+	)
+	manager.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(drain)
+	}, graceful.WithName("worker"))
+	manager.Run()
+}
@@ -0,0 +1,170 @@
+package graceful
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// ClientConns guards outbound gRPC connections so that they are closed only
+// after the RPCs issued through them have finished, instead of failing
+// in-flight calls with "connection is closing".
+//
+// The package does not depend on gRPC. Calls are tracked by wrapping the
+// invoker in an interceptor that calls Do, and *grpc.ClientConn satisfies
+// io.Closer.
+type ClientConns struct {
+	guard closeGuard
+	m     *Manager
+
+	mu       sync.Mutex
+	streams  map[*clientStream]struct{} // Streams opened through Stream still running
+	draining bool                       // Drain has half-closed the streams
+}
+
+// ClientStream is the part of a client-side gRPC stream used to end it.
+// grpc.ClientStream satisfies it.
+type ClientStream interface {
+	CloseSend() error
+}
+
+// clientStream is a stream opened through Stream.
+type clientStream struct {
+	stream ClientStream
+}
+
+// ManageClientConns registers gRPC client connections to be closed by a
+// shutdown hook, after managed goroutines have exited. Before closing, the
+// hook waits, bounded by the remaining shutdown timeout, for RPCs issued
+// through Do to finish.
+//
+// Example:
+//
+//	var conns *graceful.ClientConns
+//	conn, _ := grpc.Dial(target, grpc.WithUnaryInterceptor(
+//		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+//			invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+//			return conns.Do(func() error {
+//				return invoker(ctx, method, req, reply, cc, opts...)
+//			})
+//		}))
+//	conns = manager.ManageClientConns(conn)
+func (m *Manager) ManageClientConns(conns ...io.Closer) *ClientConns {
+	c := &ClientConns{guard: closeGuard{closers: conns}, m: m}
+	m.OnDrain(c.closeSends)
+	m.OnShutdown(c.guard.close)
+	return c
+}
+
+// Do runs call as a tracked RPC. It returns ErrClientClosing without calling
+// call once the connections have started closing.
+func (c *ClientConns) Do(call func() error) error {
+	return c.guard.do(call)
+}
+
+// InFlight returns the number of RPCs currently running through Do.
+func (c *ClientConns) InFlight() int {
+	return c.guard.inFlight.len()
+}
+
+// Stream runs a long-lived client stream, such as a watch or subscribe RPC,
+// through the connections. open is called with a context derived from ctx to
+// start the stream, then recv is called to receive from it until it returns,
+// and Stream returns what recv returned.
+//
+// When drain starts, the stream is half-closed with CloseSend so the server
+// can end it and RecvMsg returns io.EOF. If recv is still running when
+// managed goroutines are canceled, the stream's context is canceled so
+// RecvMsg cannot block past the shutdown deadline. The connections are closed
+// only after recv has returned. Once drain has started, Stream returns
+// ErrClientClosing without calling open.
+//
+// Example:
+//
+//	err := conns.Stream(ctx,
+//		func(ctx context.Context) (graceful.ClientStream, error) {
+//			return client.Watch(ctx, req)
+//		},
+//		func(s graceful.ClientStream) error {
+//			for {
+//				ev, err := s.(pb.Config_WatchClient).Recv()
+//				if err == io.EOF {
+//					return nil
+//				}
+//				if err != nil {
+//					return err
+//				}
+//				apply(ev)
+//			}
+//		})
+func (c *ClientConns) Stream(ctx context.Context, open func(ctx context.Context) (ClientStream, error), recv func(s ClientStream) error) error {
+	return c.guard.do(func() error {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Cancel the stream once managed goroutines are canceled
+		go func() {
+			select {
+			case <-c.m.Context().Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		s, err := open(ctx)
+		if err != nil {
+			return err
+		}
+		cs := &clientStream{stream: s}
+		if !c.track(cs) {
+			_ = s.CloseSend()
+			return ErrClientClosing
+		}
+		defer c.untrack(cs)
+		return recv(s)
+	})
+}
+
+// Streams returns the number of streams currently running through Stream.
+func (c *ClientConns) Streams() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.streams)
+}
+
+// track records a running stream. It reports false once drain has started.
+func (c *ClientConns) track(cs *clientStream) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.draining {
+		return false
+	}
+	if c.streams == nil {
+		c.streams = make(map[*clientStream]struct{})
+	}
+	c.streams[cs] = struct{}{}
+	return true
+}
+
+// untrack removes a stream that has ended.
+func (c *ClientConns) untrack(cs *clientStream) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.streams, cs)
+}
+
+// closeSends half-closes the running streams so servers can end them.
+func (c *ClientConns) closeSends(ctx context.Context) error {
+	c.mu.Lock()
+	c.draining = true
+	streams := make([]*clientStream, 0, len(c.streams))
+	for cs := range c.streams {
+		streams = append(streams, cs)
+	}
+	c.mu.Unlock()
+
+	for _, cs := range streams {
+		_ = cs.stream.CloseSend()
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package graceful
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestManageClientConns 测试gRPC连接在进行中调用结束后关闭
+func TestManageClientConns(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	conn1, conn2 := &closerRecorder{}, &closerRecorder{}
+	conns := m.ManageClientConns(conn1, conn2)
+
+	started := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) {
+		_ = conns.Do(func() error {
+			close(started)
+			// 模拟在关闭开始后才完成的调用
+			<-ctx.Done()
+			time.Sleep(time.Millisecond * 20)
+			if conn1.closed || conn2.closed {
+				t.Error("进行中调用结束前不应关闭连接")
+			}
+			return nil
+		})
+	})
+	<-started
+
+	m.Shutdown()
+
+	if !conn1.closed || !conn2.closed {
+		t.Error("关闭时应关闭所有连接")
+	}
+	if err := conns.Do(func() error { return nil }); err != ErrClientClosing {
+		t.Errorf("关闭后应返回ErrClientClosing，实际为%v", err)
+	}
+	if conns.InFlight() != 0 {
+		t.Errorf("关闭后进行中调用数应为0，实际为%d", conns.InFlight())
+	}
+}
+
+// fakeClientStream 模拟客户端流，CloseSend后服务端结束流
+type fakeClientStream struct {
+	ctx       context.Context
+	closeSend chan struct{}
+	once      sync.Once
+}
+
+func (s *fakeClientStream) CloseSend() error {
+	if s.closeSend != nil {
+		s.once.Do(func() { close(s.closeSend) })
+	}
+	return nil
+}
+
+// recv 模拟RecvMsg，在服务端结束流或上下文取消时返回
+func (s *fakeClientStream) recv() error {
+	select {
+	case <-s.closeSend:
+		return io.EOF
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+// TestClientConnsStream 测试排空时半关闭长连接流并在流结束后关闭连接
+func TestClientConnsStream(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	conn := &closerRecorder{}
+	conns := m.ManageClientConns(conn)
+
+	opened := make(chan struct{})
+	var result error
+	m.Go(func() {
+		result = conns.Stream(context.Background(),
+			func(ctx context.Context) (ClientStream, error) {
+				defer close(opened)
+				return &fakeClientStream{ctx: ctx, closeSend: make(chan struct{})}, nil
+			},
+			func(s ClientStream) error {
+				err := s.(*fakeClientStream).recv()
+				if conn.closed {
+					t.Error("流结束前不应关闭连接")
+				}
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			})
+	})
+	<-opened
+	if conns.Streams() != 1 {
+		t.Errorf("应有1个运行中的流，实际为%d", conns.Streams())
+	}
+
+	m.Shutdown()
+
+	if result != nil {
+		t.Errorf("流应通过CloseSend正常结束，实际为%v", result)
+	}
+	if !conn.closed {
+		t.Error("关闭时应关闭连接")
+	}
+	err := conns.Stream(context.Background(), func(ctx context.Context) (ClientStream, error) {
+		t.Error("排空后不应打开流")
+		return nil, nil
+	}, nil)
+	if err != ErrClientClosing {
+		t.Errorf("排空后应返回ErrClientClosing，实际为%v", err)
+	}
+}
+
+// TestClientConnsStreamCanceled 测试服务端不结束流时在取消协程时取消流上下文
+func TestClientConnsStreamCanceled(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	conns := m.ManageClientConns(&closerRecorder{})
+
+	opened := make(chan struct{})
+	var result error
+	m.Go(func() {
+		result = conns.Stream(context.Background(),
+			func(ctx context.Context) (ClientStream, error) {
+				defer close(opened)
+				// CloseSend不会结束该流
+				return &fakeClientStream{ctx: ctx, closeSend: nil}, nil
+			},
+			func(s ClientStream) error {
+				fs := s.(*fakeClientStream)
+				<-fs.ctx.Done()
+				return fs.ctx.Err()
+			})
+	})
+	<-opened
+
+	start := time.Now()
+	m.Shutdown()
+	if elapsed := time.Since(start); elapsed > time.Millisecond*500 {
+		t.Errorf("流应随协程取消而结束，耗时%v", elapsed)
+	}
+	if result != context.Canceled {
+		t.Errorf("应返回context.Canceled，实际为%v", result)
+	}
+}
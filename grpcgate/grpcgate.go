@@ -0,0 +1,58 @@
+// Package grpcgate adapts graceful.RequestGate to gRPC server interceptors,
+// so that gRPC servers reject new calls with codes.Unavailable once shutdown
+// begins while the calls already admitted finish. It is a separate module
+// so that the graceful module itself does not depend on gRPC.
+//
+// Example:
+//
+//	gate := manager.RequestGate()
+//	srv := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpcgate.UnaryServerInterceptor(gate)),
+//		grpc.StreamInterceptor(grpcgate.StreamServerInterceptor(gate)),
+//	)
+package grpcgate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kingcanfish/graceful"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns an interceptor that runs each unary call
+// through gate, answering codes.Unavailable once the gate has closed so that
+// clients retry on another instance.
+func UnaryServerInterceptor(gate *graceful.RequestGate) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		err = gate.Do(func() error {
+			resp, err = handler(ctx, req)
+			return err
+		})
+		if errors.Is(err, graceful.ErrDraining) {
+			return nil, unavailable(err)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that runs each stream
+// through gate, answering codes.Unavailable once the gate has closed. A
+// stream counts as in flight until its handler returns, so shutdown waits
+// for open streams, bounded by the shutdown timeout.
+func StreamServerInterceptor(gate *graceful.RequestGate) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := gate.Do(func() error { return handler(srv, ss) })
+		if errors.Is(err, graceful.ErrDraining) {
+			return unavailable(err)
+		}
+		return err
+	}
+}
+
+// unavailable converts the gate's rejection to a gRPC status.
+func unavailable(err error) error {
+	return status.Error(codes.Unavailable, err.Error())
+}
@@ -0,0 +1,72 @@
+package grpcgate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kingcanfish/graceful"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestUnaryServerInterceptor 测试关闭开始后一元调用被拒绝并返回Unavailable
+func TestUnaryServerInterceptor(t *testing.T) {
+	m := graceful.New(graceful.WithTimeout(time.Second))
+	interceptor := UnaryServerInterceptor(m.RequestGate())
+	handler := func(ctx context.Context, req any) (any, error) { return "pong", nil }
+
+	resp, err := interceptor(context.Background(), "ping", &grpc.UnaryServerInfo{}, handler)
+	if err != nil || resp != "pong" {
+		t.Fatalf("关闭前调用应成功，实际为%v, %v", resp, err)
+	}
+
+	m.Shutdown()
+	if _, err := interceptor(context.Background(), "ping", &grpc.UnaryServerInfo{}, handler); status.Code(err) != codes.Unavailable {
+		t.Errorf("关闭后调用应返回Unavailable，实际为%v", err)
+	}
+}
+
+// TestStreamServerInterceptor 测试关闭等待进行中的流结束，之后的新流被拒绝
+func TestStreamServerInterceptor(t *testing.T) {
+	m := graceful.New(graceful.WithTimeout(time.Second))
+	gate := m.RequestGate()
+	interceptor := StreamServerInterceptor(gate)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- interceptor(nil, nil, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	shutdown := make(chan struct{})
+	go func() {
+		m.Shutdown()
+		close(shutdown)
+	}()
+	select {
+	case <-shutdown:
+		t.Fatal("关闭应等待进行中的流结束")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	err := interceptor(nil, nil, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+		t.Error("关闭开始后不应运行新的流")
+		return nil
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("关闭开始后新流应返回Unavailable，实际为%v", err)
+	}
+
+	close(release)
+	if err := <-streamErr; err != nil {
+		t.Errorf("进行中的流不应失败，实际为%v", err)
+	}
+	<-shutdown
+}
@@ -0,0 +1,95 @@
+package graceful
+
+import (
+	"context"
+	"net"
+)
+
+// GRPCServer is the part of a gRPC server used to serve and stop it.
+// *grpc.Server satisfies it.
+type GRPCServer interface {
+	Serve(ln net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+// HealthServer is a health service that can report NOT_SERVING for all
+// services. *health.Server from google.golang.org/grpc/health satisfies it.
+type HealthServer interface {
+	Shutdown()
+}
+
+// GRPCOption configures a gRPC server registered with GRPCServer.
+type GRPCOption func(*grpcServer)
+
+// WithHealthServer returns a GRPCOption that marks every service of health
+// as NOT_SERVING when shutdown begins, before the server stops, so that load
+// balancers checking it stop routing new RPCs to the instance.
+//
+// Example:
+//
+//	healthSrv := health.NewServer()
+//	healthpb.RegisterHealthServer(srv, healthSrv)
+//	manager.GRPCServer(srv, ln, graceful.WithHealthServer(healthSrv))
+func WithHealthServer(health HealthServer) GRPCOption {
+	return func(s *grpcServer) {
+		s.health = health
+	}
+}
+
+// grpcServer is a gRPC server registered with GRPCServer.
+type grpcServer struct {
+	srv    GRPCServer
+	health HealthServer // Health service marked NOT_SERVING at drain, if any
+}
+
+// GRPCServer serves srv on ln and stops it gracefully when shutdown begins.
+// GracefulStop stops accepting connections and waits for pending RPCs; if
+// they do not finish within the shutdown timeout, Stop closes the remaining
+// connections and cancels their RPCs. If Serve fails before shutdown, the
+// manager shuts down.
+//
+// The package does not depend on gRPC: srv is usually a *grpc.Server.
+//
+// Example:
+//
+//	ln, err := manager.Listen("tcp", ":9090")
+//	if err != nil {
+//		return err
+//	}
+//	manager.GRPCServer(srv, ln)
+func (m *Manager) GRPCServer(srv GRPCServer, ln net.Listener, opts ...GRPCOption) {
+	s := &grpcServer{srv: srv}
+	for _, opt := range opts {
+		opt(s)
+	}
+	m.OnDrain(s.stop)
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !m.draining.Load() {
+			m.logf("grpc server on %s failed: %v", ln.Addr(), err)
+			m.requestStop(err)
+		}
+	}()
+}
+
+// stop marks the health service NOT_SERVING and stops the server gracefully,
+// falling back to Stop when ctx is done first.
+func (s *grpcServer) stop(ctx context.Context) error {
+	if s.health != nil {
+		s.health.Shutdown()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.srv.Stop()
+		<-done
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package graceful
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGRPCServer 模拟gRPC服务器，GracefulStop会等待未完成的RPC
+type fakeGRPCServer struct {
+	mu       sync.Mutex
+	calls    []string
+	serveErr error
+	stopped  chan struct{}
+	pending  chan struct{} // 关闭时表示未完成的RPC已结束
+	once     sync.Once
+}
+
+func newFakeGRPCServer() *fakeGRPCServer {
+	return &fakeGRPCServer{stopped: make(chan struct{}), pending: make(chan struct{})}
+}
+
+func (s *fakeGRPCServer) record(call string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+}
+
+func (s *fakeGRPCServer) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+func (s *fakeGRPCServer) Serve(ln net.Listener) error {
+	if s.serveErr != nil {
+		return s.serveErr
+	}
+	<-s.stopped
+	return nil
+}
+
+func (s *fakeGRPCServer) GracefulStop() {
+	s.record("GracefulStop")
+	s.once.Do(func() { close(s.stopped) })
+	<-s.pending
+}
+
+func (s *fakeGRPCServer) Stop() {
+	s.record("Stop")
+	close(s.pending)
+}
+
+// fakeHealthServer 记录健康检查服务是否被标记为NOT_SERVING
+type fakeHealthServer struct {
+	srv *fakeGRPCServer
+}
+
+func (h *fakeHealthServer) Shutdown() {
+	h.srv.record("health")
+}
+
+// TestGRPCServerGracefulStop 测试关闭时先标记健康检查再优雅停止
+func TestGRPCServerGracefulStop(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer ln.Close()
+
+	m := New(WithTimeout(time.Second))
+	srv := newFakeGRPCServer()
+	close(srv.pending)
+	m.GRPCServer(srv, ln, WithHealthServer(&fakeHealthServer{srv: srv}))
+
+	if err := m.Shutdown(); err != nil {
+		t.Errorf("优雅停止不应返回错误，实际为%v", err)
+	}
+	calls := srv.recorded()
+	if len(calls) != 2 || calls[0] != "health" || calls[1] != "GracefulStop" {
+		t.Errorf("应先标记NOT_SERVING再调用GracefulStop，实际为%v", calls)
+	}
+}
+
+// TestGRPCServerStopFallback 测试未完成的RPC超时后调用Stop
+func TestGRPCServerStopFallback(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer ln.Close()
+
+	m := New(WithTimeout(time.Millisecond * 50))
+	srv := newFakeGRPCServer()
+	m.GRPCServer(srv, ln)
+
+	start := time.Now()
+	m.Shutdown()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("超时后应强制停止，实际耗时%v", elapsed)
+	}
+	calls := srv.recorded()
+	if len(calls) != 2 || calls[0] != "GracefulStop" || calls[1] != "Stop" {
+		t.Errorf("超时后应调用Stop，实际为%v", calls)
+	}
+}
+
+// TestGRPCServerServeError 测试Serve失败时管理器停止
+func TestGRPCServerServeError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer ln.Close()
+
+	m := New(WithTimeout(time.Second))
+	m.Go(func() {})
+	srv := newFakeGRPCServer()
+	srv.serveErr = errors.New("accept failed")
+	close(srv.pending)
+	m.GRPCServer(srv, ln)
+
+	done := make(chan error, 1)
+	go func() { done <- m.Wait() }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Serve失败时Wait应返回错误")
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("Serve失败后管理器应停止")
+	}
+}
@@ -0,0 +1,70 @@
+package graceful
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// HandleSignal calls fn with the manager's context every time sig is
+// received, such as reloading the configuration on SIGHUP. Calls for one
+// signal run one at a time, so a burst of signals does not run fn
+// concurrently; signals arriving while fn runs are coalesced into one
+// further call. A handled signal no longer triggers shutdown or restart,
+// even if it was given to WithSignals or WithRestartSignals, while the other
+// monitored signals still drive graceful shutdown. Handling stops when
+// shutdown begins.
+//
+// HandleSignal must be called before Wait or Run.
+//
+// Example:
+//
+//	manager.HandleSignal(syscall.SIGHUP, func(ctx context.Context) {
+//		if err := config.Reload(ctx); err != nil {
+//			log.Printf("reload failed: %v", err)
+//		}
+//	})
+func (m *Manager) HandleSignal(sig os.Signal, fn func(ctx context.Context)) {
+	if len(m.supportedSignals([]os.Signal{sig})) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	if m.handledSignals == nil {
+		m.handledSignals = make(map[os.Signal]bool)
+	}
+	m.handledSignals[sig] = true
+	m.mu.Unlock()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, sig)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-m.lifetime.Done():
+				return
+			case <-sigCh:
+				m.recordSignal(sig, time.Now(), true)
+				fn(m.Context())
+			}
+		}
+	}()
+}
+
+// unhandledSignals returns the signals not taken over by HandleSignal.
+func (m *Manager) unhandledSignals(signals []os.Signal) []os.Signal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.handledSignals) == 0 {
+		return signals
+	}
+	unhandled := make([]os.Signal, 0, len(signals))
+	for _, sig := range signals {
+		if !m.handledSignals[sig] {
+			unhandled = append(unhandled, sig)
+		}
+	}
+	return unhandled
+}
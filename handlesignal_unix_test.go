@@ -0,0 +1,47 @@
+//go:build unix
+
+package graceful
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestHandleSignal 测试处理的信号调用回调而不触发关闭
+func TestHandleSignal(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithSignals(syscall.SIGHUP, syscall.SIGUSR2))
+	m.Go(func() {})
+
+	reloads := make(chan struct{}, 4)
+	m.HandleSignal(syscall.SIGHUP, func(ctx context.Context) {
+		if ctx.Err() != nil {
+			t.Error("回调应收到未取消的管理器上下文")
+		}
+		reloads <- struct{}{}
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- m.Wait() }()
+	time.Sleep(time.Millisecond * 20)
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	select {
+	case <-reloads:
+	case <-time.After(time.Second):
+		t.Fatal("收到SIGHUP后应调用回调")
+	}
+	select {
+	case <-done:
+		t.Fatal("处理的信号不应触发关闭")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR2)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("其他监控的信号应仍然触发关闭")
+	}
+}
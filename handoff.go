@@ -0,0 +1,89 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Handoff describes the window in which a stateful service can transfer its
+// in-memory state, such as shards or sessions, to peer instances.
+type Handoff struct {
+	Began    time.Time // When the handoff window opened; peers can be notified now
+	Deadline time.Time // When managed goroutines are canceled
+}
+
+// Remaining returns the time left in the handoff window.
+func (h Handoff) Remaining() time.Duration {
+	return time.Until(h.Deadline)
+}
+
+// WithHandoffBudget returns an Option that sets how much of the shutdown
+// timeout the hooks registered with OnHandoff may take before managed
+// goroutines are canceled. The default is half of the shutdown timeout, which
+// leaves the rest for the workers to exit and the shutdown hooks to run.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithHandoffBudget(10 * time.Second))
+func WithHandoffBudget(budget time.Duration) Option {
+	return func(m *Manager) {
+		m.handoffBudget = budget
+	}
+}
+
+// OnHandoff registers a function that transfers in-memory state to peer
+// instances during shutdown. Handoff functions run concurrently once intake
+// has stopped, after the drain functions and before managed goroutines are
+// canceled, so workers keep serving the state until it has been handed off.
+// Each receives the handoff window and a context that expires at its
+// deadline. Errors are logged; the shutdown proceeds regardless.
+//
+// Example:
+//
+//	manager.OnHandoff(func(ctx context.Context, h graceful.Handoff) error {
+//		peers.Announce(ctx, shards.Owned())
+//		return shards.TransferAll(ctx)
+//	})
+func (m *Manager) OnHandoff(f func(ctx context.Context, h Handoff) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handoffs = append(m.handoffs, f)
+}
+
+// runHandoffs runs the handoff functions concurrently within the handoff
+// budget and clears them; a restart only runs and clears those of the
+// running generation, whose state is going away.
+func (m *Manager) runHandoffs(ctx context.Context) {
+	m.mu.Lock()
+	handoffs := takeRegistrations(&m.handoffs, m.marks().handoffs, restarting(ctx))
+	m.mu.Unlock()
+
+	if len(handoffs) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.handoffWindow())
+	defer cancel()
+	h := Handoff{Began: time.Now()}
+	h.Deadline, _ = ctx.Deadline()
+
+	var wg sync.WaitGroup
+	for _, f := range handoffs {
+		wg.Add(1)
+		go func(f func(ctx context.Context, h Handoff) error) {
+			defer wg.Done()
+			if err := f(ctx, h); err != nil {
+				m.logf("handoff failed: %v", err)
+			}
+		}(f)
+	}
+	wg.Wait()
+}
+
+// handoffWindow returns the budget of the handoff functions.
+func (m *Manager) handoffWindow() time.Duration {
+	if m.handoffBudget <= 0 {
+		return m.timeout / 2
+	}
+	return m.handoffBudget
+}
@@ -0,0 +1,49 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestOnHandoff 测试交接函数在工作协程取消前并发运行
+func TestOnHandoff(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithHandoffBudget(time.Millisecond*200))
+
+	var workerCanceled atomic.Bool
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		workerCanceled.Store(true)
+	})
+
+	var handedOff atomic.Int64
+	var mu sync.Mutex
+	var window Handoff
+	for i := 0; i < 2; i++ {
+		m.OnHandoff(func(ctx context.Context, h Handoff) error {
+			mu.Lock()
+			window = h
+			mu.Unlock()
+			time.Sleep(time.Millisecond * 50)
+			if workerCanceled.Load() {
+				t.Error("交接期间工作协程不应被取消")
+			}
+			handedOff.Add(1)
+			return nil
+		})
+	}
+	start := time.Now()
+	m.Shutdown()
+
+	if handedOff.Load() != 2 {
+		t.Errorf("应运行2个交接函数，实际为%d", handedOff.Load())
+	}
+	if elapsed := time.Since(start); elapsed > time.Millisecond*90 {
+		t.Errorf("交接函数应并发运行，耗时%v", elapsed)
+	}
+	if d := window.Deadline.Sub(window.Began); d <= 0 || d > time.Millisecond*200 {
+		t.Errorf("交接窗口应受预算限制，实际为%v", d)
+	}
+}
@@ -0,0 +1,214 @@
+package graceful
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrHandoverRefused is returned by both sides of a handover handshake when
+// the old process's policies refuse the new one.
+var ErrHandoverRefused = errors.New("graceful: handover refused")
+
+// ProcessInfo describes one side of a zero-downtime binary restart.
+type ProcessInfo struct {
+	Version      string   // Release version, such as "v1.4.2"
+	Schema       int      // Version of the persisted data or wire schema
+	Capabilities []string // Features the process supports
+}
+
+// HandoverPolicy decides whether the old process may hand over to the new
+// one. It returns an error describing why not.
+type HandoverPolicy func(old, new ProcessInfo) error
+
+// RefuseDowngrade returns a policy that refuses a new process with a lower
+// version than the old one. Versions are compared as dot-separated numbers,
+// ignoring a leading "v" and any "-" or "+" suffix.
+func RefuseDowngrade() HandoverPolicy {
+	return func(old, new ProcessInfo) error {
+		c, err := compareVersions(new.Version, old.Version)
+		if err != nil {
+			return err
+		}
+		if c < 0 {
+			return fmt.Errorf("downgrade from %s to %s", old.Version, new.Version)
+		}
+		return nil
+	}
+}
+
+// RequireSchema returns a policy that refuses a new process whose schema is
+// not compatible with the old one according to compatible. A nil compatible
+// requires equal schemas.
+func RequireSchema(compatible func(old, new int) bool) HandoverPolicy {
+	if compatible == nil {
+		compatible = func(old, new int) bool { return old == new }
+	}
+	return func(old, new ProcessInfo) error {
+		if !compatible(old.Schema, new.Schema) {
+			return fmt.Errorf("schema %d is not compatible with %d", new.Schema, old.Schema)
+		}
+		return nil
+	}
+}
+
+// RequireCapabilities returns a policy that refuses a new process lacking
+// any of the given capabilities.
+func RequireCapabilities(capabilities ...string) HandoverPolicy {
+	return func(old, new ProcessInfo) error {
+		have := make(map[string]bool, len(new.Capabilities))
+		for _, c := range new.Capabilities {
+			have[c] = true
+		}
+		for _, c := range capabilities {
+			if !have[c] {
+				return fmt.Errorf("missing capability %q", c)
+			}
+		}
+		return nil
+	}
+}
+
+// handoverVerdict is the old process's reply in the handshake.
+type handoverVerdict struct {
+	Info   ProcessInfo
+	Refuse string `json:",omitempty"`
+}
+
+// ApproveHandover runs the old process's side of a handover handshake over
+// rw, usually a pipe or socket pair shared with the new process: it reads the
+// new process's info, checks it against the policies in order and replies
+// with its own info and the verdict. It returns the new process's info, and
+// an error wrapping ErrHandoverRefused if a policy refused it, in which case
+// the old process should keep serving instead of handing over its listeners
+// and draining.
+//
+// The package does not pass listeners between processes itself; run the
+// handshake before whatever mechanism does.
+//
+// Example:
+//
+//	peer, err := graceful.ApproveHandover(pipe, self,
+//		graceful.RefuseDowngrade(),
+//		graceful.RequireSchema(nil),
+//	)
+//	if err != nil {
+//		log.Printf("keeping the old process: %v", err)
+//		return
+//	}
+//	handOverListeners(pipe)
+//	manager.Shutdown()
+func ApproveHandover(rw io.ReadWriter, self ProcessInfo, policies ...HandoverPolicy) (ProcessInfo, error) {
+	var peer ProcessInfo
+	if err := readHandshake(rw, &peer); err != nil {
+		return ProcessInfo{}, err
+	}
+
+	verdict := handoverVerdict{Info: self}
+	for _, p := range policies {
+		if err := p(self, peer); err != nil {
+			verdict.Refuse = err.Error()
+			break
+		}
+	}
+	if err := json.NewEncoder(rw).Encode(verdict); err != nil {
+		return peer, err
+	}
+	if verdict.Refuse != "" {
+		return peer, fmt.Errorf("%w: %s", ErrHandoverRefused, verdict.Refuse)
+	}
+	return peer, nil
+}
+
+// ProposeHandover runs the new process's side of a handover handshake over
+// rw: it sends the process's info and waits for the old process's verdict.
+// It returns the old process's info, and an error wrapping
+// ErrHandoverRefused if the old process refused the handover, in which case
+// the new process should exit without binding.
+//
+// Example:
+//
+//	if _, err := graceful.ProposeHandover(pipe, self); err != nil {
+//		log.Fatal(err)
+//	}
+func ProposeHandover(rw io.ReadWriter, self ProcessInfo) (ProcessInfo, error) {
+	if err := json.NewEncoder(rw).Encode(self); err != nil {
+		return ProcessInfo{}, err
+	}
+	var verdict handoverVerdict
+	if err := readHandshake(rw, &verdict); err != nil {
+		return ProcessInfo{}, err
+	}
+	if verdict.Refuse != "" {
+		return verdict.Info, fmt.Errorf("%w: %s", ErrHandoverRefused, verdict.Refuse)
+	}
+	return verdict.Info, nil
+}
+
+// readHandshake reads one JSON line of the handshake into v. It reads a
+// single line so that data sent after the handshake stays in r.
+func readHandshake(r io.Reader, v any) error {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return fmt.Errorf("graceful: reading handshake: %w", err)
+		}
+		if b[0] == '\n' {
+			break
+		}
+		line = append(line, b[0])
+	}
+	if err := json.Unmarshal(line, v); err != nil {
+		return fmt.Errorf("graceful: reading handshake: %w", err)
+	}
+	return nil
+}
+
+// compareVersions compares two versions, returning -1, 0 or 1.
+func compareVersions(a, b string) (int, error) {
+	pa, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	pb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// parseVersion splits a version into its numeric parts.
+func parseVersion(v string) ([]int, error) {
+	s := strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	var parts []int
+	for _, f := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q", v)
+		}
+		parts = append(parts, n)
+	}
+	return parts, nil
+}
@@ -0,0 +1,75 @@
+package graceful
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+// handshake 在一对连接上运行新旧进程两侧的握手
+func handshake(t *testing.T, old, new ProcessInfo, policies ...HandoverPolicy) (oldErr, newErr error) {
+	t.Helper()
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var peer ProcessInfo
+		peer, oldErr = ApproveHandover(a, old, policies...)
+		if oldErr == nil && peer.Version != new.Version {
+			t.Errorf("旧进程应收到新进程的信息，实际为%+v", peer)
+		}
+	}()
+	var peer ProcessInfo
+	peer, newErr = ProposeHandover(b, new)
+	<-done
+	if peer.Version != old.Version {
+		t.Errorf("新进程应收到旧进程的信息，实际为%+v", peer)
+	}
+	return oldErr, newErr
+}
+
+// TestHandoverApproved 测试满足策略时握手成功
+func TestHandoverApproved(t *testing.T) {
+	oldErr, newErr := handshake(t,
+		ProcessInfo{Version: "v1.4.2", Schema: 3},
+		ProcessInfo{Version: "v1.10.0-rc1", Schema: 3, Capabilities: []string{"fd-passing"}},
+		RefuseDowngrade(), RequireSchema(nil), RequireCapabilities("fd-passing"),
+	)
+	if oldErr != nil || newErr != nil {
+		t.Errorf("握手应成功，实际为%v和%v", oldErr, newErr)
+	}
+}
+
+// TestHandoverRefused 测试违反策略时双方都收到拒绝
+func TestHandoverRefused(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new ProcessInfo
+		policy   HandoverPolicy
+	}{
+		{"降级", ProcessInfo{Version: "v2.0.0"}, ProcessInfo{Version: "v1.9.9"}, RefuseDowngrade()},
+		{"模式不兼容", ProcessInfo{Version: "1", Schema: 3}, ProcessInfo{Version: "1", Schema: 4}, RequireSchema(nil)},
+		{"缺少能力", ProcessInfo{Version: "1"}, ProcessInfo{Version: "1"}, RequireCapabilities("fd-passing")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldErr, newErr := handshake(t, tt.old, tt.new, tt.policy)
+			if !errors.Is(oldErr, ErrHandoverRefused) || !errors.Is(newErr, ErrHandoverRefused) {
+				t.Errorf("双方都应收到ErrHandoverRefused，实际为%v和%v", oldErr, newErr)
+			}
+		})
+	}
+}
+
+// TestHandoverClosed 测试对端关闭时返回错误
+func TestHandoverClosed(t *testing.T) {
+	a, b := net.Pipe()
+	b.Close()
+	if _, err := ApproveHandover(a, ProcessInfo{}); !errors.Is(err, io.EOF) {
+		t.Errorf("对端关闭时应返回错误，实际为%v", err)
+	}
+}
@@ -0,0 +1,172 @@
+package graceful
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HijackedConns tracks connections taken over from net/http with
+// http.Hijacker, such as WebSocket connections. http.Server.Shutdown ignores
+// hijacked connections, so without tracking they are cut off abruptly when
+// the process exits.
+type HijackedConns struct {
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{}     // Open hijacked connections
+	closing bool                      // Set once the drain has started
+	idle    chan struct{}             // Closed when the last connection closes while closing
+	grace   time.Duration             // Time connections get to close after notify
+	notify  func(conn net.Conn) error // Asks a connection to close, e.g. with a close frame
+}
+
+// HijackedConns creates a registry of hijacked connections. When shutdown
+// begins, notify is called for every open connection so the protocol can say
+// goodbye, for example by sending a WebSocket close frame; connections still
+// open after grace, or when the shutdown deadline expires, are closed
+// forcibly. New hijacks are refused once shutdown has begun.
+//
+// Example:
+//
+//	conns := manager.HijackedConns(5*time.Second, func(conn net.Conn) error {
+//		// WebSocket close frame, status 1001 (going away)
+//		_, err := conn.Write([]byte{0x88, 0x02, 0x03, 0xe9})
+//		return err
+//	})
+//	srv := &http.Server{Handler: conns.Middleware(mux)}
+func (m *Manager) HijackedConns(grace time.Duration, notify func(conn net.Conn) error) *HijackedConns {
+	h := &HijackedConns{conns: make(map[net.Conn]struct{}), grace: grace, notify: notify}
+	m.OnDrain(h.close)
+	return h
+}
+
+// Middleware returns an HTTP handler that passes requests to next and tracks
+// the connections its handlers hijack.
+func (h *HijackedConns) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&hijackWriter{ResponseWriter: w, conns: h}, r)
+	})
+}
+
+// Track starts tracking a connection hijacked without Middleware. The
+// returned connection must be used in place of conn, so that closing it stops
+// the tracking. Track closes conn and returns ErrDraining once shutdown has
+// begun.
+func (h *HijackedConns) Track(conn net.Conn) (net.Conn, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closing {
+		_ = conn.Close()
+		return nil, ErrDraining
+	}
+	tc := &trackedConn{Conn: conn, conns: h}
+	h.conns[tc] = struct{}{}
+	return tc, nil
+}
+
+// Len returns the number of open hijacked connections.
+func (h *HijackedConns) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.conns)
+}
+
+// remove stops tracking conn.
+func (h *HijackedConns) remove(conn net.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.conns, conn)
+	if len(h.conns) == 0 && h.idle != nil {
+		close(h.idle)
+		h.idle = nil
+	}
+}
+
+// close notifies every connection, waits for them to close within the grace
+// period, and closes the remaining ones.
+func (h *HijackedConns) close(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	conns := make([]net.Conn, 0, len(h.conns))
+	for conn := range h.conns {
+		conns = append(conns, conn)
+	}
+	if len(conns) > 0 {
+		h.idle = make(chan struct{})
+	}
+	idle := h.idle
+	h.mu.Unlock()
+
+	if len(conns) == 0 {
+		return nil
+	}
+	if h.notify != nil {
+		for _, conn := range conns {
+			_ = h.notify(conn)
+		}
+	}
+
+	timer := time.NewTimer(h.grace)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+	return nil
+}
+
+// hijackWriter tracks the connection when a handler hijacks it.
+type hijackWriter struct {
+	http.ResponseWriter
+	conns *HijackedConns
+}
+
+// Hijack implements http.Hijacker.
+func (w *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	tc, err := w.conns.Track(conn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tc, rw, nil
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *hijackWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *hijackWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// trackedConn stops being tracked when it is closed.
+type trackedConn struct {
+	net.Conn
+	conns *HijackedConns
+	once  sync.Once
+}
+
+// Close closes the connection and stops tracking it.
+func (c *trackedConn) Close() error {
+	c.once.Do(func() { c.conns.remove(c) })
+	return c.Conn.Close()
+}
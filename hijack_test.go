@@ -0,0 +1,64 @@
+package graceful
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestHijackedConns 测试关闭时先通知被劫持的连接，超时后强制关闭
+func TestHijackedConns(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	notified := make(chan struct{}, 2)
+	conns := m.HijackedConns(time.Millisecond*100, func(conn net.Conn) error {
+		notified <- struct{}{}
+		_, err := conn.Write([]byte("bye\n"))
+		return err
+	})
+
+	srv := httptest.NewServer(conns.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("劫持连接失败: %v", err)
+			return
+		}
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\n")
+		_ = rw.Flush()
+		// 故意不关闭连接，由关闭流程强制关闭
+		_ = conn
+	})))
+	defer srv.Close()
+
+	client, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer client.Close()
+	_, _ = client.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\n\r\n"))
+	r := bufio.NewReader(client)
+	if line, _ := r.ReadString('\n'); line != "HTTP/1.1 101 Switching Protocols\r\n" {
+		t.Fatalf("应收到101响应，实际为%q", line)
+	}
+	_, _ = r.ReadString('\n')
+
+	if conns.Len() != 1 {
+		t.Fatalf("应跟踪1个连接，实际为%d", conns.Len())
+	}
+
+	m.Shutdown()
+	select {
+	case <-notified:
+	default:
+		t.Error("关闭时应通知被劫持的连接")
+	}
+	if line, _ := r.ReadString('\n'); line != "bye\n" {
+		t.Errorf("应收到通知消息，实际为%q", line)
+	}
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := r.ReadByte(); err == nil {
+		t.Error("宽限期后连接应被强制关闭")
+	}
+}
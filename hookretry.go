@@ -0,0 +1,92 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy bounds how often a failed shutdown hook is retried.
+type RetryPolicy struct {
+	Attempts int     // Maximum number of calls, including the first
+	Backoff  Backoff // Delay before each retry; nil retries immediately
+}
+
+// HookRetry records the attempts of a shutdown hook registered with
+// OnShutdownRetry, for the shutdown report.
+type HookRetry struct {
+	Name     string // Function name of the hook
+	Attempts int    // Number of calls made
+	Error    string // Error of the last attempt, or empty if the hook succeeded
+}
+
+// OnShutdownRetry registers a shutdown hook that is retried according to
+// policy when it fails, for idempotent cleanup such as deregistration or
+// final notifications, so that a transient network error does not skip it.
+// A retry is only attempted if its delay ends before the shutdown timeout.
+// The attempts are recorded in the shutdown report.
+//
+// Example:
+//
+//	manager.OnShutdownRetry(graceful.RetryPolicy{
+//		Attempts: 3,
+//		Backoff:  graceful.ExponentialBackoff(100*time.Millisecond, time.Second),
+//	}, registry.Deregister)
+func (m *Manager) OnShutdownRetry(policy RetryPolicy, f func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{fn: f, retry: &policy})
+}
+
+// callHook calls a shutdown hook, retrying it if it has a retry policy.
+func (m *Manager) callHook(ctx context.Context, h hook) error {
+	if h.retry == nil {
+		err := h.fn(ctx)
+		if err != nil {
+			m.logf("shutdown hook %s failed: %v", funcName(h.fn), err)
+		}
+		return err
+	}
+
+	record := HookRetry{Name: funcName(h.fn)}
+	var err error
+	for record.Attempts < h.retry.Attempts || record.Attempts == 0 {
+		if record.Attempts > 0 {
+			var delay time.Duration
+			if h.retry.Backoff != nil {
+				delay = h.retry.Backoff(record.Attempts)
+			}
+			if left, ok := RemainingBudget(ctx); ok && delay > left {
+				break
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		record.Attempts++
+		if err = h.fn(ctx); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		record.Error = err.Error()
+		m.logf("shutdown hook %s failed after %d attempts: %v", record.Name, record.Attempts, err)
+	}
+
+	m.mu.Lock()
+	m.hookRetries = append(m.hookRetries, record)
+	m.mu.Unlock()
+	return err
+}
+
+// retriedHooks returns the recorded attempts of retried hooks.
+func (m *Manager) retriedHooks() []HookRetry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]HookRetry(nil), m.hookRetries...)
+}
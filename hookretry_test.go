@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestOnShutdownRetry 测试失败的关闭钩子按策略重试
+func TestOnShutdownRetry(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var calls int
+	m.OnShutdownRetry(RetryPolicy{Attempts: 3, Backoff: ConstantBackoff(time.Millisecond)}, func(ctx context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("暂时性错误")
+		}
+		return nil
+	})
+	var failing int
+	m.OnShutdownRetry(RetryPolicy{Attempts: 2}, func(ctx context.Context) error {
+		failing++
+		return errors.New("持续错误")
+	})
+	m.Shutdown()
+
+	if calls != 2 {
+		t.Errorf("成功前应调用2次，实际为%d", calls)
+	}
+	if failing != 2 {
+		t.Errorf("应最多调用2次，实际为%d", failing)
+	}
+
+	retries := m.retriedHooks()
+	if len(retries) != 2 {
+		t.Fatalf("应记录2个钩子，实际为%d", len(retries))
+	}
+	// 钩子按注册的逆序运行
+	if retries[0].Attempts != 2 || retries[0].Error == "" {
+		t.Errorf("失败钩子的记录不正确: %+v", retries[0])
+	}
+	if retries[1].Attempts != 2 || retries[1].Error != "" {
+		t.Errorf("成功钩子的记录不正确: %+v", retries[1])
+	}
+}
+
+// TestOnShutdownRetryBudget 测试重试不会超出关闭超时
+func TestOnShutdownRetryBudget(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond * 100))
+
+	var calls int
+	m.OnShutdownRetry(RetryPolicy{Attempts: 10, Backoff: ConstantBackoff(time.Second)}, func(ctx context.Context) error {
+		calls++
+		return errors.New("失败")
+	})
+	start := time.Now()
+	m.Shutdown()
+
+	if calls != 1 {
+		t.Errorf("延迟超出预算时不应重试，实际调用%d次", calls)
+	}
+	if time.Since(start) > time.Millisecond*500 {
+		t.Error("重试不应超出关闭超时")
+	}
+}
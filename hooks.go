@@ -0,0 +1,126 @@
+package graceful
+
+import (
+	"context"
+	"sort"
+)
+
+// hook is a cleanup function registered with OnShutdown or OnShutdownPriority.
+type hook struct {
+	priority int                             // Higher priorities run first
+	fn       func(ctx context.Context) error // Cleanup function
+	class    DependencyClass                 // Dependency class, if registered with OnShutdownClass
+	retry    *RetryPolicy                    // Retry policy, if registered with OnShutdownRetry
+}
+
+// OnShutdown registers a cleanup function to run once during shutdown, after
+// managed goroutines have exited or the timeout has expired. Hooks run one at
+// a time in reverse registration order, so resources are released in the
+// opposite order they were acquired. Each hook receives a context bounded by
+// the remaining shutdown timeout. A hook that returns an error is logged and
+// does not prevent the remaining hooks from running.
+//
+// OnShutdown is equivalent to OnShutdownPriority with priority 0.
+//
+// Example:
+//
+//	db := openDB()
+//	manager.OnShutdown(func(ctx context.Context) error {
+//		return db.Close()
+//	})
+func (m *Manager) OnShutdown(f func(ctx context.Context) error) {
+	m.OnShutdownPriority(0, f)
+}
+
+// OnShutdownPriority registers a shutdown hook with an explicit priority.
+// Hooks with a higher priority run before hooks with a lower one; hooks with
+// equal priority keep the reverse registration order used by OnShutdown.
+// This lets libraries position their cleanup relative to application hooks
+// without knowing when those were registered.
+//
+// Example:
+//
+//	// Deregister from service discovery before any other cleanup
+//	manager.OnShutdownPriority(100, registry.Deregister)
+func (m *Manager) OnShutdownPriority(priority int, f func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{priority: priority, fn: f})
+}
+
+// OnDrain registers a function to run when shutdown begins, before managed
+// goroutines are canceled. Drain functions run in registration order and are
+// meant for stopping intake, such as closing listeners or pausing consumers,
+// while in-flight work can still complete. Each receives a context bounded by
+// the shutdown timeout. Like shutdown hooks, those registered from the start
+// functions passed to Run also run on Restart and Rehearse, while those
+// registered before Run only run at the final shutdown.
+//
+// Example:
+//
+//	manager.OnDrain(func(ctx context.Context) error {
+//		return consumer.StopFetching(ctx)
+//	})
+func (m *Manager) OnDrain(f func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drainers = append(m.drainers, f)
+}
+
+// runDrainers runs the registered drain functions in order and clears them;
+// a restart only runs and clears those of the running generation.
+func (m *Manager) runDrainers(ctx context.Context) {
+	m.mu.Lock()
+	drainers := takeRegistrations(&m.drainers, m.marks().drainers, restarting(ctx))
+	m.mu.Unlock()
+
+	for _, f := range drainers {
+		_ = f(ctx)
+	}
+}
+
+// runHooks runs the registered shutdown hooks in order and clears them, so
+// that each hook runs at most once; a restart only runs and clears those of
+// the running generation. An error returned by one hook does not prevent the
+// remaining ones from running. Hooks of a dependency class with limits run
+// together where the first of them is reached.
+func (m *Manager) runHooks(ctx context.Context) {
+	m.mu.Lock()
+	hooks := takeRegistrations(&m.hooks, m.marks().hooks, restarting(ctx))
+	m.mu.Unlock()
+
+	ordered := orderHooks(hooks)
+	ran := make(map[DependencyClass]bool)
+	for _, h := range ordered {
+		limits, limited := m.classes[h.class]
+		if !limited {
+			_ = m.runHook(ctx, h)
+			continue
+		}
+		if ran[h.class] {
+			continue
+		}
+		ran[h.class] = true
+
+		var class []hook
+		for _, c := range ordered {
+			if c.class == h.class {
+				class = append(class, c)
+			}
+		}
+		m.runClass(ctx, limits, class)
+	}
+}
+
+// orderHooks returns hooks sorted by descending priority, with hooks of equal
+// priority in reverse registration order.
+func orderHooks(hooks []hook) []hook {
+	ordered := make([]hook, len(hooks))
+	for i, h := range hooks {
+		ordered[len(hooks)-1-i] = h
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].priority > ordered[j].priority
+	})
+	return ordered
+}
@@ -0,0 +1,117 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestShutdownHookOrder 测试关闭钩子按优先级和后进先出顺序执行
+func TestShutdownHookOrder(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var order []string
+	add := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	m.OnShutdown(add("a"))
+	m.OnShutdownPriority(10, add("high1"))
+	m.OnShutdown(add("b"))
+	m.OnShutdownPriority(-1, add("low"))
+	m.OnShutdownPriority(10, add("high2"))
+
+	m.Shutdown()
+	m.Shutdown()
+
+	expected := []string{"high2", "high1", "b", "a", "low"}
+	if len(order) != len(expected) {
+		t.Fatalf("钩子执行顺序应为%v，实际为%v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("钩子执行顺序应为%v，实际为%v", expected, order)
+		}
+	}
+}
+
+// TestShutdownHookError 测试钩子出错时记录日志并继续执行其余钩子
+func TestShutdownHookError(t *testing.T) {
+	logger := &recordingLogger{}
+	m := New(WithTimeout(time.Second), WithLogger(logger))
+
+	ran := false
+	m.OnShutdown(func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	m.OnShutdown(func(ctx context.Context) error {
+		return errors.New("pool busy")
+	})
+	m.Shutdown()
+
+	if !ran {
+		t.Error("钩子出错后应继续执行其余钩子")
+	}
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "pool busy") {
+		t.Errorf("钩子出错时应记录日志，实际为%v", logger.lines)
+	}
+}
+
+// TestShutdownHookAfterGoroutines 测试关闭钩子在goroutine退出后执行且共享超时
+func TestShutdownHookAfterGoroutines(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond * 100))
+
+	exited := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 30)
+		close(exited)
+	})
+
+	var remaining time.Duration
+	m.OnShutdown(func(ctx context.Context) error {
+		select {
+		case <-exited:
+		default:
+			t.Error("关闭钩子应在goroutine退出后执行")
+		}
+		deadline, _ := ctx.Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	})
+
+	m.Shutdown()
+
+	if remaining <= 0 || remaining > time.Millisecond*75 {
+		t.Errorf("关闭钩子应获得剩余的超时时间，实际为%v", remaining)
+	}
+}
+
+// TestOnDrain 测试drain函数在goroutine取消前执行
+func TestOnDrain(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	task := m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+
+	drained := false
+	m.OnDrain(func(ctx context.Context) error {
+		drained = true
+		if task.Context().Err() != nil {
+			t.Error("drain函数应在goroutine取消前执行")
+		}
+		return nil
+	})
+
+	m.Shutdown()
+	if !drained {
+		t.Error("关闭时应执行drain函数")
+	}
+}
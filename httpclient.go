@@ -0,0 +1,97 @@
+package graceful
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Transport is an http.RoundTripper that tracks outbound requests so that
+// shutdown can wait for them. A request counts as in flight until its response
+// body has been read to the end or closed.
+type Transport struct {
+	base     http.RoundTripper
+	inFlight inFlight
+}
+
+// ManageTransport wraps base, or http.DefaultTransport if base is nil, and
+// registers a shutdown hook that waits for in-flight requests, bounded by the
+// remaining shutdown timeout, and then closes idle connections. Requests
+// started after the hook has begun fail with ErrClientClosing.
+//
+// Because hooks run after managed goroutines have exited, fire-and-forget
+// calls made by those goroutines are not severed mid-body when the process
+// exits.
+//
+// Example:
+//
+//	client := &http.Client{Transport: manager.ManageTransport(nil)}
+func (m *Manager) ManageTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{base: base}
+	m.OnShutdown(t.close)
+	return t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.inFlight.begin() {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, ErrClientClosing
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		t.inFlight.end()
+		return resp, err
+	}
+	resp.Body = &trackedBody{ReadCloser: resp.Body, end: t.inFlight.end}
+	return resp, nil
+}
+
+// InFlight returns the number of requests whose responses have not been
+// fully consumed yet.
+func (t *Transport) InFlight() int {
+	return t.inFlight.len()
+}
+
+// CloseIdleConnections closes idle connections of the wrapped transport, if
+// it supports doing so.
+func (t *Transport) CloseIdleConnections() {
+	if c, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// close waits for in-flight requests and then closes idle connections.
+func (t *Transport) close(ctx context.Context) error {
+	err := t.inFlight.close(ctx)
+	t.CloseIdleConnections()
+	return err
+}
+
+// trackedBody ends an in-flight request when the body is exhausted or closed.
+type trackedBody struct {
+	io.ReadCloser
+	end  func()
+	once sync.Once
+}
+
+func (b *trackedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.once.Do(b.end)
+	}
+	return n, err
+}
+
+func (b *trackedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.end)
+	return err
+}
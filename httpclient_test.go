@@ -0,0 +1,61 @@
+package graceful
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestManageTransport 测试关闭时等待进行中的出站请求
+func TestManageTransport(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = io.WriteString(w, "done")
+	}))
+	defer srv.Close()
+
+	m := New(WithTimeout(time.Second))
+	transport := m.ManageTransport(nil)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if transport.InFlight() != 1 {
+		t.Errorf("响应体未读完时进行中请求数应为1，实际为%d", transport.InFlight())
+	}
+
+	body := make(chan string, 1)
+	go func() {
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		body <- string(data)
+	}()
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		m.Shutdown()
+		close(shutdownDone)
+	}()
+
+	select {
+	case <-shutdownDone:
+		t.Fatal("进行中请求完成前不应结束关闭")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	close(release)
+	if got := <-body; got != "done" {
+		t.Errorf("响应体应完整读取，实际为%q", got)
+	}
+	<-shutdownDone
+
+	if _, err := client.Get(srv.URL); err == nil {
+		t.Error("关闭后新请求应失败")
+	}
+}
@@ -0,0 +1,199 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ServerOption configures an HTTP server registered with HTTPServer.
+type ServerOption func(*httpServer)
+
+// WithDrainOrder returns a ServerOption that sets when the server is shut
+// down relative to the other servers registered with HTTPServer. Servers with
+// a lower order are shut down first, and servers with the same order are shut
+// down concurrently. The default order is 0.
+//
+// Example:
+//
+//	manager.HTTPServer(public, publicLn)
+//	manager.HTTPServer(admin, adminLn, graceful.WithDrainOrder(1))
+func WithDrainOrder(order int) ServerOption {
+	return func(s *httpServer) {
+		s.order = order
+	}
+}
+
+// ShutdownLast returns a ServerOption that keeps the server serving until
+// every other shutdown hook has run, instead of shutting it down with the
+// other servers when shutdown begins. It is meant for metrics endpoints, so
+// that the last scrape captures the shutdown itself.
+//
+// Example:
+//
+//	manager.HTTPServer(metrics, metricsLn, graceful.ShutdownLast())
+func ShutdownLast() ServerOption {
+	return func(s *httpServer) {
+		s.last = true
+	}
+}
+
+// httpServer is an HTTP server registered with HTTPServer.
+type httpServer struct {
+	srv   *http.Server
+	order int  // Shutdown order among the servers; lower first
+	last  bool // Whether the server is shut down after the shutdown hooks
+
+	generation bool // Registered by the running generation rather than before Run
+
+	serving     chan struct{} // Closed once Serve tracks the listener or has returned
+	servingOnce sync.Once
+}
+
+// markServing records that Serve has started, or returned without serving.
+func (s *httpServer) markServing() {
+	s.servingOnce.Do(func() { close(s.serving) })
+}
+
+// shutdown waits for Serve to start, so that its listener is closed now
+// rather than whenever Serve gets to run, then shuts the server down.
+func (s *httpServer) shutdown(ctx context.Context) error {
+	select {
+	case <-s.serving:
+	case <-ctx.Done():
+	}
+	return s.srv.Shutdown(ctx)
+}
+
+// HTTPServer serves srv on ln and shuts it down gracefully during shutdown.
+// Servers are shut down in their drain order, all under the shutdown
+// timeout, when shutdown begins; servers registered with ShutdownLast are
+// shut down after all other shutdown hooks instead. If Serve fails for a
+// reason other than the server being shut down, the manager shuts down.
+//
+// With a nil ln, the server listens on srv.Addr, or ":http" if it is empty,
+// like ListenAndServe. If srv.TLSConfig has certificates or a
+// GetCertificate function, the server serves TLS. The listener of a server
+// that is shut down last must not come from Listen, which closes its
+// listeners as soon as shutdown begins.
+//
+// Example:
+//
+//	ln, err := manager.Listen("tcp", ":8080")
+//	if err != nil {
+//		return err
+//	}
+//	manager.HTTPServer(&http.Server{Handler: mux}, ln)
+func (m *Manager) HTTPServer(srv *http.Server, ln net.Listener, opts ...ServerOption) {
+	s := &httpServer{srv: srv, serving: make(chan struct{})}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// Serve calls BaseContext once the listener is tracked, after which
+	// Shutdown closes it immediately
+	base := srv.BaseContext
+	srv.BaseContext = func(l net.Listener) context.Context {
+		s.markServing()
+		if base != nil {
+			return base(l)
+		}
+		return context.Background()
+	}
+
+	if s.last {
+		m.OnShutdownPriority(math.MinInt, s.shutdown)
+	} else {
+		// Servers registered before Run and those of the running generation
+		// are shut down by separate drain functions, so that a restart only
+		// shuts down the latter
+		m.mu.Lock()
+		s.generation = m.generation != nil
+		first := true
+		for _, other := range m.servers {
+			if other.generation == s.generation {
+				first = false
+			}
+		}
+		m.servers = append(m.servers, s)
+		m.mu.Unlock()
+		if first {
+			m.OnDrain(m.shutdownServers)
+		}
+	}
+
+	go func() {
+		defer s.markServing()
+		if err := serve(srv, ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.logf("http server on %s failed: %v", serverAddr(srv, ln), err)
+			m.requestStop(err)
+		}
+	}()
+}
+
+// serve serves srv on ln, or on srv.Addr if ln is nil, using TLS if srv is
+// configured with certificates.
+func serve(srv *http.Server, ln net.Listener) error {
+	tls := srv.TLSConfig != nil && (len(srv.TLSConfig.Certificates) > 0 || srv.TLSConfig.GetCertificate != nil)
+	switch {
+	case ln == nil && tls:
+		return srv.ListenAndServeTLS("", "")
+	case ln == nil:
+		return srv.ListenAndServe()
+	case tls:
+		return srv.ServeTLS(ln, "", "")
+	}
+	return srv.Serve(ln)
+}
+
+// serverAddr describes where srv listens, for log messages.
+func serverAddr(srv *http.Server, ln net.Listener) string {
+	if ln != nil {
+		return ln.Addr().String()
+	}
+	if srv.Addr == "" {
+		return ":http"
+	}
+	return srv.Addr
+}
+
+// shutdownServers shuts down the servers registered with HTTPServer in their
+// drain order, concurrently within each order, and clears them so that
+// servers registered after a restart are shut down by a new drain function.
+// A restart only shuts down the servers of the running generation.
+func (m *Manager) shutdownServers(ctx context.Context) error {
+	restart := restarting(ctx)
+	m.mu.Lock()
+	var servers, kept []*httpServer
+	for _, s := range m.servers {
+		if restart && !s.generation {
+			kept = append(kept, s)
+		} else {
+			servers = append(servers, s)
+		}
+	}
+	m.servers = kept
+	m.mu.Unlock()
+
+	sort.SliceStable(servers, func(i, j int) bool {
+		return servers[i].order < servers[j].order
+	})
+	for i := 0; i < len(servers); {
+		j := i
+		var wg sync.WaitGroup
+		for ; j < len(servers) && servers[j].order == servers[i].order; j++ {
+			wg.Add(1)
+			go func(s *httpServer) {
+				defer wg.Done()
+				_ = s.shutdown(ctx)
+			}(servers[j])
+		}
+		wg.Wait()
+		i = j
+	}
+	return nil
+}
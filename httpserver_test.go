@@ -0,0 +1,126 @@
+package graceful
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestHTTPServerOrder 测试多个HTTP服务器按顺序关闭，指标服务器最后关闭
+func TestHTTPServerOrder(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+		}
+	}
+
+	serve := func(name string, opts ...ServerOption) string {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("监听失败: %v", err)
+		}
+		srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})}
+		m.HTTPServer(srv, &recordingListener{Listener: ln, closed: record(name)}, opts...)
+		return ln.Addr().String()
+	}
+	serve("admin", WithDrainOrder(1))
+	metrics := serve("metrics", ShutdownLast())
+	serve("public")
+
+	var scraped bool
+	m.OnShutdown(func(ctx context.Context) error {
+		record("hook")()
+		resp, err := http.Get("http://" + metrics)
+		if err == nil {
+			resp.Body.Close()
+			scraped = true
+		}
+		return nil
+	})
+	m.Shutdown()
+
+	want := []string{"public", "admin", "hook", "metrics"}
+	if len(order) != len(want) {
+		t.Fatalf("关闭顺序应为%v，实际为%v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("关闭顺序应为%v，实际为%v", want, order)
+		}
+	}
+	if !scraped {
+		t.Error("关闭钩子运行时指标服务器应仍可访问")
+	}
+}
+
+// recordingListener 在关闭时记录
+type recordingListener struct {
+	net.Listener
+	once   sync.Once
+	closed func()
+}
+
+func (l *recordingListener) Close() error {
+	l.once.Do(l.closed)
+	return l.Listener.Close()
+}
+
+// TestHTTPServerListenError 测试未提供监听器时监听srv.Addr，失败则停止管理器
+func TestHTTPServerListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer ln.Close()
+
+	m := New(WithTimeout(time.Second))
+	m.Go(func() {})
+	m.HTTPServer(&http.Server{Addr: ln.Addr().String()}, nil)
+
+	done := make(chan error, 1)
+	go func() { done <- m.Wait() }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("地址被占用时Wait应返回错误")
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("监听失败后管理器应停止")
+	}
+}
+
+// TestHTTPServerTLS 测试配置了证书的服务器使用TLS
+func TestHTTPServerTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	client := ts.Client()
+	certs := ts.TLS.Certificates
+	ts.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	m := New(WithTimeout(time.Second))
+	m.HTTPServer(&http.Server{
+		Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		TLSConfig: &tls.Config{Certificates: certs},
+	}, ln)
+	defer m.Shutdown()
+
+	resp, err := client.Get("https://" + ln.Addr().String())
+	if err != nil {
+		t.Fatalf("应能通过TLS访问服务器: %v", err)
+	}
+	resp.Body.Close()
+}
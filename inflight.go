@@ -0,0 +1,69 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+)
+
+// inFlight counts operations in progress and lets shutdown code wait for
+// them. Unlike sync.WaitGroup, waiting is bounded by a context and new
+// operations can be rejected once closing has begun.
+type inFlight struct {
+	mu      sync.Mutex
+	count   int           // Operations in progress
+	closing bool          // Set once new operations are rejected
+	idle    chan struct{} // Closed when count drops to zero while closing
+}
+
+// begin records the start of an operation. It returns false if closing has
+// begun, in which case end must not be called.
+func (f *inFlight) begin() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closing {
+		return false
+	}
+	f.count++
+	return true
+}
+
+// end records the end of an operation started with begin.
+func (f *inFlight) end() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count--
+	if f.count == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// len returns the number of operations in progress.
+func (f *inFlight) len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// close rejects new operations and waits until the ones in progress have
+// ended or ctx is done, returning ctx's error in the latter case.
+func (f *inFlight) close(ctx context.Context) error {
+	f.mu.Lock()
+	f.closing = true
+	if f.count == 0 {
+		f.mu.Unlock()
+		return nil
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	idle := f.idle
+	f.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
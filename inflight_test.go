@@ -0,0 +1,58 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestInFlight 测试进行中操作计数及关闭等待
+func TestInFlight(t *testing.T) {
+	var f inFlight
+
+	if !f.begin() {
+		t.Fatal("关闭前应允许新操作")
+	}
+	if f.len() != 1 {
+		t.Errorf("进行中操作数应为1，实际为%d", f.len())
+	}
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- f.close(context.Background())
+	}()
+
+	time.Sleep(time.Millisecond * 20)
+	if f.begin() {
+		t.Error("关闭开始后应拒绝新操作")
+	}
+
+	select {
+	case <-closed:
+		t.Fatal("仍有进行中操作时close不应返回")
+	default:
+	}
+
+	f.end()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("操作结束后close应返回nil，实际为%v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("操作结束后close未返回")
+	}
+}
+
+// TestInFlightCloseTimeout 测试关闭等待受上下文限制
+func TestInFlightCloseTimeout(t *testing.T) {
+	var f inFlight
+	f.begin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	if err := f.close(ctx); err != context.DeadlineExceeded {
+		t.Errorf("超时时应返回DeadlineExceeded，实际为%v", err)
+	}
+}
@@ -0,0 +1,6 @@
+package graceful
+
+// initSignalOffset is added to the number of the signal that killed the
+// main child to form the exit code in init mode, following the shell
+// convention.
+const initSignalOffset = 128
@@ -0,0 +1,11 @@
+//go:build !unix || aix
+
+package graceful
+
+// RunInit reports that init mode is not available and exits with the
+// StartupFailure code. Init mode is only available on Unix systems other than
+// AIX.
+func (m *Manager) RunInit(name string, args ...string) {
+	m.logf("init mode is not supported on this platform")
+	m.exit(outcome{startupFailure: true, timedOut: m.shutdownTimedOut()})
+}
@@ -0,0 +1,113 @@
+//go:build unix && !aix
+
+package graceful
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// forwardedSignals are the signals init mode passes to the main child in
+// addition to the shutdown signals.
+var forwardedSignals = []os.Signal{
+	syscall.SIGHUP, syscall.SIGQUIT, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGWINCH,
+}
+
+// RunInit runs the manager as a container entrypoint, like tini: it starts
+// the program name with args as its main child, reaps every process that
+// exits, including orphans reparented to it, forwards signals to the child,
+// and when the child exits, shuts down gracefully and exits with the child's
+// exit code, or 128 plus the signal number if a signal killed it.
+//
+// The shutdown signals and SIGHUP, SIGQUIT, SIGUSR1, SIGUSR2 and SIGWINCH
+// are forwarded. After a shutdown signal, the child has the shutdown timeout
+// to exit before it is killed. When the manager is not PID 1 on Linux, it
+// registers as a child subreaper so that orphaned descendants are still
+// adopted and reaped.
+//
+// If the child cannot be started, RunInit exits with the StartupFailure
+// code. RunInit never returns, and is only available on Unix systems other
+// than AIX.
+//
+// Example:
+//
+//	func main() {
+//		manager := graceful.New(graceful.WithTimeout(20 * time.Second))
+//		manager.RunInit(os.Args[1], os.Args[2:]...)
+//	}
+func (m *Manager) RunInit(name string, args ...string) {
+	if !m.childReaper {
+		m.childReaper = true
+		m.startReaping()
+	}
+	if os.Getpid() != 1 {
+		if err := setSubreaper(); err != nil {
+			m.logf("registering as child subreaper failed: %v", err)
+		}
+	}
+
+	code, err := m.runInit(name, args...)
+	if err != nil {
+		m.logf("starting %s failed: %v", name, err)
+		m.exit(outcome{startupFailure: true, timedOut: m.shutdownTimedOut()})
+		return
+	}
+	m.shutdownTimedOut()
+	m.runFinal(code)
+	exit(code)
+}
+
+// runInit starts the main child, forwards signals to it until it exits and
+// returns the exit code derived from its status.
+func (m *Manager) runInit(name string, args ...string) (int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	sigCh, stop := m.notifySignals(append(append([]os.Signal(nil), m.signals...), forwardedSignals...))
+	defer stop()
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+
+	exited := make(chan ChildExit, 1)
+	go func() {
+		status, _ := m.WaitChild(context.Background(), cmd.Process.Pid)
+		exited <- status
+	}()
+
+	var kill <-chan time.Time
+	for {
+		select {
+		case sig := <-sigCh:
+			_ = cmd.Process.Signal(sig)
+			if kill == nil && m.isShutdownSignal(sig) {
+				timer := time.NewTimer(m.timeout)
+				defer timer.Stop()
+				kill = timer.C
+			}
+		case <-kill:
+			m.logf("%s did not exit within %v, killing it", name, m.timeout)
+			_ = cmd.Process.Kill()
+		case status := <-exited:
+			if status.Signal != nil {
+				if n, ok := signalNumber(status.Signal); ok {
+					return initSignalOffset + n, nil
+				}
+			}
+			return status.ExitCode, nil
+		}
+	}
+}
+
+// isShutdownSignal reports whether sig is one of the shutdown signals.
+func (m *Manager) isShutdownSignal(sig os.Signal) bool {
+	for _, s := range m.signals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+//go:build unix && !aix
+
+package graceful
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// pollingReaper 让管理器通过轮询回收子进程，以免安装全局SIGCHLD处理
+func pollingReaper(t *testing.T, m *Manager) {
+	m.childReaper = true
+	m.childExits = make(map[int]ChildExit)
+	m.childReaped = make(chan struct{})
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond * 5):
+				m.reapChildren()
+			}
+		}
+	}()
+}
+
+// TestRunInitExitCode 测试init模式以主子进程的退出码退出
+func TestRunInitExitCode(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	pollingReaper(t, m)
+
+	code, err := m.runInit("sh", "-c", "exit 7")
+	if err != nil {
+		t.Skipf("无法启动子进程: %v", err)
+	}
+	if code != 7 {
+		t.Errorf("退出码应为7，实际为%d", code)
+	}
+}
+
+// TestRunInitForwardSignal 测试init模式把信号转发给主子进程
+func TestRunInitForwardSignal(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	pollingReaper(t, m)
+
+	go func() {
+		time.Sleep(time.Millisecond * 200)
+		syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+	}()
+	code, err := m.runInit("sleep", "10")
+	if err != nil {
+		t.Skipf("无法启动子进程: %v", err)
+	}
+	if want := initSignalOffset + int(syscall.SIGUSR1); code != want {
+		t.Errorf("被SIGUSR1终止时退出码应为%d，实际为%d", want, code)
+	}
+}
+
+// TestRunInitStartFailure 测试主程序无法启动时返回错误
+func TestRunInitStartFailure(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	pollingReaper(t, m)
+
+	if _, err := m.runInit("/nonexistent/program"); err == nil {
+		t.Error("主程序不存在时应返回错误")
+	}
+}
@@ -0,0 +1,66 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// JobLock is a distributed lock that lets one instance among several run a
+// scheduled job, such as a lease in etcd, Consul, Redis or a database row.
+type JobLock interface {
+	// TryLock acquires the lease on key, or renews it if this instance
+	// already holds it, and reports whether this instance holds it.
+	TryLock(ctx context.Context, key string) (bool, error)
+	// Unlock releases the lease on key so that another instance can take it.
+	Unlock(ctx context.Context, key string) error
+}
+
+// EveryLocked is like Every, but runs f only on the instance holding the
+// lease on key, trying to acquire or renew it before each run. When shutdown
+// begins, the job stops being scheduled, a run in progress finishes, and the
+// lease is released right away instead of being left to expire, so a peer
+// instance can take over scheduling without a gap. The release is bounded by
+// the remaining shutdown budget.
+//
+// Example:
+//
+//	manager.EveryLocked(time.Minute, redisLock, "jobs/billing", func(ctx context.Context) {
+//		runBilling(ctx)
+//	})
+func (m *Manager) EveryLocked(interval time.Duration, lock JobLock, key string, f func(ctx context.Context)) {
+	m.CtxGo(func(ctx context.Context) {
+		held := false
+		defer func() {
+			if held {
+				m.releaseLease(ctx, lock, key)
+			}
+		}()
+
+		// Stop scheduling as soon as shutdown begins
+		intake := m.AttachContext(ctx)
+		m.runEvery(ctx, intake, interval, func(ctx context.Context) {
+			var err error
+			held, err = lock.TryLock(intake, key)
+			if err != nil {
+				m.logf("acquiring the lease on %s failed: %v", key, err)
+			}
+			if held && err == nil {
+				f(ctx)
+			}
+		})
+	})
+}
+
+// releaseLease releases the lease on key within the remaining shutdown
+// budget, or the shutdown timeout if the budget is unknown.
+func (m *Manager) releaseLease(ctx context.Context, lock JobLock, key string) {
+	budget, ok := RemainingBudget(ctx)
+	if !ok || budget <= 0 {
+		budget = m.timeout
+	}
+	rctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+	if err := lock.Unlock(rctx, key); err != nil {
+		m.logf("releasing the lease on %s failed: %v", key, err)
+	}
+}
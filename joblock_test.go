@@ -0,0 +1,124 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeJobLock 是记录调用的分布式锁
+type fakeJobLock struct {
+	mu       sync.Mutex
+	holder   bool
+	deny     bool
+	err      error
+	tries    int
+	unlocked []string
+}
+
+func (l *fakeJobLock) TryLock(ctx context.Context, key string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tries++
+	if l.err != nil {
+		return false, l.err
+	}
+	l.holder = !l.deny
+	return l.holder, nil
+}
+
+func (l *fakeJobLock) Unlock(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.holder = false
+	l.unlocked = append(l.unlocked, key)
+	return nil
+}
+
+// TestEveryLockedReleasesLease 测试关闭时在运行中的任务完成后立即释放租约
+func TestEveryLockedReleasesLease(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	lock := &fakeJobLock{}
+
+	var count int32
+	started := make(chan struct{}, 1)
+	m.EveryLocked(time.Millisecond*10, lock, "jobs/billing", func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+	})
+
+	<-started
+	m.Shutdown()
+
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	if len(lock.unlocked) != 1 || lock.unlocked[0] != "jobs/billing" {
+		t.Errorf("关闭时应释放一次租约，实际为%v", lock.unlocked)
+	}
+	if lock.holder {
+		t.Error("关闭后不应持有租约")
+	}
+	if atomic.LoadInt32(&count) < 1 {
+		t.Error("持有租约时应执行任务")
+	}
+}
+
+// TestEveryLockedNotHeld 测试未获得租约时不执行任务也不释放
+func TestEveryLockedNotHeld(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	lock := &fakeJobLock{deny: true}
+
+	var count int32
+	m.EveryLocked(time.Millisecond*10, lock, "jobs/billing", func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+	})
+
+	waitFor(t, func() bool {
+		lock.mu.Lock()
+		defer lock.mu.Unlock()
+		return lock.tries >= 2
+	}, "应反复尝试获取租约")
+	m.Shutdown()
+
+	if n := atomic.LoadInt32(&count); n != 0 {
+		t.Errorf("未持有租约时不应执行任务，实际执行了%d次", n)
+	}
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	if len(lock.unlocked) != 0 {
+		t.Errorf("未持有租约时不应释放，实际为%v", lock.unlocked)
+	}
+}
+
+// TestEveryLockedError 测试获取租约出错时记录日志并跳过任务
+func TestEveryLockedError(t *testing.T) {
+	logger := &recordingLogger{}
+	m := New(WithTimeout(time.Second), WithLogger(logger))
+	lock := &fakeJobLock{err: errors.New("lease store unavailable")}
+
+	var count int32
+	m.EveryLocked(time.Millisecond*10, lock, "jobs/billing", func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+	})
+
+	waitFor(t, func() bool {
+		lock.mu.Lock()
+		defer lock.mu.Unlock()
+		return lock.tries >= 1
+	}, "应尝试获取租约")
+	m.Shutdown()
+
+	if n := atomic.LoadInt32(&count); n != 0 {
+		t.Errorf("获取租约失败时不应执行任务，实际执行了%d次", n)
+	}
+	if len(logger.lines) == 0 || !strings.Contains(logger.lines[0], "acquiring the lease on jobs/billing failed") {
+		t.Errorf("应记录获取租约失败的日志，实际为%v", logger.lines)
+	}
+}
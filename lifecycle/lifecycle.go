@@ -0,0 +1,123 @@
+// Package lifecycle is a context-first API over graceful.Manager, in the
+// shape planned for the next major version of graceful: every lifecycle
+// method takes a context, every operation returns an error, and tasks are
+// always func(ctx) error, so task failures and deadlines are reported
+// instead of being left to the caller.
+//
+// The package is a layer over graceful.Manager rather than a fork of it.
+// Legacy returns the underlying manager, so code written against Go, CtxGo,
+// Wait and Shutdown keeps working on the same manager during a migration,
+// and Wrap adopts a manager created by such code.
+//
+// Example:
+//
+//	func main() {
+//		m := lifecycle.New(graceful.WithTimeout(10 * time.Second))
+//		if err := m.Go(consume); err != nil {
+//			log.Fatal(err)
+//		}
+//		if err := m.Wait(context.Background()); err != nil {
+//			log.Print(err)
+//		}
+//	}
+package lifecycle
+
+import (
+	"context"
+
+	"github.com/kingcanfish/graceful"
+)
+
+// Task is a function run as a managed goroutine. A non-nil error other than
+// one caused by shutdown is reported as a *graceful.TaskError by Wait,
+// Shutdown and Stop.
+type Task func(ctx context.Context) error
+
+// Manager coordinates the lifecycle of tasks with context-first signatures.
+type Manager struct {
+	m *graceful.Manager
+}
+
+// New creates a Manager configured with the options of graceful.New.
+func New(opts ...graceful.Option) *Manager {
+	return Wrap(graceful.New(opts...))
+}
+
+// Wrap returns a Manager that drives m, for migrating code that already
+// created a graceful.Manager.
+func Wrap(m *graceful.Manager) *Manager {
+	return &Manager{m: m}
+}
+
+// Legacy returns the underlying graceful.Manager, for code that still uses
+// its Go, CtxGo, Wait and Shutdown methods or its other features.
+func (m *Manager) Legacy() *graceful.Manager {
+	return m.m
+}
+
+// Go starts f as a managed goroutine, like graceful.Manager.GoErr. It returns
+// graceful.ErrDraining without starting f once the manager's context has
+// been canceled.
+func (m *Manager) Go(f Task, opts ...graceful.TaskOption) error {
+	if m.m.Context().Err() != nil {
+		return graceful.ErrDraining
+	}
+	m.m.GoErr(f, opts...)
+	return nil
+}
+
+// OnShutdown registers f to run after managed goroutines have exited, like
+// graceful.Manager.OnShutdown.
+func (m *Manager) OnShutdown(f func(ctx context.Context) error) {
+	m.m.OnShutdown(f)
+}
+
+// Start runs the start hooks, startup checks and warm-up tasks, bounded by
+// ctx, and marks the manager ready, like graceful.Manager.Start.
+func (m *Manager) Start(ctx context.Context) error {
+	return m.m.Start(ctx)
+}
+
+// Wait blocks until a monitored signal is received, the manager stops itself
+// or ctx is done, then shuts down and returns what graceful.Manager.Wait
+// returns. A shutdown caused by ctx is recorded with the reason "context
+// done" and is not an error by itself.
+func (m *Manager) Wait(ctx context.Context) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = m.m.ShutdownWithReason("context done", nil)
+		case <-stop:
+		}
+	}()
+	return m.m.Wait()
+}
+
+// Shutdown shuts the manager down and returns what graceful.Manager.Shutdown
+// returns. If ctx is done first, Shutdown returns its error while the
+// shutdown continues under the manager's own timeout.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	return m.await(ctx, m.m.Shutdown)
+}
+
+// Stop ends a run begun with Start and returns what graceful.Manager.Stop
+// returns. If ctx is done first, Stop returns its error while the shutdown
+// continues under the manager's own timeout.
+func (m *Manager) Stop(ctx context.Context) error {
+	return m.await(ctx, m.m.Stop)
+}
+
+// await runs f in the background and returns its error, or the error of ctx
+// if ctx is done first.
+func (m *Manager) await(ctx context.Context, f func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- f() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,75 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kingcanfish/graceful"
+)
+
+// TestGoErrors 测试任务错误由Shutdown返回
+func TestGoErrors(t *testing.T) {
+	m := New(graceful.WithTimeout(time.Second))
+	cause := errors.New("消费失败")
+	if err := m.Go(func(ctx context.Context) error { return cause }, graceful.WithName("consumer")); err != nil {
+		t.Fatalf("启动任务失败: %v", err)
+	}
+	m.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	time.Sleep(time.Millisecond * 20)
+
+	err := m.Shutdown(context.Background())
+	var taskErr *graceful.TaskError
+	if !errors.As(err, &taskErr) || taskErr.Name != "consumer" || !errors.Is(err, cause) {
+		t.Errorf("Shutdown应返回任务错误，实际为%v", err)
+	}
+	if err := m.Go(func(ctx context.Context) error { return nil }); !errors.Is(err, graceful.ErrDraining) {
+		t.Errorf("关闭后启动任务应返回ErrDraining，实际为%v", err)
+	}
+}
+
+// TestWaitContext 测试上下文结束时Wait关闭并正常返回
+func TestWaitContext(t *testing.T) {
+	m := New(graceful.WithTimeout(time.Second))
+	exited := make(chan struct{})
+	m.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		close(exited)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	if err := m.Wait(ctx); err != nil {
+		t.Errorf("上下文结束引起的关闭不应返回错误，实际为%v", err)
+	}
+	select {
+	case <-exited:
+	default:
+		t.Error("Wait返回前任务应已退出")
+	}
+}
+
+// TestShutdownContext 测试上下文先结束时Shutdown返回上下文错误
+func TestShutdownContext(t *testing.T) {
+	m := New(graceful.WithTimeout(time.Second))
+	release := make(chan struct{})
+	m.Go(func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	if err := m.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("上下文先结束时应返回其错误，实际为%v", err)
+	}
+	close(release)
+	if err := m.Legacy().Shutdown(); !errors.Is(err, graceful.ErrAlreadyShutdown) {
+		t.Errorf("关闭应在后台继续完成，实际为%v", err)
+	}
+}
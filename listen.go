@@ -0,0 +1,46 @@
+package graceful
+
+import (
+	"context"
+	"net"
+)
+
+// Listen announces on the local network address like net.Listen and registers
+// the listener with the manager: its address is included in the startup
+// summary, its connections are reported in Stats, and it is closed when
+// shutdown begins so that no new connections are accepted while in-flight
+// work drains.
+//
+// Example:
+//
+//	ln, err := manager.Listen("tcp", ":8080")
+//	if err != nil {
+//		return err
+//	}
+//	go srv.Serve(ln)
+func (m *Manager) Listen(network, address string) (net.Listener, error) {
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	stats := &listenerStats{address: ln.Addr().String(), conns: make(map[*managedConn]struct{})}
+	m.mu.Lock()
+	m.listeners = append(m.listeners, stats)
+	m.mu.Unlock()
+
+	m.AddAddress(stats.address)
+	m.OnDrain(func(ctx context.Context) error {
+		// Servers that already closed the listener make this a no-op
+		_ = ln.Close()
+		return nil
+	})
+	return &managedListener{Listener: ln, stats: stats}, nil
+}
+
+// AddAddress records a listen address for the startup summary, for listeners
+// that were not created with Listen.
+func (m *Manager) AddAddress(address string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addresses = append(m.addresses, address)
+}
@@ -0,0 +1,84 @@
+package graceful
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestListen 测试受管理的监听器在关闭开始时关闭
+func TestListen(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	ln, err := m.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+
+	accepted := make(chan error, 1)
+	go func() {
+		_, err := ln.Accept()
+		accepted <- err
+	}()
+
+	m.Shutdown()
+
+	select {
+	case err := <-accepted:
+		if err == nil {
+			t.Error("关闭后Accept应返回错误")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("关闭时应关闭监听器")
+	}
+
+	if addrs := m.Summary().Addresses; len(addrs) != 1 || addrs[0] != ln.Addr().String() {
+		t.Errorf("应记录监听地址%s，实际为%v", ln.Addr(), addrs)
+	}
+}
+
+// TestListenerStats 测试受管理监听器的连接统计
+func TestListenerStats(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	ln, err := m.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+
+	release := make(chan struct{})
+	srv := &http.Server{
+		Handler:   http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-release }),
+		ConnState: m.ConnState,
+	}
+	go func() { _ = srv.Serve(ln) }()
+
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	var ls ListenerStats
+	for time.Now().Before(deadline) {
+		ls = m.Stats().Listeners[0]
+		if ls.InFlight == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	if ls.OpenConns != 1 || ls.InFlight != 1 {
+		t.Errorf("应有1个打开的连接和1个进行中的请求，实际为%+v", ls)
+	}
+	if ls.OldestConn <= 0 {
+		t.Error("最老连接的时长应大于0")
+	}
+
+	close(release)
+	_ = srv.Close()
+	m.Shutdown()
+	if ls := m.Stats().Listeners[0]; ls.OpenConns != 0 {
+		t.Errorf("服务器关闭后不应有打开的连接，实际为%d", ls.OpenConns)
+	}
+}
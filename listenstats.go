@@ -0,0 +1,121 @@
+package graceful
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ListenerStats describes the connections of a listener created with Listen,
+// so that operators watching a slow drain can see what is still holding the
+// process open.
+type ListenerStats struct {
+	Address    string        // Listen address
+	OpenConns  int           // Accepted connections not yet closed
+	InFlight   int           // Connections serving a request, as reported to ConnState
+	OldestConn time.Duration // Age of the oldest open connection
+}
+
+// listenerStats tracks the open connections of one managed listener.
+type listenerStats struct {
+	address string
+	mu      sync.Mutex
+	conns   map[*managedConn]struct{}
+}
+
+// managedListener records the connections it accepts.
+type managedListener struct {
+	net.Listener
+	stats *listenerStats
+}
+
+// Accept waits for and returns the next connection, tracking it until it is
+// closed.
+func (l *managedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	mc := &managedConn{Conn: conn, stats: l.stats, accepted: time.Now()}
+	l.stats.mu.Lock()
+	l.stats.conns[mc] = struct{}{}
+	l.stats.mu.Unlock()
+	return mc, nil
+}
+
+// managedConn is a connection accepted by a managed listener.
+type managedConn struct {
+	net.Conn
+	stats    *listenerStats
+	accepted time.Time
+	active   bool // Guarded by stats.mu
+	once     sync.Once
+}
+
+// Close closes the connection and stops tracking it.
+func (c *managedConn) Close() error {
+	c.forget()
+	return c.Conn.Close()
+}
+
+// forget stops tracking the connection.
+func (c *managedConn) forget() {
+	c.once.Do(func() {
+		c.stats.mu.Lock()
+		delete(c.stats.conns, c)
+		c.stats.mu.Unlock()
+	})
+}
+
+// ConnState records the state of connections accepted by listeners created
+// with Listen, so that ListenerStats can report requests in flight. Assign it
+// to http.Server.ConnState; connections from other listeners are ignored.
+//
+// Example:
+//
+//	srv := &http.Server{Handler: mux, ConnState: manager.ConnState}
+func (m *Manager) ConnState(conn net.Conn, state http.ConnState) {
+	c, ok := conn.(*managedConn)
+	if !ok {
+		return
+	}
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		c.forget()
+	default:
+		c.stats.mu.Lock()
+		c.active = state == http.StateActive
+		c.stats.mu.Unlock()
+	}
+}
+
+// snapshot returns the current statistics of the listener.
+func (s *listenerStats) snapshot(now time.Time) ListenerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ls := ListenerStats{Address: s.address, OpenConns: len(s.conns)}
+	for c := range s.conns {
+		if c.active {
+			ls.InFlight++
+		}
+		if age := now.Sub(c.accepted); age > ls.OldestConn {
+			ls.OldestConn = age
+		}
+	}
+	return ls
+}
+
+// listenerStats returns the statistics of every managed listener.
+func (m *Manager) listenerStats() []ListenerStats {
+	m.mu.Lock()
+	listeners := append([]*listenerStats(nil), m.listeners...)
+	m.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]ListenerStats, 0, len(listeners))
+	for _, l := range listeners {
+		stats = append(stats, l.snapshot(now))
+	}
+	return stats
+}
@@ -0,0 +1,26 @@
+package graceful
+
+// Logger is the logging interface used by the manager. *log.Logger satisfies
+// it, and adapters for structured loggers are a single method.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// WithLogger returns an Option that sets a logger for lifecycle messages such
+// as warnings about misconfiguration. By default the manager does not log.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithLogger(log.Default()))
+func WithLogger(logger Logger) Option {
+	return func(m *Manager) {
+		m.logger = logger
+	}
+}
+
+// logf writes a message to the configured logger, if any.
+func (m *Manager) logf(format string, v ...any) {
+	if m.logger != nil {
+		m.logger.Printf("graceful: "+format, v...)
+	}
+}
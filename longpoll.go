@@ -0,0 +1,125 @@
+package graceful
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// LongPolls tracks long-polling HTTP requests so that they can be completed
+// as soon as shutdown begins, instead of holding their connections open until
+// the shutdown deadline. Long-pollers are the usual reason HTTP drains take
+// the full grace period.
+type LongPolls struct {
+	inFlight   inFlight
+	retryAfter time.Duration           // Hint sent to clients in Retry-After
+	drained    context.Context         // Canceled with ErrDraining when the drain starts
+	drain      context.CancelCauseFunc // Cancels drained
+}
+
+// LongPolls creates a registry of long-polling requests. When shutdown
+// begins, the context of every request served through its Middleware is
+// canceled with ErrDraining as the cause, and requests that have not written
+// a response yet are answered with 503 Service Unavailable and a Retry-After
+// header of retryAfter, rounded up to whole seconds. Shutdown waits, bounded
+// by the shutdown timeout, for the handlers to return.
+//
+// Handlers should return promptly once their request context is done:
+//
+//	polls := manager.LongPolls(time.Second)
+//	mux.Handle("/events", polls.Middleware(http.HandlerFunc(
+//		func(w http.ResponseWriter, r *http.Request) {
+//			select {
+//			case ev := <-subscribe(r.Context()):
+//				json.NewEncoder(w).Encode(ev)
+//			case <-r.Context().Done():
+//			}
+//		})))
+func (m *Manager) LongPolls(retryAfter time.Duration) *LongPolls {
+	drained, drain := context.WithCancelCause(context.Background())
+	p := &LongPolls{retryAfter: retryAfter, drained: drained, drain: drain}
+	m.OnDrain(p.close)
+	return p
+}
+
+// Middleware returns an HTTP handler that serves long-polling requests with
+// next, completing them early once the drain has started.
+func (p *LongPolls) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !p.inFlight.begin() {
+			p.retry(w)
+			return
+		}
+		defer p.inFlight.end()
+
+		ctx, cancel := context.WithCancelCause(r.Context())
+		defer cancel(nil)
+		go func() {
+			select {
+			case <-p.drained.Done():
+				cancel(ErrDraining)
+			case <-ctx.Done():
+			}
+		}()
+
+		pw := &pollWriter{ResponseWriter: w}
+		next.ServeHTTP(pw, r.WithContext(ctx))
+		if !pw.wrote && context.Cause(ctx) == ErrDraining {
+			p.retry(w)
+		}
+	})
+}
+
+// InFlight returns the number of long-polling requests in progress.
+func (p *LongPolls) InFlight() int {
+	return p.inFlight.len()
+}
+
+// retry tells the client to poll again, preferably on another instance.
+func (p *LongPolls) retry(w http.ResponseWriter) {
+	seconds := int((p.retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.Header().Set("Connection", "close")
+	http.Error(w, ErrDraining.Error(), http.StatusServiceUnavailable)
+}
+
+// close completes the pending polls and waits for their handlers.
+func (p *LongPolls) close(ctx context.Context) error {
+	p.drain(ErrDraining)
+	return p.inFlight.close(ctx)
+}
+
+// pollWriter records whether a handler has started its response.
+type pollWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *pollWriter) WriteHeader(code int) {
+	w.wrote = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *pollWriter) Write(b []byte) (int, error) {
+	w.wrote = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *pollWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wrote = true
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *pollWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
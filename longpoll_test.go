@@ -0,0 +1,56 @@
+package graceful
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestLongPolls 测试关闭开始时长轮询请求被提前完成并返回重试提示
+func TestLongPolls(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	polls := m.LongPolls(time.Millisecond * 1500)
+
+	started := make(chan struct{})
+	causes := make(chan error, 1)
+	handler := polls.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		causes <- context.Cause(r.Context())
+	}))
+
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+	}()
+	<-started
+	if polls.InFlight() != 1 {
+		t.Errorf("应有1个进行中的长轮询，实际为%d", polls.InFlight())
+	}
+
+	begin := time.Now()
+	m.Shutdown()
+	<-done
+	if time.Since(begin) > time.Millisecond*500 {
+		t.Error("长轮询应在关闭开始时立即完成")
+	}
+	if cause := <-causes; cause != ErrDraining {
+		t.Errorf("取消原因应为ErrDraining，实际为%v", cause)
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("状态码应为503，实际为%d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After应为2，实际为%q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("排空后的新请求应返回503，实际为%d", rec.Code)
+	}
+}
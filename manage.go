@@ -0,0 +1,106 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotManageable is returned by Manage when a value implements none of the
+// supported lifecycle methods.
+var ErrNotManageable = errors.New("graceful: value has no supported lifecycle methods")
+
+// Starter is implemented by components that must be started explicitly.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Drainer is implemented by components that can stop accepting new work while
+// finishing work already in progress.
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// Stopper is implemented by components that release their resources when
+// stopped.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// shutdowner is implemented by components such as *http.Server.
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// errStopper and simpleStopper cover clients whose Stop takes no context.
+type errStopper interface {
+	Stop() error
+}
+
+type simpleStopper interface {
+	Stop()
+}
+
+// Manage wires a component into the lifecycle according to the methods it
+// implements:
+//
+//   - Starter: Start is called immediately with the manager's context, and its
+//     error is returned without registering anything else.
+//   - Drainer: Drain is registered with OnDrain.
+//   - Stopper: Stop is registered as a shutdown hook. If the value is not a
+//     Stopper, the first of Shutdown(ctx) error, Close() error, Stop() error
+//     and Stop() that it implements is used instead.
+//
+// Manage returns ErrNotManageable if the value implements none of these
+// methods.
+//
+// Example:
+//
+//	manager.Manage(redisClient) // Close() error
+//	manager.Manage(httpServer)  // Shutdown(ctx) error
+func (m *Manager) Manage(x any) error {
+	managed := false
+
+	if s, ok := x.(Starter); ok {
+		if err := s.Start(m.Context()); err != nil {
+			return err
+		}
+		managed = true
+	}
+
+	if d, ok := x.(Drainer); ok {
+		m.OnDrain(d.Drain)
+		managed = true
+	}
+
+	if stop := stopFunc(x); stop != nil {
+		m.OnShutdown(stop)
+		managed = true
+	}
+
+	if !managed {
+		return ErrNotManageable
+	}
+	return nil
+}
+
+// stopFunc returns a shutdown hook for the first stop method that x
+// implements, or nil if it implements none.
+func stopFunc(x any) func(ctx context.Context) error {
+	switch s := x.(type) {
+	case Stopper:
+		return s.Stop
+	case shutdowner:
+		return s.Shutdown
+	case io.Closer:
+		return func(ctx context.Context) error { return s.Close() }
+	case errStopper:
+		return func(ctx context.Context) error { return s.Stop() }
+	case simpleStopper:
+		return func(ctx context.Context) error {
+			s.Stop()
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type lifecycleRecorder struct {
+	calls []string
+}
+
+func (r *lifecycleRecorder) Start(ctx context.Context) error {
+	r.calls = append(r.calls, "start")
+	return nil
+}
+
+func (r *lifecycleRecorder) Drain(ctx context.Context) error {
+	r.calls = append(r.calls, "drain")
+	return nil
+}
+
+func (r *lifecycleRecorder) Stop(ctx context.Context) error {
+	r.calls = append(r.calls, "stop")
+	return nil
+}
+
+type closerRecorder struct {
+	closed bool
+}
+
+func (c *closerRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+type failingStarter struct{}
+
+func (failingStarter) Start(ctx context.Context) error {
+	return errors.New("启动失败")
+}
+
+// TestManage 测试根据实现的接口接入生命周期
+func TestManage(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	r := &lifecycleRecorder{}
+	if err := m.Manage(r); err != nil {
+		t.Fatalf("Manage不应返回错误，实际为%v", err)
+	}
+
+	c := &closerRecorder{}
+	if err := m.Manage(c); err != nil {
+		t.Fatalf("Manage不应返回错误，实际为%v", err)
+	}
+
+	m.Shutdown()
+
+	expected := []string{"start", "drain", "stop"}
+	if len(r.calls) != len(expected) {
+		t.Fatalf("生命周期调用应为%v，实际为%v", expected, r.calls)
+	}
+	for i := range expected {
+		if r.calls[i] != expected[i] {
+			t.Fatalf("生命周期调用应为%v，实际为%v", expected, r.calls)
+		}
+	}
+	if !c.closed {
+		t.Error("实现io.Closer的值应在关闭时被Close")
+	}
+}
+
+// TestManageErrors 测试不支持的值和启动失败
+func TestManageErrors(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	if err := m.Manage(struct{}{}); err != ErrNotManageable {
+		t.Errorf("不支持的值应返回ErrNotManageable，实际为%v", err)
+	}
+	if err := m.Manage(failingStarter{}); err == nil {
+		t.Error("启动失败时应返回错误")
+	}
+}
@@ -0,0 +1,57 @@
+package graceful
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// TaskMiddleware wraps the function of every task started with CtxGo. name
+// is the task name, or the function name for unnamed tasks. Middleware is
+// the place for cross-cutting instrumentation such as logging, metrics,
+// panic capture and profiler labels.
+type TaskMiddleware func(name string, next func(ctx context.Context)) func(ctx context.Context)
+
+// WithTaskMiddleware returns an Option that wraps every task function with
+// the given middleware. The first middleware is the outermost one, and each
+// call to WithTaskMiddleware appends to the chain.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithTaskMiddleware(
+//		graceful.PprofLabels(),
+//		func(name string, next func(ctx context.Context)) func(ctx context.Context) {
+//			return func(ctx context.Context) {
+//				start := time.Now()
+//				next(ctx)
+//				taskDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+//			}
+//		},
+//	))
+func WithTaskMiddleware(mw ...TaskMiddleware) Option {
+	return func(m *Manager) {
+		m.middleware = append(m.middleware, mw...)
+	}
+}
+
+// PprofLabels returns a TaskMiddleware that runs each task with the pprof
+// label "task" set to its name, so that CPU and goroutine profiles attribute
+// work to tasks.
+func PprofLabels() TaskMiddleware {
+	return func(name string, next func(ctx context.Context)) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			pprof.Do(ctx, pprof.Labels("task", name), next)
+		}
+	}
+}
+
+// wrapTask applies the task middleware to f.
+func (m *Manager) wrapTask(t *Task, f func(ctx context.Context)) func(ctx context.Context) {
+	if len(m.middleware) == 0 {
+		return f
+	}
+	name := taskName(t, f)
+	for i := len(m.middleware) - 1; i >= 0; i-- {
+		f = m.middleware[i](name, f)
+	}
+	return f
+}
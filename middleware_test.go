@@ -0,0 +1,40 @@
+package graceful
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestTaskMiddleware 测试中间件按顺序包装每个任务
+func TestTaskMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	record := func(tag string) TaskMiddleware {
+		return func(name string, next func(ctx context.Context)) func(ctx context.Context) {
+			return func(ctx context.Context) {
+				mu.Lock()
+				calls = append(calls, tag+":"+name)
+				mu.Unlock()
+				next(ctx)
+			}
+		}
+	}
+	m := New(WithTimeout(time.Second), WithTaskMiddleware(record("outer"), record("inner"), PprofLabels()))
+
+	label := make(chan string, 1)
+	m.CtxGo(func(ctx context.Context) {
+		v, _ := pprof.Label(ctx, "task")
+		label <- v
+	}, WithName("worker"))
+	m.Shutdown()
+
+	if len(calls) != 2 || calls[0] != "outer:worker" || calls[1] != "inner:worker" {
+		t.Errorf("中间件调用顺序不正确: %v", calls)
+	}
+	if v := <-label; v != "worker" {
+		t.Errorf("任务应带有pprof标签，实际为%q", v)
+	}
+}
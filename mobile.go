@@ -0,0 +1,54 @@
+package graceful
+
+// MobileLifecycle exposes the manager's lifecycle as argument-less methods
+// that gomobile can bind, so Android and iOS apps embedding Go code drive
+// managed goroutines from platform callbacks instead of signals. Call the
+// methods from the matching Activity or UIApplicationDelegate callbacks.
+type MobileLifecycle struct {
+	m *Manager
+}
+
+// MobileLifecycle returns the bind-friendly lifecycle bridge of the manager.
+// Wrap it in a type of the package passed to gomobile bind, since gomobile
+// only exports types declared there:
+//
+//	package mobile
+//
+//	var manager = graceful.New()
+//
+//	type Lifecycle struct{ l *graceful.MobileLifecycle }
+//
+//	func NewLifecycle() *Lifecycle { return &Lifecycle{manager.MobileLifecycle()} }
+//	func (l *Lifecycle) OnPause() { l.l.OnPause() }
+//	func (l *Lifecycle) OnResume() { l.l.OnResume() }
+//	func (l *Lifecycle) OnStop() { l.l.OnStop() }
+//	func (l *Lifecycle) OnDestroy() { l.l.OnDestroy() }
+func (m *Manager) MobileLifecycle() *MobileLifecycle {
+	return &MobileLifecycle{m: m}
+}
+
+// OnPause pauses periodic tasks and intake, like Manager.Pause. Call it from
+// onPause (Android) or applicationWillResignActive (iOS).
+func (l *MobileLifecycle) OnPause() {
+	l.m.Pause()
+}
+
+// OnResume resumes after OnPause. Call it from onResume (Android) or
+// applicationDidBecomeActive (iOS).
+func (l *MobileLifecycle) OnResume() {
+	l.m.Resume()
+}
+
+// OnStop runs the flush functions registered with OnFlush, since the
+// platform may kill a stopped app without further notice. Goroutines keep
+// running, so the app can come back to the foreground. Call it from onStop
+// (Android) or applicationDidEnterBackground (iOS).
+func (l *MobileLifecycle) OnStop() {
+	l.m.runFlushers()
+}
+
+// OnDestroy runs a full graceful shutdown and makes a pending Wait return.
+// Call it from onDestroy (Android) or applicationWillTerminate (iOS).
+func (l *MobileLifecycle) OnDestroy() {
+	l.m.shutdown()
+}
@@ -0,0 +1,60 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestMobileLifecycle 测试移动平台回调驱动暂停、刷新和关闭
+func TestMobileLifecycle(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	l := m.MobileLifecycle()
+
+	flushes := 0
+	m.OnFlush(func(ctx context.Context) error {
+		flushes++
+		return nil
+	})
+	stopped := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	l.OnPause()
+	if !m.Paused() {
+		t.Error("OnPause后应处于暂停状态")
+	}
+	l.OnResume()
+	if m.Paused() {
+		t.Error("OnResume后不应处于暂停状态")
+	}
+
+	l.OnStop()
+	if flushes != 1 {
+		t.Errorf("OnStop应运行刷新函数，实际运行%d次", flushes)
+	}
+	select {
+	case <-stopped:
+		t.Fatal("OnStop不应停止任务")
+	default:
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waited)
+	}()
+	l.OnDestroy()
+	select {
+	case <-stopped:
+	default:
+		t.Error("OnDestroy应停止任务")
+	}
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Error("OnDestroy后Wait应返回")
+	}
+}
@@ -0,0 +1,61 @@
+package graceful
+
+import "errors"
+
+// ErrNothingRegistered reports that the manager was asked to wait while no
+// goroutines or start functions had been registered.
+var ErrNothingRegistered = errors.New("graceful: no tasks or services registered")
+
+// EventNoTasks is emitted when Wait or Run is reached with nothing
+// registered, unless the policy is NoTasksBlock.
+const EventNoTasks EventType = "no_tasks"
+
+// NoTasksPolicy controls what Wait and Run do when no goroutines have been
+// started and no start functions were given to Run.
+type NoTasksPolicy int
+
+const (
+	// NoTasksBlock waits for a signal as usual. This is the default.
+	NoTasksBlock NoTasksPolicy = iota
+	// NoTasksWarn logs a warning and emits EventNoTasks, then waits for a
+	// signal as usual.
+	NoTasksWarn
+	// NoTasksFail emits EventNoTasks and shuts down immediately instead of
+	// waiting. Run exits with the StartupFailure code.
+	NoTasksFail
+)
+
+// WithNoTasksPolicy returns an Option that sets what happens when Wait or Run
+// is reached with nothing registered. A process that forgot to register its
+// workers otherwise looks healthy while doing nothing.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithNoTasksPolicy(graceful.NoTasksFail))
+func WithNoTasksPolicy(policy NoTasksPolicy) Option {
+	return func(m *Manager) {
+		m.noTasksPolicy = policy
+	}
+}
+
+// checkRegistered applies the no-tasks policy. It returns ErrNothingRegistered
+// if the caller should shut down instead of waiting.
+func (m *Manager) checkRegistered() error {
+	if m.noTasksPolicy == NoTasksBlock {
+		return nil
+	}
+
+	m.mu.Lock()
+	empty := m.started == 0 && len(m.starts) == 0
+	m.mu.Unlock()
+	if !empty {
+		return nil
+	}
+
+	m.emit(Event{Type: EventNoTasks, Err: ErrNothingRegistered})
+	if m.noTasksPolicy == NoTasksFail {
+		return ErrNothingRegistered
+	}
+	m.logf("warning: waiting for a signal but %v", ErrNothingRegistered)
+	return nil
+}
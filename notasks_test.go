@@ -0,0 +1,75 @@
+package graceful
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+// TestNoTasksFail 测试未注册任何任务时立即返回
+func TestNoTasksFail(t *testing.T) {
+	var events []Event
+	m := New(WithNoTasksPolicy(NoTasksFail), WithEventHandler(func(e Event) {
+		events = append(events, e)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("未注册任何任务时Wait应立即返回")
+	}
+	// no_tasks事件之后是关闭序列的事件
+	if len(events) != 3 || events[0].Type != EventNoTasks || events[0].Err != ErrNothingRegistered {
+		t.Errorf("应发出no_tasks事件，实际为%v", events)
+	}
+	if m.Context().Err() == nil {
+		t.Error("立即返回时应执行关闭")
+	}
+}
+
+// TestNoTasksFailRun 测试Run在未注册任何任务时以启动失败退出
+func TestNoTasksFailRun(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	New(WithNoTasksPolicy(NoTasksFail)).Run()
+
+	if code != DefaultExitCodes().StartupFailure {
+		t.Errorf("退出码应为%d，实际为%d", DefaultExitCodes().StartupFailure, code)
+	}
+}
+
+// TestNoTasksWarn 测试警告模式只记录日志
+func TestNoTasksWarn(t *testing.T) {
+	logger := &recordingLogger{}
+	m := New(WithNoTasksPolicy(NoTasksWarn), WithLogger(logger))
+
+	if err := m.checkRegistered(); err != nil {
+		t.Errorf("警告模式不应返回错误，实际为%v", err)
+	}
+	if len(logger.lines) != 1 {
+		t.Errorf("警告模式应记录1条日志，实际为%d条", len(logger.lines))
+	}
+
+	m.Go(func() {})
+	if err := m.checkRegistered(); err != nil || len(logger.lines) != 1 {
+		t.Error("已注册任务时不应警告")
+	}
+	m.Shutdown()
+}
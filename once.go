@@ -0,0 +1,63 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+)
+
+// OnceValue returns a function that initializes a resource with init on its
+// first call and returns the same value and error on every call after that,
+// as if guarded by a sync.Once. Once init succeeds, cleanup is registered as a
+// shutdown hook, so a lazily created singleton is released with the rest of
+// the application instead of leaking. init receives the manager's context.
+//
+// If drain has started before the first call, init is not called and the
+// function returns ErrDraining, so a shutting-down process does not open
+// resources nobody will clean up. A nil cleanup registers nothing. Once the
+// cleanup has run, for example when Restart tears down a value first
+// created by the start functions, the next call initializes the resource
+// again.
+//
+// Example:
+//
+//	getDB := graceful.OnceValue(manager,
+//		func(ctx context.Context) (*sql.DB, error) { return sql.Open("postgres", dsn) },
+//		func(ctx context.Context, db *sql.DB) error { return db.Close() },
+//	)
+//	db, err := getDB()
+func OnceValue[T any](m *Manager, init func(ctx context.Context) (T, error), cleanup func(ctx context.Context, v T) error) func() (T, error) {
+	var (
+		mu    sync.Mutex
+		done  bool
+		value T
+		err   error
+	)
+	return func() (T, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			return value, err
+		}
+		if m.draining.Load() {
+			var zero T
+			return zero, ErrDraining
+		}
+
+		done = true
+		value, err = init(m.Context())
+		if err == nil && cleanup != nil {
+			v := value
+			m.OnShutdown(func(ctx context.Context) error {
+				err := cleanup(ctx, v)
+				// A restart releases values created by the running
+				// generation; the next call initializes a fresh one
+				mu.Lock()
+				var zero T
+				done, value = false, zero
+				mu.Unlock()
+				return err
+			})
+		}
+		return value, err
+	}
+}
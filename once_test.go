@@ -0,0 +1,84 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestOnceValue 测试惰性初始化只执行一次并在关闭时清理
+func TestOnceValue(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	inits, cleaned := 0, 0
+	get := OnceValue(m,
+		func(ctx context.Context) (int, error) {
+			inits++
+			return 42, nil
+		},
+		func(ctx context.Context, v int) error {
+			if v != 42 {
+				t.Errorf("清理函数应收到初始化的值，实际为%d", v)
+			}
+			cleaned++
+			return nil
+		},
+	)
+
+	for i := 0; i < 3; i++ {
+		if v, err := get(); v != 42 || err != nil {
+			t.Fatalf("应返回42和nil，实际为%d和%v", v, err)
+		}
+	}
+	if inits != 1 {
+		t.Errorf("初始化应只执行一次，实际为%d次", inits)
+	}
+
+	m.Shutdown()
+	if cleaned != 1 {
+		t.Errorf("关闭时应清理一次，实际为%d次", cleaned)
+	}
+}
+
+// TestOnceValueAfterDrain 测试排空开始后拒绝初始化
+func TestOnceValueAfterDrain(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.Shutdown()
+
+	called := false
+	get := OnceValue(m, func(ctx context.Context) (string, error) {
+		called = true
+		return "x", nil
+	}, nil)
+
+	if _, err := get(); !errors.Is(err, ErrDraining) {
+		t.Errorf("排空后应返回ErrDraining，实际为%v", err)
+	}
+	if called {
+		t.Error("排空后不应调用初始化函数")
+	}
+}
+
+// TestOnceValueError 测试初始化失败时不注册清理函数
+func TestOnceValueError(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	boom := errors.New("boom")
+
+	cleaned := false
+	get := OnceValue(m,
+		func(ctx context.Context) (int, error) { return 0, boom },
+		func(ctx context.Context, v int) error { cleaned = true; return nil },
+	)
+	if _, err := get(); !errors.Is(err, boom) {
+		t.Errorf("应返回初始化错误，实际为%v", err)
+	}
+	if _, err := get(); !errors.Is(err, boom) {
+		t.Errorf("再次调用应返回相同错误，实际为%v", err)
+	}
+
+	m.Shutdown()
+	if cleaned {
+		t.Error("初始化失败时不应清理")
+	}
+}
@@ -0,0 +1,63 @@
+package graceful
+
+import "os"
+
+// OutputFlusher is a buffered writer in front of standard output or standard
+// error, such as a *bufio.Writer.
+type OutputFlusher interface {
+	Flush() error
+}
+
+// RegisterOutput registers a buffered writer to be flushed as the very last
+// step of shutdown, after the flush phase, and again by Run after the
+// functions registered with OnExit, so that output piped from a CLI tool is
+// not truncated when the tool is stopped by a signal.
+//
+// Example:
+//
+//	out := bufio.NewWriter(os.Stdout)
+//	manager.RegisterOutput(out)
+func (m *Manager) RegisterOutput(w OutputFlusher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outputs = append(m.outputs, w)
+}
+
+// WithOutputSync returns an Option that makes the final output flush also
+// sync standard output and standard error to disk when they are redirected
+// to regular files, so that the output of a process killed right after
+// shutdown survives a crash of the host. Pipes and terminals are left alone.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithOutputSync())
+func WithOutputSync() Option {
+	return func(m *Manager) {
+		m.outputSync = true
+	}
+}
+
+// flushOutput flushes the registered writers and, if enabled, syncs the
+// standard streams that are redirected to files.
+func (m *Manager) flushOutput() {
+	m.mu.Lock()
+	outputs := append([]OutputFlusher(nil), m.outputs...)
+	m.mu.Unlock()
+
+	for _, w := range outputs {
+		if err := w.Flush(); err != nil {
+			m.logf("flushing output failed: %v", err)
+		}
+	}
+	if m.outputSync {
+		syncFile(os.Stdout)
+		syncFile(os.Stderr)
+	}
+}
+
+// syncFile syncs f to disk if it is a regular file.
+func syncFile(f *os.File) {
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+		_ = f.Sync()
+	}
+}
@@ -0,0 +1,73 @@
+package graceful
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRegisterOutput 测试关闭的最后一步刷新缓冲的输出
+func TestRegisterOutput(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	var buf bytes.Buffer
+	out := bufio.NewWriter(&buf)
+	m.RegisterOutput(out)
+
+	fmt.Fprint(out, "partial line")
+	var atHook string
+	m.OnShutdown(func(ctx context.Context) error {
+		atHook = buf.String()
+		fmt.Fprint(out, ", done")
+		return nil
+	})
+	m.Shutdown()
+
+	if atHook != "" {
+		t.Error("输出应在关闭钩子之后才刷新")
+	}
+	if buf.String() != "partial line, done" {
+		t.Errorf("关闭后缓冲的输出应已刷新，实际为%q", buf.String())
+	}
+}
+
+// TestRegisterOutputRun 测试Run在最终函数之后再次刷新输出
+func TestRegisterOutputRun(t *testing.T) {
+	var flushed string
+	var buf bytes.Buffer
+	exit = func(int) { flushed = buf.String() }
+	defer func() { exit = os.Exit }()
+
+	m := New(WithTimeout(time.Second))
+	out := bufio.NewWriter(&buf)
+	m.RegisterOutput(out)
+	m.OnExit(func(code int) { fmt.Fprintf(out, "exit %d", code) })
+	m.Run(func(ctx context.Context) error { return errors.New("启动失败") })
+
+	if want := fmt.Sprintf("exit %d", DefaultExitCodes().StartupFailure); flushed != want {
+		t.Errorf("退出前应刷新最终函数的输出，实际为%q", flushed)
+	}
+}
+
+// TestSyncFile 测试只同步普通文件
+func TestSyncFile(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatalf("创建文件失败: %v", err)
+	}
+	defer f.Close()
+	syncFile(f)
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("创建管道失败: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+	syncFile(w)
+}
@@ -0,0 +1,110 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// maxPacketSize is large enough for any UDP datagram.
+const maxPacketSize = 64 << 10
+
+// PacketHandler handles one packet received by ServePackets. p is only valid
+// for the duration of the call; replies can be sent with conn.WriteTo.
+type PacketHandler func(ctx context.Context, conn net.PacketConn, p []byte, addr net.Addr)
+
+// PacketServer is a packet-oriented server managed by ServePackets.
+type PacketServer struct {
+	conn     net.PacketConn
+	handler  PacketHandler
+	inFlight inFlight
+	stopping atomic.Bool
+	ctx      context.Context    // Context of the handlers
+	cancel   context.CancelFunc // Cancels ctx once draining has ended
+	stopped  chan struct{}      // Closed when the read loop has returned
+}
+
+// ServePackets serves packets received on conn, such as a UDP socket for DNS
+// or metrics, calling handler for each packet in its own goroutine. When
+// shutdown begins, the server stops reading, waits for handlers in flight
+// within the shutdown timeout, and then closes conn. Handlers run with a
+// context that is only canceled once that wait is over, so their replies
+// still go out.
+//
+// Example:
+//
+//	conn, err := net.ListenPacket("udp", ":53")
+//	if err != nil {
+//		return err
+//	}
+//	manager.ServePackets(conn, func(ctx context.Context, conn net.PacketConn, p []byte, addr net.Addr) {
+//		if reply, err := resolve(ctx, p); err == nil {
+//			_, _ = conn.WriteTo(reply, addr)
+//		}
+//	})
+func (m *Manager) ServePackets(conn net.PacketConn, handler PacketHandler) *PacketServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &PacketServer{
+		conn:    conn,
+		handler: handler,
+		ctx:     ctx,
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+	m.AddAddress(conn.LocalAddr().String())
+	m.OnDrain(s.close)
+	m.Go(s.serve)
+	return s
+}
+
+// InFlight returns the number of packets being handled.
+func (s *PacketServer) InFlight() int {
+	return s.inFlight.len()
+}
+
+// serve reads packets until the server stops or conn fails.
+func (s *PacketServer) serve() {
+	defer close(s.stopped)
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			var ne net.Error
+			if s.stopping.Load() || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return
+		}
+		if !s.inFlight.begin() {
+			return
+		}
+		p := append([]byte(nil), buf[:n]...)
+		go func() {
+			defer s.inFlight.end()
+			s.handler(s.ctx, s.conn, p, addr)
+		}()
+	}
+}
+
+// close stops reading, waits for handlers in flight and closes the socket.
+func (s *PacketServer) close(ctx context.Context) error {
+	s.stopping.Store(true)
+	// Interrupt a blocked read
+	_ = s.conn.SetReadDeadline(time.Now())
+	select {
+	case <-s.stopped:
+	case <-ctx.Done():
+	}
+
+	err := s.inFlight.close(ctx)
+	s.cancel()
+	if cerr := s.conn.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
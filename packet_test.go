@@ -0,0 +1,49 @@
+package graceful
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestServePackets 测试关闭时等待进行中的数据包处理后再关闭套接字
+func TestServePackets(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+
+	m := New(WithTimeout(time.Second))
+	started := make(chan struct{})
+	s := m.ServePackets(conn, func(ctx context.Context, conn net.PacketConn, p []byte, addr net.Addr) {
+		close(started)
+		time.Sleep(time.Millisecond * 50)
+		_, _ = conn.WriteTo(append([]byte("re:"), p...), addr)
+	})
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("连接失败: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.Write([]byte("ping")); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	<-started
+	if s.InFlight() != 1 {
+		t.Errorf("应有1个进行中的处理，实际为%d", s.InFlight())
+	}
+
+	m.Shutdown()
+
+	_ = client.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 64)
+	n, err := client.Read(buf)
+	if err != nil || string(buf[:n]) != "re:ping" {
+		t.Errorf("关闭前应发出回复，实际为%q, %v", buf[:n], err)
+	}
+	if _, err := conn.WriteTo([]byte("x"), client.LocalAddr()); err == nil {
+		t.Error("关闭后套接字应已关闭")
+	}
+}
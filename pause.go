@@ -0,0 +1,102 @@
+package graceful
+
+import (
+	"context"
+	"os"
+)
+
+// WithJobControl returns an Option that makes the manager handle SIGTSTP and
+// SIGCONT. On SIGTSTP the manager pauses periodic tasks and intake, emits an
+// EventPaused event and then suspends the process; on SIGCONT it resumes and
+// emits EventResumed. Without this option, timers keep running while the
+// process is suspended and fire in a burst on resume.
+//
+// Job control is only available on Unix systems; elsewhere this option has
+// no effect.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithJobControl())
+func WithJobControl() Option {
+	return func(m *Manager) {
+		m.jobControl = true
+	}
+}
+
+// Pause stops periodic tasks started with Every from running and makes
+// WaitResumed block until Resume is called. Calling Pause on a paused manager
+// has no effect.
+func (m *Manager) Pause() {
+	m.pause(nil)
+}
+
+// Resume releases tasks blocked by Pause. Calling Resume on a manager that is
+// not paused has no effect.
+func (m *Manager) Resume() {
+	m.resume(nil)
+}
+
+// Paused reports whether the manager is currently paused.
+func (m *Manager) Paused() bool {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+	return m.resumed != nil
+}
+
+// WaitResumed blocks while the manager is paused. It returns nil immediately
+// if the manager is not paused, or the context's error if ctx is done first.
+// Loops that accept new work, such as queue consumers, should call it before
+// taking the next item.
+//
+// Example:
+//
+//	for {
+//		if err := manager.WaitResumed(ctx); err != nil {
+//			return
+//		}
+//		handle(<-jobs)
+//	}
+func (m *Manager) WaitResumed(ctx context.Context) error {
+	m.pauseMu.Lock()
+	resumed := m.resumed
+	m.pauseMu.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause records the paused state and emits EventPaused on transition. It
+// reports whether the manager was not paused before.
+func (m *Manager) pause(sig os.Signal) (paused bool) {
+	m.pauseMu.Lock()
+	if m.resumed != nil {
+		m.pauseMu.Unlock()
+		return false
+	}
+	m.resumed = make(chan struct{})
+	m.pauseMu.Unlock()
+
+	m.emit(Event{Type: EventPaused, Signal: sig})
+	return true
+}
+
+// resume clears the paused state and emits EventResumed on transition.
+func (m *Manager) resume(sig os.Signal) {
+	m.pauseMu.Lock()
+	if m.resumed == nil {
+		m.pauseMu.Unlock()
+		return
+	}
+	close(m.resumed)
+	m.resumed = nil
+	m.pauseMu.Unlock()
+
+	m.emit(Event{Type: EventResumed, Signal: sig})
+}
@@ -0,0 +1,6 @@
+//go:build !unix
+
+package graceful
+
+// handleJobControl is a no-op on systems without Unix job control.
+func (m *Manager) handleJobControl() {}
@@ -0,0 +1,66 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestPauseResume 测试暂停与恢复状态及事件
+func TestPauseResume(t *testing.T) {
+	var events []EventType
+	m := New(WithEventHandler(func(e Event) {
+		events = append(events, e.Type)
+	}))
+
+	if m.Paused() {
+		t.Error("新创建的Manager不应处于暂停状态")
+	}
+	if err := m.WaitResumed(context.Background()); err != nil {
+		t.Errorf("未暂停时WaitResumed应立即返回nil，实际为%v", err)
+	}
+
+	m.Pause()
+	m.Pause()
+	if !m.Paused() {
+		t.Error("调用Pause后应处于暂停状态")
+	}
+
+	released := make(chan error, 1)
+	go func() {
+		released <- m.WaitResumed(context.Background())
+	}()
+
+	select {
+	case <-released:
+		t.Error("暂停期间WaitResumed不应返回")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	m.Resume()
+	select {
+	case err := <-released:
+		if err != nil {
+			t.Errorf("恢复后WaitResumed应返回nil，实际为%v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("恢复后WaitResumed未返回")
+	}
+
+	if len(events) != 2 || events[0] != EventPaused || events[1] != EventResumed {
+		t.Errorf("事件应为[paused resumed]，实际为%v", events)
+	}
+}
+
+// TestWaitResumedContext 测试暂停期间上下文取消时WaitResumed返回错误
+func TestWaitResumedContext(t *testing.T) {
+	m := New()
+	m.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	if err := m.WaitResumed(ctx); err != context.DeadlineExceeded {
+		t.Errorf("上下文超时时应返回DeadlineExceeded，实际为%v", err)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package graceful
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// handleJobControl pauses the manager on SIGTSTP before suspending the
+// process, and resumes it on SIGCONT. It returns when shutdown begins.
+func (m *Manager) handleJobControl() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTSTP, syscall.SIGCONT)
+
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-m.lifetime.Done():
+				return
+			case sig := <-sigCh:
+				m.recordSignal(sig, time.Now(), true)
+				if sig == syscall.SIGCONT {
+					m.resume(sig)
+					continue
+				}
+				m.pause(sig)
+				// SIGTSTP is caught, so suspend the process explicitly
+				_ = syscall.Kill(os.Getpid(), syscall.SIGSTOP)
+			}
+		}
+	}()
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package graceful
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestJobControlResume 测试收到SIGCONT时恢复暂停的Manager
+func TestJobControlResume(t *testing.T) {
+	resumed := make(chan struct{}, 1)
+	m := New(WithJobControl(), WithEventHandler(func(e Event) {
+		if e.Type == EventResumed && e.Signal == syscall.SIGCONT {
+			resumed <- struct{}{}
+		}
+	}))
+	defer m.Shutdown()
+
+	m.Pause()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGCONT); err != nil {
+		t.Fatalf("发送SIGCONT失败: %v", err)
+	}
+
+	select {
+	case <-resumed:
+	case <-time.After(time.Second * 2):
+		t.Fatal("收到SIGCONT后应恢复")
+	}
+	if m.Paused() {
+		t.Error("收到SIGCONT后不应处于暂停状态")
+	}
+}
@@ -0,0 +1,52 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// Every starts a managed goroutine that calls f once per interval until the
+// manager shuts down. Ticks are skipped while the manager is paused, and the
+// schedule restarts from the moment of resumption, so a suspended process
+// does not run a burst of missed ticks when it continues.
+//
+// Example:
+//
+//	manager.Every(time.Minute, func(ctx context.Context) {
+//		refreshCache(ctx)
+//	})
+func (m *Manager) Every(interval time.Duration, f func(ctx context.Context)) {
+	m.CtxGo(func(ctx context.Context) {
+		m.runEvery(ctx, ctx, interval, f)
+	})
+}
+
+// runEvery calls f with ctx once per interval until stop is done, skipping
+// ticks while the manager is paused.
+func (m *Manager) runEvery(ctx, stop context.Context, interval time.Duration, f func(ctx context.Context)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if m.Paused() {
+			if err := m.WaitResumed(stop); err != nil {
+				return
+			}
+			// Restart the schedule and drop the tick that was queued during the pause
+			ticker.Reset(interval)
+			select {
+			case <-ticker.C:
+			default:
+			}
+			continue
+		}
+
+		f(ctx)
+	}
+}
@@ -0,0 +1,55 @@
+package graceful
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestEvery 测试周期任务按间隔执行并在关闭时退出
+func TestEvery(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var count int32
+	m.Every(time.Millisecond*10, func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+	})
+
+	time.Sleep(time.Millisecond * 55)
+	m.Shutdown()
+
+	n := atomic.LoadInt32(&count)
+	if n < 2 {
+		t.Errorf("周期任务应至少执行2次，实际执行了%d次", n)
+	}
+
+	time.Sleep(time.Millisecond * 30)
+	if atomic.LoadInt32(&count) != n {
+		t.Error("关闭后周期任务不应继续执行")
+	}
+}
+
+// TestEveryPaused 测试暂停期间周期任务不执行
+func TestEveryPaused(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.Pause()
+
+	var count int32
+	m.Every(time.Millisecond*10, func(ctx context.Context) {
+		atomic.AddInt32(&count, 1)
+	})
+
+	time.Sleep(time.Millisecond * 50)
+	if n := atomic.LoadInt32(&count); n != 0 {
+		t.Errorf("暂停期间周期任务不应执行，实际执行了%d次", n)
+	}
+
+	m.Resume()
+	time.Sleep(time.Millisecond * 45)
+	m.Shutdown()
+
+	if n := atomic.LoadInt32(&count); n < 1 || n > 5 {
+		t.Errorf("恢复后周期任务应按间隔执行，实际执行了%d次", n)
+	}
+}
@@ -0,0 +1,49 @@
+package graceful
+
+import "context"
+
+// PersistentCache is implemented by caches that are kept warm across
+// restarts by saving their contents at shutdown and loading them again at
+// startup.
+type PersistentCache interface {
+	Load(ctx context.Context) error
+	Persist(ctx context.Context) error
+}
+
+// RegisterCache ties a persistent cache into the lifecycle: Load is
+// registered as a start hook, so it runs when Run or Start runs the start
+// hooks, and Persist runs during shutdown like a flusher registered with
+// RegisterFlusher, after managed goroutines have stopped writing to the
+// cache and before the shutdown hooks close the store it is saved to. Use
+// WithFlusherBudget to limit how long Persist may take.
+//
+// A failing Load is logged and startup continues with a cold cache; a
+// failing Persist is logged without stopping the shutdown.
+//
+// Example:
+//
+//	cache := newSnapshotCache("/var/lib/app/cache.gob")
+//	manager.RegisterCache(cache, graceful.WithFlusherBudget(5*time.Second))
+func (m *Manager) RegisterCache(c PersistentCache, opts ...FlusherOption) {
+	m.OnStart(func(ctx context.Context) error {
+		if err := c.Load(ctx); err != nil {
+			m.logf("loading %T failed, starting with a cold cache: %v", c, err)
+		}
+		return nil
+	})
+	m.RegisterFlusher(cachePersister{cache: c, m: m}, opts...)
+}
+
+// cachePersister adapts a PersistentCache to the Flusher interface.
+type cachePersister struct {
+	cache PersistentCache
+	m     *Manager
+}
+
+// Flush persists the cache, logging failures under the cache's type.
+func (p cachePersister) Flush(ctx context.Context) error {
+	if err := p.cache.Persist(ctx); err != nil {
+		p.m.logf("persisting %T failed: %v", p.cache, err)
+	}
+	return nil
+}
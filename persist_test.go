@@ -0,0 +1,77 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCache 记录加载与持久化的调用
+type fakeCache struct {
+	loaded, persisted bool
+	loadErr           error
+	left              time.Duration
+	closed            *bool
+}
+
+func (c *fakeCache) Load(ctx context.Context) error {
+	c.loaded = true
+	return c.loadErr
+}
+
+func (c *fakeCache) Persist(ctx context.Context) error {
+	if *c.closed {
+		return errors.New("存储已关闭")
+	}
+	deadline, _ := ctx.Deadline()
+	c.left = time.Until(deadline)
+	c.persisted = true
+	return nil
+}
+
+// TestRegisterCache 测试缓存在启动时加载并在关闭钩子前持久化
+func TestRegisterCache(t *testing.T) {
+	m := New(WithTimeout(time.Second * 5))
+	closed := false
+	m.OnShutdown(func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+	cache := &fakeCache{closed: &closed}
+	m.RegisterCache(cache, WithFlusherBudget(time.Millisecond*100))
+	m.Go(func() {})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !cache.loaded {
+		t.Error("启动时应加载缓存")
+	}
+	m.Stop()
+
+	if !cache.persisted {
+		t.Error("关闭钩子运行前应持久化缓存")
+	}
+	if cache.left > time.Millisecond*100 {
+		t.Errorf("持久化应受预算限制，剩余%v", cache.left)
+	}
+}
+
+// TestRegisterCacheLoadError 测试加载失败时以冷缓存继续启动
+func TestRegisterCacheLoadError(t *testing.T) {
+	logger := &recordingLogger{}
+	m := New(WithTimeout(time.Second), WithLogger(logger))
+	closed := false
+	m.RegisterCache(&fakeCache{closed: &closed, loadErr: errors.New("no snapshot")})
+	m.Go(func() {})
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("加载失败不应导致启动失败，实际为%v", err)
+	}
+	m.Stop()
+	if !strings.Contains(strings.Join(logger.lines, "\n"), "starting with a cold cache") {
+		t.Errorf("加载失败时应记录日志，实际为%v", logger.lines)
+	}
+}
@@ -0,0 +1,99 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// WithPhase returns a TaskOption that puts the task in a shutdown phase.
+// When shutdown begins, after the drain functions and handoffs, phases are
+// canceled one at a time in ascending order, and each phase's tasks must
+// return before the next phase is canceled, giving orderings such as "stop
+// accepting traffic, then drain in-flight work, then close infrastructure".
+// Tasks without a phase, and goroutines started with Go, are stopped after
+// the last phase by the drain strategy or canceled together.
+//
+// Example:
+//
+//	manager.CtxGo(serveHTTP, graceful.WithPhase(0))
+//	manager.CtxGo(processJobs, graceful.WithPhase(1))
+//	manager.CtxGo(flushToKafka, graceful.WithPhase(2))
+func WithPhase(phase int) TaskOption {
+	return func(c *taskConfig) {
+		c.phase = &phase
+	}
+}
+
+// WithPhaseTimeout returns an Option that limits how long the tasks of a
+// shutdown phase may take to return before the next phase is canceled.
+// Without it, a phase may wait until the shutdown timeout expires.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithPhaseTimeout(1, 10*time.Second))
+func WithPhaseTimeout(phase int, timeout time.Duration) Option {
+	return func(m *Manager) {
+		if m.phaseTimeouts == nil {
+			m.phaseTimeouts = make(map[int]time.Duration)
+		}
+		m.phaseTimeouts[phase] = timeout
+	}
+}
+
+// drainPhases cancels the tasks that have a phase, phase by phase.
+func (m *Manager) drainPhases(ctx context.Context) {
+	phases := make(map[int][]*Task)
+	var order []int
+	for _, t := range m.liveTasks() {
+		if t.phase == nil {
+			continue
+		}
+		p := *t.phase
+		if _, ok := phases[p]; !ok {
+			order = append(order, p)
+		}
+		phases[p] = append(phases[p], t)
+	}
+	sort.Ints(order)
+
+	for _, p := range order {
+		pctx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout, ok := m.phaseTimeouts[p]; ok && timeout > 0 {
+			pctx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		DrainSimultaneous().Drain(pctx, phases[p])
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// phaseSteps returns the dry-run steps of the shutdown phases of the running
+// tasks. The caller must hold m.mu.
+func (m *Manager) phaseSteps() []PlanStep {
+	counts := make(map[int]int)
+	var order []int
+	for t := range m.live {
+		if t.phase == nil {
+			continue
+		}
+		if counts[*t.phase] == 0 {
+			order = append(order, *t.phase)
+		}
+		counts[*t.phase]++
+	}
+	sort.Ints(order)
+
+	steps := make([]PlanStep, 0, len(order))
+	for _, p := range order {
+		budget := m.timeout
+		if timeout, ok := m.phaseTimeouts[p]; ok && timeout > 0 && timeout < budget {
+			budget = timeout
+		}
+		steps = append(steps, PlanStep{Phase: "phase", Name: fmt.Sprintf("stop %d tasks of phase %d", counts[p], p), Budget: budget})
+	}
+	return steps
+}
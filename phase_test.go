@@ -0,0 +1,71 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWithPhase 测试关闭按阶段依次取消任务并等待每个阶段结束
+func TestWithPhase(t *testing.T) {
+	m := New(WithTimeout(time.Second * 5))
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			<-ctx.Done()
+			time.Sleep(time.Millisecond * 10)
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}
+	}
+	m.CtxGo(record("infra"), WithPhase(2))
+	m.CtxGo(record("unphased"))
+	m.CtxGo(record("servers"), WithPhase(0))
+	m.CtxGo(record("workers"), WithPhase(1))
+
+	plan := m.DryRunShutdown()
+	phases := 0
+	for _, s := range plan.Steps {
+		if s.Phase == "phase" {
+			phases++
+		}
+	}
+	if phases != 3 {
+		t.Errorf("演练计划应包含3个阶段步骤，实际为%d", phases)
+	}
+
+	m.Shutdown()
+	expected := []string{"servers", "workers", "infra", "unphased"}
+	if len(order) != len(expected) {
+		t.Fatalf("停止顺序应为%v，实际为%v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("停止顺序应为%v，实际为%v", expected, order)
+		}
+	}
+}
+
+// TestWithPhaseTimeout 测试阶段超时后继续取消下一阶段
+func TestWithPhaseTimeout(t *testing.T) {
+	m := New(WithTimeout(time.Second*5), WithPhaseTimeout(0, time.Millisecond*20))
+
+	release := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) { <-release }, WithPhase(0))
+	var canceledAfter time.Duration
+	start := time.Now()
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		canceledAfter = time.Since(start)
+		close(release)
+	}, WithPhase(1))
+
+	m.Shutdown()
+	if canceledAfter > time.Millisecond*500 {
+		t.Errorf("阶段超时后应取消下一阶段，实际耗时%v", canceledAfter)
+	}
+}
@@ -0,0 +1,91 @@
+package graceful
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WithMetricsPush returns an Option that pushes lifecycle metrics describing
+// the shutdown to a Prometheus Pushgateway at gateway under the given job, in
+// the flush phase after the functions registered with OnFlush. Scrape-based
+// collection usually misses the last interval of a process's life, which is
+// the one that describes the shutdown. The push runs within the flush
+// timeout and replaces the metrics of the job, so the gateway keeps the last
+// shutdown of each instance when job includes the instance name.
+//
+// The pushed metrics are graceful_shutdown_began_timestamp_seconds,
+// graceful_shutdown_duration_seconds, graceful_shutdown_timed_out,
+// graceful_shutdown_abandoned_goroutines, graceful_shutdown_retried_hooks and
+// graceful_signals_received. To export through OTLP instead, register the
+// meter provider with ManageTelemetry.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithMetricsPush("http://pushgateway:9091", "api-"+hostname))
+func WithMetricsPush(gateway, job string) Option {
+	return func(m *Manager) {
+		m.pushURL = strings.TrimSuffix(gateway, "/") + "/metrics/job/" + url.PathEscape(job)
+	}
+}
+
+// pushMetrics pushes the lifecycle metrics of the shutdown that began at
+// began.
+func (m *Manager) pushMetrics(began time.Time, timedOut bool) {
+	if m.pushURL == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), m.flushTimeout)
+	defer cancel()
+
+	body := formatMetrics(m.report(began, timedOut))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, m.pushURL, body)
+	if err != nil {
+		m.logf("pushing shutdown metrics failed: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		m.logf("pushing shutdown metrics failed: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		m.logf("pushing shutdown metrics failed: %s", resp.Status)
+	}
+}
+
+// formatMetrics renders the report in the Prometheus text exposition format.
+func formatMetrics(r ShutdownReport) *bytes.Buffer {
+	var buf bytes.Buffer
+	gauge := func(name, help string, value float64) {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, help, name, name, value)
+	}
+	timedOut := 0.0
+	if r.TimedOut {
+		timedOut = 1
+	}
+	gauge("graceful_shutdown_began_timestamp_seconds", "Unix time the shutdown began.", float64(r.Began.UnixNano())/1e9)
+	gauge("graceful_shutdown_duration_seconds", "Time from the start of shutdown to the flush phase.", r.Duration.Seconds())
+	gauge("graceful_shutdown_timed_out", "Whether goroutines exceeded the shutdown timeout.", timedOut)
+	gauge("graceful_shutdown_abandoned_goroutines", "Managed goroutines still running when shutdown finished.", float64(r.Abandoned))
+	gauge("graceful_shutdown_retried_hooks", "Shutdown hooks that needed more than one attempt.", float64(retried(r.RetriedHooks)))
+	gauge("graceful_signals_received", "Signals received over the process lifetime.", float64(len(r.Signals)))
+	return &buf
+}
+
+// retried counts the hooks that needed more than one attempt.
+func retried(hooks []HookRetry) int {
+	n := 0
+	for _, h := range hooks {
+		if h.Attempts > 1 {
+			n++
+		}
+	}
+	return n
+}
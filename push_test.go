@@ -0,0 +1,63 @@
+package graceful
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithMetricsPush 测试关闭时在刷新阶段推送生命周期指标
+func TestWithMetricsPush(t *testing.T) {
+	var method, path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.EscapedPath(), string(data)
+	}))
+	defer srv.Close()
+
+	m := New(WithTimeout(time.Second), WithMetricsPush(srv.URL+"/", "api/1"))
+	m.Shutdown()
+
+	if method != http.MethodPut {
+		t.Errorf("应使用PUT推送，实际为%q", method)
+	}
+	if path != "/metrics/job/api%2F1" {
+		t.Errorf("推送路径不正确，实际为%q", path)
+	}
+	for _, want := range []string{
+		"# TYPE graceful_shutdown_duration_seconds gauge",
+		"graceful_shutdown_timed_out 0\n",
+		"graceful_shutdown_abandoned_goroutines 0\n",
+		"graceful_shutdown_began_timestamp_seconds ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("推送内容应包含%q，实际为:\n%s", want, body)
+		}
+	}
+}
+
+// TestWithMetricsPushFailure 测试推送失败不影响关闭
+func TestWithMetricsPushFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	logger := &recordingLogger{}
+	m := New(WithTimeout(time.Second), WithMetricsPush(srv.URL, "api"), WithLogger(logger))
+	if err := m.Shutdown(); err != nil {
+		t.Errorf("推送失败不应导致关闭出错，实际为%v", err)
+	}
+	found := false
+	for _, l := range logger.lines {
+		if strings.Contains(l, "pushing shutdown metrics failed") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("推送失败时应记录日志")
+	}
+}
@@ -0,0 +1,245 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Queue is the subset of a cloud queue API used by Consume. M is the
+// message type of the client library. Implementations map the methods as
+// follows:
+//
+//	           AWS SQS                             GCP Pub/Sub (pull)
+//	Receive    ReceiveMessage (long poll)          Pull
+//	Extend     ChangeMessageVisibilityBatch        ModifyAckDeadline
+//	Ack        DeleteMessage                       Acknowledge
+//	Nack       ChangeMessageVisibility(0)          ModifyAckDeadline(0)
+//
+// Receive must return promptly when its context is canceled.
+type Queue[M any] interface {
+	Receive(ctx context.Context) ([]M, error)
+	Extend(ctx context.Context, msgs []M) error
+	Ack(ctx context.Context, msg M) error
+	Nack(ctx context.Context, msgs []M) error
+}
+
+// ConsumerOption defines a function type for configuring Consume.
+type ConsumerOption func(*consumerConfig)
+
+// consumerConfig holds the settings applied by ConsumerOption values.
+type consumerConfig struct {
+	concurrency  int           // Maximum number of concurrent handlers
+	drainTimeout time.Duration // Time handlers get to finish after shutdown begins
+	retryDelay   time.Duration // Pause after a failed Receive
+	onError      func(error)   // Receives errors from queue operations
+}
+
+// WithConsumerConcurrency returns a ConsumerOption that sets how many messages
+// are handled at the same time. The default is 1.
+func WithConsumerConcurrency(n int) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.concurrency = n
+	}
+}
+
+// WithConsumerDrainTimeout returns a ConsumerOption that sets how long
+// in-flight handlers may keep running once shutdown begins. It should be
+// shorter than the manager's timeout. The default is 10 seconds.
+func WithConsumerDrainTimeout(timeout time.Duration) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.drainTimeout = timeout
+	}
+}
+
+// WithConsumerErrorHandler returns a ConsumerOption that sets a function to
+// receive errors returned by the queue, such as failed receives or acks.
+func WithConsumerErrorHandler(f func(error)) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.onError = f
+	}
+}
+
+// Consume starts a managed goroutine that receives messages from q and calls
+// handler for each of them. Messages are acknowledged when handler returns nil
+// and returned to the queue when it returns an error. Receiving pauses while
+// the manager is paused.
+//
+// When shutdown begins, Consume stops receiving, extends the visibility or
+// ack deadline of the messages still being handled, and waits up to the drain
+// timeout for their handlers. Handlers run with a context that is only
+// canceled when the drain timeout expires; messages whose handlers have not
+// finished by then are returned to the queue so that another instance can
+// pick them up immediately.
+//
+// Example:
+//
+//	graceful.Consume[types.Message](manager, sqsQueue, func(ctx context.Context, msg types.Message) error {
+//		return process(ctx, msg)
+//	}, graceful.WithConsumerConcurrency(8))
+func Consume[M any](m *Manager, q Queue[M], handler func(ctx context.Context, msg M) error, opts ...ConsumerOption) *Task {
+	cfg := consumerConfig{
+		concurrency:  1,
+		drainTimeout: time.Second * 10,
+		retryDelay:   time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.concurrency < 1 {
+		cfg.concurrency = 1
+	}
+
+	c := &consumer[M]{
+		m:        m,
+		q:        q,
+		handler:  handler,
+		cfg:      cfg,
+		sem:      make(chan struct{}, cfg.concurrency),
+		inFlight: make(map[int]M),
+	}
+	return m.CtxGo(c.run)
+}
+
+// consumer holds the state of a single Consume loop.
+type consumer[M any] struct {
+	m       *Manager
+	q       Queue[M]
+	handler func(ctx context.Context, msg M) error
+	cfg     consumerConfig
+	sem     chan struct{} // Limits concurrent handlers
+	wg      sync.WaitGroup
+
+	mu       sync.Mutex
+	nextID   int
+	inFlight map[int]M // Messages whose handlers have not finished
+}
+
+// run receives and dispatches messages until ctx is canceled, then drains.
+func (c *consumer[M]) run(ctx context.Context) {
+	// Handlers outlive ctx so that in-flight messages can finish during drain
+	handlerCtx, cancelHandlers := context.WithCancel(context.Background())
+	defer cancelHandlers()
+
+	var pending []M
+	for ctx.Err() == nil && len(pending) == 0 {
+		if c.m.WaitResumed(ctx) != nil {
+			break
+		}
+		msgs, err := c.q.Receive(ctx)
+		if ctx.Err() != nil {
+			pending = msgs
+			break
+		}
+		if err != nil {
+			c.reportError(err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(c.cfg.retryDelay):
+			}
+			continue
+		}
+		for i, msg := range msgs {
+			select {
+			case c.sem <- struct{}{}:
+				c.dispatch(handlerCtx, msg)
+			case <-ctx.Done():
+				pending = msgs[i:]
+			}
+			if len(pending) > 0 {
+				break
+			}
+		}
+	}
+
+	c.drain(cancelHandlers, pending)
+}
+
+// dispatch runs the handler for msg in its own goroutine.
+func (c *consumer[M]) dispatch(ctx context.Context, msg M) {
+	c.mu.Lock()
+	id := c.nextID
+	c.nextID++
+	c.inFlight[id] = msg
+	c.mu.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer func() { <-c.sem }()
+
+		err := c.handler(ctx, msg)
+
+		c.mu.Lock()
+		_, owned := c.inFlight[id]
+		delete(c.inFlight, id)
+		c.mu.Unlock()
+		if !owned {
+			// Already returned to the queue by drain
+			return
+		}
+
+		if err != nil {
+			c.reportError(c.q.Nack(context.Background(), []M{msg}))
+			return
+		}
+		c.reportError(c.q.Ack(context.Background(), msg))
+	}()
+}
+
+// drain returns undispatched messages, extends in-flight ones, waits for
+// their handlers and returns whatever did not finish in time.
+func (c *consumer[M]) drain(cancelHandlers context.CancelFunc, pending []M) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.drainTimeout)
+	defer cancel()
+
+	if len(pending) > 0 {
+		c.reportError(c.q.Nack(ctx, pending))
+	}
+	if msgs := c.takeInFlight(false); len(msgs) > 0 {
+		c.reportError(c.q.Extend(ctx, msgs))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	// Take ownership of unfinished messages before canceling their handlers
+	unfinished := c.takeInFlight(true)
+	cancelHandlers()
+	if len(unfinished) > 0 {
+		nackCtx, cancelNack := context.WithTimeout(context.Background(), time.Second)
+		defer cancelNack()
+		c.reportError(c.q.Nack(nackCtx, unfinished))
+	}
+}
+
+// takeInFlight returns the messages still being handled, removing them from
+// the in-flight set if remove is true.
+func (c *consumer[M]) takeInFlight(remove bool) []M {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	msgs := make([]M, 0, len(c.inFlight))
+	for id, msg := range c.inFlight {
+		msgs = append(msgs, msg)
+		if remove {
+			delete(c.inFlight, id)
+		}
+	}
+	return msgs
+}
+
+// reportError passes a non-nil error to the configured error handler.
+func (c *consumer[M]) reportError(err error) {
+	if err != nil && c.cfg.onError != nil {
+		c.cfg.onError(err)
+	}
+}
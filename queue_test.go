@@ -0,0 +1,125 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeQueue 是用于测试的内存队列
+type fakeQueue struct {
+	mu       sync.Mutex
+	messages []int
+	acked    []int
+	nacked   []int
+	extended []int
+}
+
+func (q *fakeQueue) Receive(ctx context.Context) ([]int, error) {
+	q.mu.Lock()
+	msgs := q.messages
+	q.messages = nil
+	q.mu.Unlock()
+	if len(msgs) > 0 {
+		return msgs, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (q *fakeQueue) Extend(ctx context.Context, msgs []int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.extended = append(q.extended, msgs...)
+	return nil
+}
+
+func (q *fakeQueue) Ack(ctx context.Context, msg int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.acked = append(q.acked, msg)
+	return nil
+}
+
+func (q *fakeQueue) Nack(ctx context.Context, msgs []int) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nacked = append(q.nacked, msgs...)
+	return nil
+}
+
+func (q *fakeQueue) snapshot() (acked, nacked, extended int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.acked), len(q.nacked), len(q.extended)
+}
+
+// TestConsume 测试消息处理成功时确认、失败时退回
+func TestConsume(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	q := &fakeQueue{messages: []int{1, 2, 3}}
+
+	handled := make(chan struct{}, 3)
+	Consume[int](m, q, func(ctx context.Context, msg int) error {
+		defer func() { handled <- struct{}{} }()
+		if msg == 2 {
+			return errors.New("处理失败")
+		}
+		return nil
+	}, WithConsumerConcurrency(2))
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-handled:
+		case <-time.After(time.Second):
+			t.Fatal("消息未被处理")
+		}
+	}
+	m.Shutdown()
+
+	acked, nacked, _ := q.snapshot()
+	if acked != 2 || nacked != 1 {
+		t.Errorf("应确认2条、退回1条消息，实际确认%d条、退回%d条", acked, nacked)
+	}
+}
+
+// TestConsumeDrain 测试关闭时延长进行中消息并退回未完成的消息
+func TestConsumeDrain(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	q := &fakeQueue{messages: []int{1, 2}}
+
+	started := make(chan struct{}, 2)
+	canceled := make(chan struct{}, 2)
+	Consume[int](m, q, func(ctx context.Context, msg int) error {
+		started <- struct{}{}
+		if msg == 1 {
+			// 在drain超时内完成
+			time.Sleep(time.Millisecond * 30)
+			return nil
+		}
+		// 超过drain超时，仅在上下文取消时退出
+		<-ctx.Done()
+		canceled <- struct{}{}
+		return ctx.Err()
+	}, WithConsumerConcurrency(2), WithConsumerDrainTimeout(time.Millisecond*100))
+
+	<-started
+	<-started
+	m.Shutdown()
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("drain超时后应取消处理函数")
+	}
+
+	acked, nacked, extended := q.snapshot()
+	if extended != 2 {
+		t.Errorf("关闭时应延长2条进行中的消息，实际延长%d条", extended)
+	}
+	if acked != 1 || nacked != 1 {
+		t.Errorf("应确认1条、退回1条消息，实际确认%d条、退回%d条", acked, nacked)
+	}
+}
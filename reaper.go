@@ -0,0 +1,120 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// ErrNoChildReaper is returned by WaitChild when the manager was not created
+// with WithChildReaper.
+var ErrNoChildReaper = errors.New("graceful: child reaper not enabled")
+
+// EventChildExited is emitted when the child reaper started by
+// WithChildReaper reaps a child process, with its status in Event.Child.
+const EventChildExited EventType = "child_exited"
+
+// maxChildExits bounds the exit statuses kept for WaitChild.
+const maxChildExits = 1024
+
+// ChildExit is the exit status of a child process reaped by the manager.
+type ChildExit struct {
+	Pid      int       // Process ID of the child
+	ExitCode int       // Exit code, or -1 if the child was killed by a signal
+	Signal   os.Signal // Signal that killed the child, if any
+}
+
+// WithChildReaper returns an Option that handles SIGCHLD and reaps every
+// exited child process as soon as it exits, not only at shutdown, so that
+// zombies do not accumulate. This is required for a process that runs as
+// PID 1 in a minimal container, where orphaned descendants are reparented to
+// it. Each reaped child is reported with EventChildExited, and its status is
+// kept for WaitChild.
+//
+// Because the reaper collects every child, exec.Cmd.Wait fails with "no
+// child processes" for commands started by the application; call WaitChild
+// with the command's process ID instead. The reaper stops once shutdown has
+// completed. It is only available on Unix systems other than AIX; elsewhere
+// this option has no effect.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithChildReaper())
+//	cmd := exec.Command("worker")
+//	cmd.Start()
+//	exit, err := manager.WaitChild(ctx, cmd.Process.Pid)
+func WithChildReaper() Option {
+	return func(m *Manager) {
+		m.childReaper = true
+	}
+}
+
+// WaitChild waits for the child process pid to be reaped by the reaper
+// started with WithChildReaper and returns its exit status. It returns ctx's
+// error if ctx is done first, and ErrNoChildReaper without the reaper. The
+// status of each child is returned to one caller only.
+func (m *Manager) WaitChild(ctx context.Context, pid int) (ChildExit, error) {
+	if !m.childReaper {
+		return ChildExit{}, ErrNoChildReaper
+	}
+	for {
+		m.childMu.Lock()
+		exit, ok := m.childExits[pid]
+		if ok {
+			delete(m.childExits, pid)
+		}
+		reaped := m.childReaped
+		m.childMu.Unlock()
+
+		if ok {
+			return exit, nil
+		}
+		select {
+		case <-ctx.Done():
+			return ChildExit{}, ctx.Err()
+		case <-reaped:
+		}
+	}
+}
+
+// startReaping prepares the exit status records and starts the reaper.
+func (m *Manager) startReaping() {
+	m.childExits = make(map[int]ChildExit)
+	m.childReaped = make(chan struct{})
+	stop := m.startChildReaper()
+	m.childMu.Lock()
+	m.stopReaper = stop
+	m.childMu.Unlock()
+}
+
+// stopReaping stops the reaper, if it was started, and restores the default
+// handling of SIGCHLD, so that children of code running after shutdown are
+// left for it to wait on.
+func (m *Manager) stopReaping() {
+	m.childMu.Lock()
+	stop := m.stopReaper
+	m.stopReaper = nil
+	m.childMu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// childExited records the status of a reaped child and wakes WaitChild
+// callers.
+func (m *Manager) childExited(exit ChildExit) {
+	m.childMu.Lock()
+	if len(m.childExits) >= maxChildExits {
+		// Forget an unclaimed status to make room
+		for pid := range m.childExits {
+			delete(m.childExits, pid)
+			break
+		}
+	}
+	m.childExits[exit.Pid] = exit
+	close(m.childReaped)
+	m.childReaped = make(chan struct{})
+	m.childMu.Unlock()
+
+	m.emit(Event{Type: EventChildExited, Child: &exit})
+}
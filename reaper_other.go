@@ -0,0 +1,9 @@
+//go:build !unix || aix
+
+package graceful
+
+// startChildReaper does nothing; child reaping is only available on Unix
+// systems other than AIX.
+func (m *Manager) startChildReaper() (stop func()) {
+	return func() {}
+}
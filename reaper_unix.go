@@ -0,0 +1,51 @@
+//go:build unix && !aix
+
+package graceful
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// startChildReaper reaps exited children whenever SIGCHLD arrives, until the
+// returned function is called.
+func (m *Manager) startChildReaper() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGCHLD)
+	done := make(chan struct{})
+	go func() {
+		for {
+			m.reapChildren()
+			select {
+			case <-ch:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// reapChildren reaps all children that have exited.
+func (m *Manager) reapChildren() {
+	for {
+		var status syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil || pid <= 0 {
+			return
+		}
+
+		exit := ChildExit{Pid: pid, ExitCode: status.ExitStatus()}
+		if status.Signaled() {
+			exit.Signal = status.Signal()
+		}
+		m.childExited(exit)
+	}
+}
@@ -0,0 +1,88 @@
+//go:build unix && !aix
+
+package graceful
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestReapChildren 测试回收子进程并通过WaitChild返回其退出状态
+func TestReapChildren(t *testing.T) {
+	events := make(chan Event, 2)
+	m := New(WithEventHandler(func(e Event) { events <- e }))
+	// 不安装SIGCHLD处理，直接轮询回收，以免影响其他测试
+	m.childReaper = true
+	m.childExits = make(map[int]ChildExit)
+	m.childReaped = make(chan struct{})
+
+	exited := exec.Command("sh", "-c", "exit 3")
+	killed := exec.Command("sleep", "10")
+	if err := exited.Start(); err != nil {
+		t.Skipf("无法启动子进程: %v", err)
+	}
+	if err := killed.Start(); err != nil {
+		t.Skipf("无法启动子进程: %v", err)
+	}
+	killed.Process.Signal(syscall.SIGTERM)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Millisecond * 5):
+				m.reapChildren()
+			}
+		}
+	}()
+
+	exit, err := m.WaitChild(ctx, exited.Process.Pid)
+	if err != nil || exit.ExitCode != 3 || exit.Signal != nil {
+		t.Errorf("应返回退出码3，实际为%+v和%v", exit, err)
+	}
+	exit, err = m.WaitChild(ctx, killed.Process.Pid)
+	if err != nil || exit.ExitCode != -1 || exit.Signal != syscall.SIGTERM {
+		t.Errorf("应返回SIGTERM，实际为%+v和%v", exit, err)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case e := <-events:
+			if e.Type != EventChildExited || e.Child == nil {
+				t.Errorf("应发出child_exited事件，实际为%+v", e)
+			}
+		case <-ctx.Done():
+			t.Fatal("每个回收的子进程应发出child_exited事件")
+		}
+	}
+}
+
+// TestWaitChildWithoutReaper 测试未启用回收时返回ErrNoChildReaper
+func TestWaitChildWithoutReaper(t *testing.T) {
+	m := New()
+	if _, err := m.WaitChild(context.Background(), 1); err != ErrNoChildReaper {
+		t.Errorf("应返回ErrNoChildReaper，实际为%v", err)
+	}
+}
+
+// TestChildReaperStopsAfterShutdown 测试关闭完成后停止回收子进程，不影响之后启动的命令
+func TestChildReaperStopsAfterShutdown(t *testing.T) {
+	m := New(WithChildReaper())
+	if err := m.Shutdown(); err != nil {
+		t.Fatalf("关闭不应返回错误，实际为%v", err)
+	}
+	if m.stopReaper != nil {
+		t.Error("关闭后回收器应已停止")
+	}
+
+	if err := exec.Command("sh", "-c", "exit 0").Run(); err != nil {
+		t.Errorf("关闭后启动的命令应能正常等待，实际为%v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+)
+
+// EventShutdownRequested is emitted when ShutdownWithReason starts a
+// shutdown, with the *ShutdownReason as the event error.
+const EventShutdownRequested EventType = "shutdown_requested"
+
+// ShutdownReason is the cause of a shutdown started with ShutdownWithReason.
+// It is the context.Cause of the manager's context and of task contexts once
+// they are canceled, and Unwrap returns the error it was given.
+type ShutdownReason struct {
+	Reason string // Why the shutdown was requested, such as "license expired"
+	Err    error  // Error that led to the shutdown, if any
+}
+
+func (r *ShutdownReason) Error() string {
+	if r.Err == nil {
+		return "graceful: shutdown requested: " + r.Reason
+	}
+	return fmt.Sprintf("graceful: shutdown requested: %s: %v", r.Reason, r.Err)
+}
+
+// Unwrap returns the error that led to the shutdown.
+func (r *ShutdownReason) Unwrap() error {
+	return r.Err
+}
+
+// ShutdownWithReason shuts down like Shutdown, recording why. The reason is
+// the context.Cause of canceled contexts, is emitted with
+// EventShutdownRequested, logged, and stored in the shutdown report, and Run
+// exits with the code ExitCodes.Reasons maps it to, or with the TaskError
+// code if err is non-nil. A non-nil err is also joined to what
+// ShutdownWithReason and a pending Wait return, so code-initiated shutdowns
+// such as a fatal config error are as visible as signal-initiated ones.
+// Only the first reason is kept; a call made once shutdown has begun is
+// ignored and returns what Shutdown returns.
+//
+// Example:
+//
+//	if err := license.Check(ctx); err != nil {
+//		manager.ShutdownWithReason("license expired", err)
+//	}
+func (m *Manager) ShutdownWithReason(reason string, err error) error {
+	r := &ShutdownReason{Reason: reason, Err: err}
+	if m.shutDown() || !m.reason.CompareAndSwap(nil, r) {
+		return m.Shutdown()
+	}
+	m.logf("%v", r)
+	m.emit(Event{Type: EventShutdownRequested, Err: r})
+	m.requestStop(nil)
+	// A pending Wait may start the shutdown first; this call still reports it
+	_, timedOut := m.shutdown()
+	return m.shutdownResult(timedOut)
+}
+
+// cause returns the reason given to ShutdownWithReason as an error, or nil.
+func (m *Manager) cause() error {
+	if r := m.reason.Load(); r != nil {
+		return r
+	}
+	return nil
+}
+
+// failures returns the reason given to ShutdownWithReason if it carries an
+// error, followed by the errors recorded from failed tasks.
+func (m *Manager) failures() []error {
+	var errs []error
+	if r := m.reason.Load(); r != nil && r.Err != nil {
+		errs = append(errs, r)
+	}
+	return append(errs, m.taskErrors()...)
+}
+
+// withShutdownCause derives a context from parent whose cancel function
+// cancels it with the reason given to ShutdownWithReason, if any.
+func (m *Manager) withShutdownCause(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	return ctx, func() { cancel(m.cause()) }
+}
@@ -0,0 +1,114 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestShutdownWithReason 测试关闭原因传递到上下文、事件和报告
+func TestShutdownWithReason(t *testing.T) {
+	var events []Event
+	store := FileReportStore(filepath.Join(t.TempDir(), "shutdown.json"))
+	m := New(WithTimeout(time.Second), WithReportStore(store), WithEventHandler(func(e Event) {
+		events = append(events, e)
+	}))
+
+	cause := errors.New("license expired at midnight")
+	causes := make(chan error, 1)
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+	})
+
+	err := m.ShutdownWithReason("license expired", cause)
+	if !errors.Is(err, cause) {
+		t.Errorf("应返回关闭原因中的错误，实际为%v", err)
+	}
+
+	var reason *ShutdownReason
+	if got := <-causes; !errors.As(got, &reason) || reason.Reason != "license expired" {
+		t.Errorf("任务上下文的Cause应为关闭原因，实际为%v", got)
+	}
+	if len(events) == 0 || events[0].Type != EventShutdownRequested || !errors.Is(events[0].Err, cause) {
+		t.Errorf("应发出EventShutdownRequested事件，实际为%v", events)
+	}
+	if r, err := store.Load(context.Background()); err != nil || r == nil || r.Reason != "license expired: license expired at midnight" {
+		t.Errorf("报告应记录关闭原因，实际为%+v, %v", r, err)
+	}
+
+	if err := m.ShutdownWithReason("again", nil); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("重复调用应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+	if r := m.reason.Load(); r.Reason != "license expired" {
+		t.Errorf("重复调用应保留第一个原因，实际为%v", r)
+	}
+	if err := m.Shutdown(); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("带原因关闭后再次关闭应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+}
+
+// TestShutdownWithReasonUnblocksWait 测试带原因关闭使Wait返回该错误
+func TestShutdownWithReasonUnblocksWait(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.Go(func() {})
+
+	waited := make(chan error, 1)
+	go func() { waited <- m.Wait() }()
+	time.Sleep(time.Millisecond * 20)
+
+	cause := errors.New("配置无效")
+	m.ShutdownWithReason("fatal config", cause)
+	select {
+	case err := <-waited:
+		if !errors.Is(err, cause) || errors.Is(err, ErrAlreadyShutdown) {
+			t.Errorf("Wait应返回关闭原因，实际为%v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("带原因关闭后Wait应返回")
+	}
+}
+
+// TestShutdownWithReasonDuringRehearsal 测试演练期间请求的关闭会保留原因
+func TestShutdownWithReasonDuringRehearsal(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.beginGeneration()
+
+	done := make(chan error, 1)
+	m.OnDrain(func(ctx context.Context) error {
+		go func() { done <- m.ShutdownWithReason("配置错误", nil) }()
+		waitFor(t, func() bool { return m.reason.Load() != nil }, "演练期间应记录关闭原因")
+		return nil
+	})
+	if _, err := m.Rehearse(); err != nil {
+		t.Fatalf("演练失败: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("关闭不应返回错误，实际为%v", err)
+	}
+	if r := m.reason.Load(); r == nil || r.Reason != "配置错误" {
+		t.Errorf("关闭原因应为配置错误，实际为%v", r)
+	}
+}
+
+// TestExitCodeReason 测试关闭原因决定退出码
+func TestExitCodeReason(t *testing.T) {
+	codes := DefaultExitCodes()
+	codes.Reasons = map[string]int{"license expired": 5}
+
+	cases := []struct {
+		reason *ShutdownReason
+		want   int
+	}{
+		{&ShutdownReason{Reason: "license expired"}, 5},
+		{&ShutdownReason{Reason: "fatal config", Err: errors.New("bad")}, codes.TaskError},
+		{&ShutdownReason{Reason: "maintenance"}, codes.Clean},
+	}
+	for _, c := range cases {
+		if got := codes.code(outcome{reason: c.reason}); got != c.want {
+			t.Errorf("原因%q的退出码应为%d，实际为%d", c.reason.Reason, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Refresher keeps a value fresh by fetching it periodically in a managed
+// goroutine, such as an access token or remote configuration.
+type Refresher[T any] struct {
+	m     *Manager
+	fetch func(ctx context.Context) (T, error)
+	ctx   context.Context // Context for fetches, canceled at shutdown
+
+	mu        sync.Mutex
+	value     T
+	fetched   bool      // Whether a fetch has succeeded yet
+	fetchedAt time.Time // Time of the last successful fetch
+	err       error     // Error of the last fetch, nil once one succeeds
+	call      *refreshCall
+}
+
+// refreshCall is a fetch in progress, shared by all callers.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// Refresh starts a managed goroutine that calls fetch right away and then
+// once per interval, keeping the latest value for Get. A failed fetch keeps
+// the previous value, so callers can decide how stale is too stale. Fetching
+// is skipped while the manager is paused and stops when shutdown begins.
+//
+// Example:
+//
+//	token := graceful.Refresh(manager, 5*time.Minute, func(ctx context.Context) (string, error) {
+//		return auth.Token(ctx)
+//	})
+//	...
+//	if tok, age, ok := token.Get(); ok && age < 10*time.Minute {
+//		req.Header.Set("Authorization", "Bearer "+tok)
+//	}
+func Refresh[T any](m *Manager, interval time.Duration, fetch func(ctx context.Context) (T, error)) *Refresher[T] {
+	r := &Refresher[T]{m: m, fetch: fetch, ctx: m.Context()}
+	m.CtxGo(func(ctx context.Context) {
+		_, _ = r.Now(ctx)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if m.Paused() {
+				if m.WaitResumed(ctx) != nil {
+					return
+				}
+				ticker.Reset(interval)
+			}
+			_, _ = r.Now(ctx)
+		}
+	})
+	return r
+}
+
+// Get returns the latest value, how long ago it was fetched, and whether any
+// fetch has succeeded yet.
+func (r *Refresher[T]) Get() (value T, age time.Duration, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.fetched {
+		return value, 0, false
+	}
+	return r.value, time.Since(r.fetchedAt), true
+}
+
+// Err returns the error of the last fetch, or nil if it succeeded.
+func (r *Refresher[T]) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Now fetches the value immediately, for example after the server rejected
+// a cached token, and returns it. Concurrent calls share a single fetch. The
+// fetch itself runs as a managed goroutine with the manager's context, so it
+// stops at shutdown; ctx only bounds how long Now waits for it.
+func (r *Refresher[T]) Now(ctx context.Context) (T, error) {
+	r.mu.Lock()
+	call := r.call
+	if call == nil {
+		call = &refreshCall{done: make(chan struct{})}
+		r.call = call
+		r.m.Go(func() { r.run(call) })
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.value, call.err
+}
+
+// run performs a shared fetch and records its result.
+func (r *Refresher[T]) run(call *refreshCall) {
+	v, err := r.fetch(r.ctx)
+
+	r.mu.Lock()
+	if err == nil {
+		r.value, r.fetched, r.fetchedAt = v, true, time.Now()
+	}
+	r.err, call.err = err, err
+	r.call = nil
+	r.mu.Unlock()
+	close(call.done)
+}
@@ -0,0 +1,78 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRefresh 测试后台刷新保持值最新并在失败时保留旧值
+func TestRefresh(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var calls atomic.Int32
+	r := Refresh(m, time.Millisecond*20, func(ctx context.Context) (int, error) {
+		n := calls.Add(1)
+		if n == 2 {
+			return 0, errors.New("刷新失败")
+		}
+		return int(n), nil
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond * 5)
+	}
+	m.Shutdown()
+
+	v, age, ok := r.Get()
+	if !ok {
+		t.Fatal("应已获取到值")
+	}
+	if v < 3 {
+		t.Errorf("值应已刷新，实际为%d", v)
+	}
+	if age < 0 || age > time.Second {
+		t.Errorf("值的时长不合理: %v", age)
+	}
+
+	stopped := calls.Load()
+	time.Sleep(time.Millisecond * 50)
+	if calls.Load() != stopped {
+		t.Error("关闭后不应继续刷新")
+	}
+}
+
+// TestRefreshNow 测试并发的立即刷新共享同一次获取
+func TestRefreshNow(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	r := Refresh(m, time.Hour, func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "token", nil
+	})
+
+	results := make(chan string, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			v, _ := r.Now(context.Background())
+			results <- v
+		}()
+	}
+	time.Sleep(time.Millisecond * 20)
+	close(release)
+	for i := 0; i < 3; i++ {
+		if v := <-results; v != "token" {
+			t.Errorf("应返回token，实际为%q", v)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("并发刷新应只获取一次，实际为%d次", calls.Load())
+	}
+}
@@ -0,0 +1,109 @@
+package graceful
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// EventRehearsed is emitted after a shutdown rehearsal has brought the
+// application back up, with the report in Event.Rehearsal.
+const EventRehearsed EventType = "rehearsed"
+
+// ErrShutdownInProgress is returned by Rehearse and Restart when the manager
+// is already draining for a rehearsal or restart.
+var ErrShutdownInProgress = errors.New("graceful: shutdown in progress")
+
+// RehearsalReport measures the phases of a shutdown rehearsal.
+type RehearsalReport struct {
+	Drain    time.Duration // Time spent finishing streams, in drain and handoff functions and the drain strategy
+	Wait     time.Duration // Time spent waiting for goroutines to exit
+	Hooks    time.Duration // Time spent flushing write-behind components and in shutdown hooks
+	Flush    time.Duration // Time spent in flush functions
+	Restart  time.Duration // Time spent in the start functions
+	Total    time.Duration // Duration of the whole rehearsal
+	TimedOut bool          // Whether goroutines exceeded the shutdown timeout
+	Error    string        // Error returned by a start function, if any
+}
+
+// Rehearse runs the same sequence as Restart — readiness turns false, the
+// running generation is torn down within the shutdown timeout and flush
+// functions run — then calls the start functions passed to Run again, and
+// reports how long each phase took. Teams can use it on game days to
+// rehearse and measure the shutdown path in production without terminating
+// instances.
+//
+// Like Restart, it only runs and drops the drain functions, shutdown hooks
+// and servers registered once Run called the start functions, which register
+// them again. Those registered before Run belong to the process: they are
+// left alone, so the components they would tear down keep working, and run
+// at the final shutdown. If a real shutdown begins during the rehearsal,
+// readiness stays false.
+func (m *Manager) Rehearse() (RehearsalReport, error) {
+	if m.shutDown() {
+		return RehearsalReport{}, ErrAlreadyShutdown
+	}
+	if !m.draining.CompareAndSwap(false, true) {
+		return RehearsalReport{}, ErrShutdownInProgress
+	}
+	defer m.endDraining()
+
+	var r RehearsalReport
+	begin := time.Now()
+	timeoutCtx, cancel := context.WithTimeout(withRestart(context.Background()), m.timeout)
+	r.TimedOut = m.stopGeneration(timeoutCtx, nil, &r)
+	cancel()
+	m.reopenStreams()
+
+	phase := time.Now()
+	m.runFlushers()
+	r.Flush = time.Since(phase)
+
+	phase = time.Now()
+	if err := m.restartGeneration(); err != nil {
+		r.Error = err.Error()
+	}
+	r.Restart = time.Since(phase)
+	r.Total = time.Since(begin)
+
+	m.logf("shutdown rehearsal took %v: drain %v, wait %v, hooks %v, flush %v, restart %v, timed out %v",
+		r.Total, r.Drain, r.Wait, r.Hooks, r.Flush, r.Restart, r.TimedOut)
+	m.emit(Event{Type: EventRehearsed, Rehearsal: &r})
+	return r, nil
+}
+
+// endDraining reports readiness again after a rehearsal or restart, unless a
+// real shutdown began meanwhile.
+func (m *Manager) endDraining() {
+	// Shutdown moves to StateDraining before setting draining, so checking
+	// the state after the reset cannot miss one that began
+	m.draining.Store(false)
+	if m.shutDown() {
+		m.draining.Store(true)
+	}
+}
+
+// RehearsalHandler returns an HTTP handler that runs Rehearse on POST and
+// responds with the report as JSON. Mount it on an admin-only listener.
+//
+// Example:
+//
+//	admin.Handle("/admin/rehearse-shutdown", manager.RehearsalHandler())
+func (m *Manager) RehearsalHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		report, err := m.Rehearse()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
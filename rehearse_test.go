@@ -0,0 +1,210 @@
+package graceful
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRehearse 测试演练只排空并重新启动运行中的一代组件，进程级的排空函数和钩子保留到最终关闭
+func TestRehearse(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var processDrains, processHooks, drains, hooks, starts int
+	var readyDuringDrain bool
+	m.OnDrain(func(ctx context.Context) error {
+		processDrains++
+		return nil
+	})
+	m.OnShutdown(func(ctx context.Context) error {
+		processHooks++
+		return nil
+	})
+	m.mu.Lock()
+	m.starts = append(m.starts, func(ctx context.Context) error {
+		starts++
+		m.OnDrain(func(ctx context.Context) error {
+			drains++
+			readyDuringDrain = m.IsReady()
+			return nil
+		})
+		m.OnShutdown(func(ctx context.Context) error {
+			hooks++
+			return nil
+		})
+		m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+		return nil
+	})
+	start := m.starts[0]
+	m.mu.Unlock()
+	m.beginGeneration()
+	_ = start(m.Context())
+	m.markReady()
+
+	old := m.Context()
+	for i := 0; i < 2; i++ {
+		report, err := m.Rehearse()
+		if err != nil {
+			t.Fatalf("演练失败: %v", err)
+		}
+		if report.TimedOut || report.Total <= 0 {
+			t.Errorf("报告不正确: %+v", report)
+		}
+	}
+	if drains != 2 || hooks != 2 || starts != 3 {
+		t.Errorf("应排空2次、运行钩子2次、启动3次，实际为%d、%d、%d", drains, hooks, starts)
+	}
+	if processDrains != 0 || processHooks != 0 {
+		t.Errorf("演练不应运行进程级的排空函数和钩子，实际为%d、%d", processDrains, processHooks)
+	}
+	if readyDuringDrain {
+		t.Error("演练排空期间不应就绪")
+	}
+	if !m.IsReady() {
+		t.Error("演练结束后应恢复就绪")
+	}
+	if old.Err() == nil || m.Context().Err() != nil {
+		t.Error("演练应取消旧一代的上下文并创建新的上下文")
+	}
+	if s := m.Summary(); s.DrainFunctions != 2 || s.ShutdownHooks != 2 {
+		t.Errorf("演练后不应重复注册排空函数和钩子，实际为%d、%d", s.DrainFunctions, s.ShutdownHooks)
+	}
+
+	m.Shutdown()
+	if drains != 3 || hooks != 3 || processDrains != 1 || processHooks != 1 {
+		t.Errorf("关闭时应各运行一次当前一代和进程级的排空函数和钩子，实际为%d、%d、%d、%d", drains, hooks, processDrains, processHooks)
+	}
+}
+
+// TestRehearseKeepsProcessComponents 测试演练不会关闭在Run之前注册的组件
+func TestRehearseKeepsProcessComponents(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	gate := m.RequestGate()
+	getValue := OnceValue(m, func(ctx context.Context) (*int, error) { return new(int), nil },
+		func(ctx context.Context, v *int) error { *v = -1; return nil })
+	if _, err := getValue(); err != nil {
+		t.Fatal(err)
+	}
+	m.beginGeneration()
+	m.markReady()
+
+	if _, err := m.Rehearse(); err != nil {
+		t.Fatalf("演练失败: %v", err)
+	}
+	if err := gate.Do(func() error { return nil }); err != nil {
+		t.Errorf("演练后进程级的请求闸门应保持打开，实际为%v", err)
+	}
+	if v, _ := getValue(); *v != 0 {
+		t.Error("演练不应清理进程级的单例")
+	}
+}
+
+// TestRehearseRecreatesOnceValue 测试演练清理运行中一代创建的单例后会重新初始化
+func TestRehearseRecreatesOnceValue(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	inits := 0
+	getValue := OnceValue(m, func(ctx context.Context) (int, error) { inits++; return inits, nil },
+		func(ctx context.Context, v int) error { return nil })
+	m.beginGeneration()
+	first, _ := getValue()
+
+	if _, err := m.Rehearse(); err != nil {
+		t.Fatalf("演练失败: %v", err)
+	}
+	if second, _ := getValue(); second == first {
+		t.Error("演练清理单例后应重新初始化")
+	}
+}
+
+// TestRehearseKeepsHTTPServers 测试演练只关闭运行中一代注册的HTTP服务器，保留在Run之前注册的服务器
+func TestRehearseKeepsHTTPServers(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	serve := func(opts ...ServerOption) string {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.HTTPServer(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})}, ln, opts...)
+		return "http://" + ln.Addr().String()
+	}
+	urls := []string{serve(), serve(ShutdownLast())}
+	m.beginGeneration()
+	generation := serve()
+
+	if _, err := m.Rehearse(); err != nil {
+		t.Fatalf("演练失败: %v", err)
+	}
+	if _, err := http.Get(generation); err == nil {
+		t.Error("演练应关闭运行中一代注册的服务器")
+	}
+	for _, url := range urls {
+		resp, err := http.Get(url)
+		if err != nil {
+			t.Errorf("演练后服务器应继续服务: %v", err)
+			continue
+		}
+		resp.Body.Close()
+	}
+}
+
+// TestRehearseDuringShutdown 测试演练期间开始真正的关闭时，演练结束后不会恢复就绪
+func TestRehearseDuringShutdown(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.markReady()
+
+	var once sync.Once
+	shutdownDone := make(chan struct{})
+	m.beginGeneration()
+	m.OnDrain(func(ctx context.Context) error {
+		once.Do(func() {
+			go func() {
+				m.Shutdown()
+				close(shutdownDone)
+			}()
+			waitFor(t, m.ShuttingDown, "应开始关闭")
+		})
+		return nil
+	})
+
+	if _, err := m.Rehearse(); err != nil {
+		t.Fatalf("演练失败: %v", err)
+	}
+	if m.IsReady() {
+		t.Error("演练期间开始关闭后不应恢复就绪")
+	}
+	<-shutdownDone
+}
+
+// TestRehearsalHandler 测试演练HTTP端点返回JSON报告
+func TestRehearsalHandler(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+	h := m.RehearsalHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET应返回405，实际为%d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	var report RehearsalReport
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("解析报告失败: %v", err)
+	}
+	if rec.Code != http.StatusOK || report.Total <= 0 {
+		t.Errorf("POST应返回报告，实际状态码%d，报告%+v", rec.Code, report)
+	}
+}
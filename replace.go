@@ -0,0 +1,119 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTaskNotFound is returned when no running task has the requested name.
+	ErrTaskNotFound = errors.New("graceful: task not found")
+	// ErrTaskExited is returned by Replace when the replacement task returned
+	// before reporting readiness.
+	ErrTaskExited = errors.New("graceful: task exited before becoming ready")
+	// ErrReadyTimeout is returned by Replace, and is the cancellation cause of
+	// the replacement task, when the replacement did not become ready within
+	// the manager's timeout.
+	ErrReadyTimeout = errors.New("graceful: task did not become ready in time")
+	// ErrReplaced is the cancellation cause of a task stopped by Replace.
+	ErrReplaced = errors.New("graceful: task replaced")
+)
+
+// register adds a task to the set of live tasks and, if it is named, to the
+// registry.
+func (m *Manager) register(t *Task) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.live == nil {
+		m.live = make(map[*Task]struct{})
+	}
+	m.seq++
+	t.seq = m.seq
+	m.live[t] = struct{}{}
+
+	if t.name == "" {
+		return
+	}
+	if m.tasks == nil {
+		m.tasks = make(map[string]*Task)
+	}
+	m.tasks[t.name] = t
+}
+
+// unregister removes a task from the set of live tasks, and a named task
+// from the registry unless it has already been superseded by a newer task
+// with the same name.
+func (m *Manager) unregister(t *Task) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.live, t)
+	if t.name != "" && m.tasks[t.name] == t {
+		delete(m.tasks, t.name)
+	}
+}
+
+// Task returns the running task with the given name, or nil if there is none.
+func (m *Manager) Task(name string) *Task {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tasks[name]
+}
+
+// Replace performs a blue/green swap of the named task. It starts f as a new
+// task with the same name, waits for it to call Ready, and only then cancels
+// the old task with ErrReplaced and waits for it to return, bounded by the
+// manager's timeout. Work is therefore never left without a running worker.
+//
+// If the new task returns before becoming ready, Replace returns
+// ErrTaskExited, and if it does not become ready within the manager's
+// timeout, Replace cancels it and returns ErrReadyTimeout; either way the old
+// task keeps running. If no task has the given name, Replace returns
+// ErrTaskNotFound without starting anything.
+//
+// Example:
+//
+//	newTask, err := manager.Replace("consumer", func(ctx context.Context) {
+//		conn := connect(ctx, newConfig)
+//		graceful.Ready(ctx)
+//		consume(ctx, conn)
+//	})
+func (m *Manager) Replace(name string, f func(ctx context.Context), opts ...TaskOption) (*Task, error) {
+	old := m.Task(name)
+	if old == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	var cfg taskConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.name = name
+
+	// Keep the old task registered until the new one is ready
+	t := m.newTask(cfg)
+	m.run(t, f)
+
+	ready := time.NewTimer(m.timeout)
+	defer ready.Stop()
+	select {
+	case <-t.ready:
+	case <-t.done:
+		return nil, ErrTaskExited
+	case <-ready.C:
+		t.Cancel(ErrReadyTimeout)
+		return nil, ErrReadyTimeout
+	}
+
+	m.register(t)
+	old.Cancel(ErrReplaced)
+
+	timer := time.NewTimer(m.timeout)
+	defer timer.Stop()
+	select {
+	case <-old.done:
+	case <-timer.C:
+		// The old task is abandoned; its context stays canceled
+	}
+	return t, nil
+}
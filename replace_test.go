@@ -0,0 +1,117 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestReplace 测试蓝绿替换：新任务就绪后才停止旧任务
+func TestReplace(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	oldCause := make(chan error, 1)
+	old := m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		oldCause <- context.Cause(ctx)
+	}, WithName("worker"))
+
+	if m.Task("worker") != old {
+		t.Fatal("注册表中应能找到命名任务")
+	}
+
+	proceed := make(chan struct{})
+	result := make(chan *Task, 1)
+	go func() {
+		task, err := m.Replace("worker", func(ctx context.Context) {
+			<-proceed
+			Ready(ctx)
+			<-ctx.Done()
+		})
+		if err != nil {
+			t.Errorf("替换不应返回错误，实际为%v", err)
+		}
+		result <- task
+	}()
+
+	time.Sleep(time.Millisecond * 50)
+	if old.Context().Err() != nil {
+		t.Error("新任务就绪前不应停止旧任务")
+	}
+	close(proceed)
+
+	var replacement *Task
+	select {
+	case replacement = <-result:
+	case <-time.After(time.Second):
+		t.Fatal("替换未完成")
+	}
+	if got := <-oldCause; got != ErrReplaced {
+		t.Errorf("旧任务取消原因应为ErrReplaced，实际为%v", got)
+	}
+	if m.Task("worker") != replacement || replacement.Name() != "worker" {
+		t.Error("注册表应指向新任务")
+	}
+}
+
+// TestReplaceErrors 测试替换不存在的任务或新任务提前退出
+func TestReplaceErrors(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	if _, err := m.Replace("missing", func(ctx context.Context) {}); err != ErrTaskNotFound {
+		t.Errorf("替换不存在的任务应返回ErrTaskNotFound，实际为%v", err)
+	}
+
+	old := m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+	}, WithName("worker"))
+
+	if _, err := m.Replace("worker", func(ctx context.Context) {}); err != ErrTaskExited {
+		t.Errorf("新任务未就绪即退出应返回ErrTaskExited，实际为%v", err)
+	}
+	if old.Context().Err() != nil {
+		t.Error("替换失败时旧任务应继续运行")
+	}
+	if m.Task("worker") != old {
+		t.Error("替换失败时注册表应仍指向旧任务")
+	}
+}
+
+// TestReplaceReadyTimeout 测试新任务在超时内未就绪时替换失败并取消新任务
+func TestReplaceReadyTimeout(t *testing.T) {
+	m := New(WithTimeout(100 * time.Millisecond))
+	defer m.Shutdown()
+
+	old := m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+	}, WithName("worker"))
+
+	causes := make(chan error, 1)
+	if _, err := m.Replace("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+	}); err != ErrReadyTimeout {
+		t.Errorf("新任务未在超时内就绪应返回ErrReadyTimeout，实际为%v", err)
+	}
+	if cause := <-causes; cause != ErrReadyTimeout {
+		t.Errorf("未就绪的新任务应以ErrReadyTimeout取消，实际为%v", cause)
+	}
+	if old.Context().Err() != nil || m.Task("worker") != old {
+		t.Error("替换超时时旧任务应继续运行并保留在注册表中")
+	}
+}
+
+// TestTaskRegistry 测试命名任务退出后从注册表移除
+func TestTaskRegistry(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	task := m.CtxGo(func(ctx context.Context) {}, WithName("once"))
+	<-task.Done()
+
+	if m.Task("once") != nil {
+		t.Error("任务退出后应从注册表移除")
+	}
+	m.Shutdown()
+}
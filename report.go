@@ -0,0 +1,179 @@
+package graceful
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// ShutdownReport describes how a shutdown went. It is saved to the store set
+// with WithReportStore and loaded again by the next process.
+type ShutdownReport struct {
+	Began          time.Time        // When shutdown began
+	Duration       time.Duration    // Time from the start of shutdown to the end of the flush phase
+	TimedOut       bool             // Whether goroutines exceeded the shutdown timeout
+	Abandoned      int              // Managed goroutines still running when shutdown finished
+	AbandonedTasks []string         // Names of the named tasks among them
+	Signals        []string         // Signals received over the process lifetime, oldest first
+	RetriedHooks   []HookRetry      // Attempts of the hooks registered with OnShutdownRetry
+	AbandonedHooks []string         // Hooks that did not return within the hook timeout
+	Reason         string           // Reason given to ShutdownWithReason, with its error
+	Blocked        []StuckGoroutine // Goroutines found blocked by WithDeadlockDetection at the timeout
+
+	Resources ResourceSnapshot // Resource usage sampled at the end of shutdown
+}
+
+// String summarizes the report in one line.
+func (r ShutdownReport) String() string {
+	var s string
+	if r.TimedOut {
+		s = fmt.Sprintf("shutdown at %s timed out after %v abandoning %d goroutines",
+			r.Began.Format(time.RFC3339), r.Duration.Round(time.Millisecond), r.Abandoned)
+		if len(r.AbandonedTasks) > 0 {
+			s += " (" + strings.Join(r.AbandonedTasks, ", ") + ")"
+		}
+		if len(r.Blocked) > 0 {
+			s += "; " + (&DeadlockError{Goroutines: r.Blocked}).summary()
+		}
+	} else {
+		s = fmt.Sprintf("shutdown at %s took %v", r.Began.Format(time.RFC3339), r.Duration.Round(time.Millisecond))
+	}
+	if r.Reason != "" {
+		s += "; reason: " + r.Reason
+	}
+	return s
+}
+
+// ReportStore persists shutdown reports across restarts, for example in a
+// file, an object store or a metrics push gateway.
+type ReportStore interface {
+	// Save persists the report of the shutdown that just completed.
+	Save(ctx context.Context, r ShutdownReport) error
+	// Load returns the report saved by the previous process, or nil if there
+	// is none.
+	Load(ctx context.Context) (*ShutdownReport, error)
+}
+
+// WithReportStore returns an Option that saves a ShutdownReport to store as
+// the last step of shutdown, after the flush phase, and loads the previous
+// report at startup to log it, for example "previous shutdown at ... timed
+// out after 30s abandoning 2 goroutines". Both run within the flush timeout.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithReportStore(graceful.FileReportStore("/var/lib/app/shutdown.json")))
+func WithReportStore(store ReportStore) Option {
+	return func(m *Manager) {
+		m.reportStore = store
+	}
+}
+
+// FileReportStore returns a ReportStore that keeps the report as JSON in the
+// file at path.
+func FileReportStore(path string) ReportStore {
+	return fileReportStore(path)
+}
+
+// fileReportStore is a ReportStore backed by a file.
+type fileReportStore string
+
+// Save writes the report to a temporary file and renames it into place, so
+// that a crash while saving leaves the previous report intact.
+func (s fileReportStore) Save(ctx context.Context, r ShutdownReport) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	tmp := string(s) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, string(s))
+}
+
+// Load reads the report from the file.
+func (s fileReportStore) Load(ctx context.Context) (*ShutdownReport, error) {
+	data, err := os.ReadFile(string(s))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r ShutdownReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// logPreviousReport logs the report saved by the previous process.
+func (m *Manager) logPreviousReport() {
+	if m.reportStore == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), m.flushTimeout)
+	defer cancel()
+	r, err := m.reportStore.Load(ctx)
+	if err != nil {
+		m.logf("loading previous shutdown report failed: %v", err)
+		return
+	}
+	if r != nil {
+		m.logf("previous %v", r)
+	}
+}
+
+// saveReport saves the report of the shutdown that began at began, once.
+func (m *Manager) saveReport(began time.Time, timedOut bool) {
+	if m.reportStore == nil {
+		return
+	}
+	saved := true
+	m.reportOnce.Do(func() { saved = false })
+	if saved {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.flushTimeout)
+	defer cancel()
+	if err := m.reportStore.Save(ctx, m.report(began, timedOut)); err != nil {
+		m.logf("saving shutdown report failed: %v", err)
+	}
+}
+
+// report builds the report of the shutdown that began at began.
+func (m *Manager) report(began time.Time, timedOut bool) ShutdownReport {
+	r := ShutdownReport{
+		Began:          began,
+		Duration:       time.Since(began),
+		TimedOut:       timedOut,
+		RetriedHooks:   m.retriedHooks(),
+		AbandonedHooks: m.abandoned(),
+		Resources:      snapshotResources(),
+	}
+	if reason := m.reason.Load(); reason != nil {
+		r.Reason = reason.Reason
+		if reason.Err != nil {
+			r.Reason += ": " + reason.Err.Error()
+		}
+	}
+	if timedOut {
+		r.Abandoned = int(m.managed.Load())
+		r.AbandonedTasks = m.namedLiveTasks()
+		m.mu.Lock()
+		if m.deadlock != nil {
+			r.Blocked = m.deadlock.Goroutines
+		}
+		m.mu.Unlock()
+	}
+	for _, s := range m.signalHistory() {
+		r.Signals = append(r.Signals, fmt.Sprintf("%v at %s", s.Signal, s.Time.Format(time.RFC3339Nano)))
+	}
+	return r
+}
@@ -0,0 +1,48 @@
+package graceful
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestReportStore 测试关闭报告被保存并在下次启动时记录
+func TestReportStore(t *testing.T) {
+	store := FileReportStore(filepath.Join(t.TempDir(), "shutdown.json"))
+
+	m := New(WithTimeout(time.Millisecond*50), WithReportStore(store))
+	stuck := make(chan struct{})
+	defer close(stuck)
+	m.CtxGo(func(ctx context.Context) { <-stuck }, WithName("stuck"))
+	m.Shutdown()
+
+	r, err := store.Load(context.Background())
+	if err != nil || r == nil {
+		t.Fatalf("应能加载报告: %v", err)
+	}
+	if !r.TimedOut || r.Abandoned != 1 || len(r.AbandonedTasks) != 1 || r.AbandonedTasks[0] != "stuck" {
+		t.Errorf("报告内容不正确: %+v", r)
+	}
+
+	var buf bytes.Buffer
+	next := New(WithReportStore(store), WithLogger(log.New(&buf, "", 0)))
+	next.Go(func() {})
+	if _, err := next.startup(context.Background(), nil); err != nil {
+		t.Fatalf("启动失败: %v", err)
+	}
+	if !strings.Contains(buf.String(), "previous shutdown") || !strings.Contains(buf.String(), "abandoning 1 goroutines (stuck)") {
+		t.Errorf("启动时应记录上次的关闭报告，实际为%q", buf.String())
+	}
+}
+
+// TestReportStoreEmpty 测试没有上次报告时返回nil
+func TestReportStoreEmpty(t *testing.T) {
+	store := FileReportStore(filepath.Join(t.TempDir(), "missing.json"))
+	if r, err := store.Load(context.Background()); r != nil || err != nil {
+		t.Errorf("没有报告时应返回nil，实际为%v, %v", r, err)
+	}
+}
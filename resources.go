@@ -0,0 +1,38 @@
+package graceful
+
+import (
+	"runtime"
+	"time"
+)
+
+// ResourceSnapshot describes the process's resource usage at the end of its
+// life, giving capacity planners a per-instance summary.
+type ResourceSnapshot struct {
+	PeakRSS     uint64        // Peak resident set size in bytes, or 0 where unsupported
+	OpenFiles   int           // Open file descriptors, or -1 where unsupported
+	Goroutines  int           // Goroutines in the process
+	HeapAlloc   uint64        // Bytes of allocated heap objects
+	TotalAlloc  uint64        // Cumulative bytes allocated on the heap
+	NumGC       uint32        // Completed garbage collection cycles
+	GCPauseTime time.Duration // Cumulative stop-the-world pause time
+}
+
+// snapshotResources samples the process's resource usage.
+func snapshotResources() ResourceSnapshot {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	r := ResourceSnapshot{
+		PeakRSS:     peakRSS(),
+		OpenFiles:   -1,
+		Goroutines:  runtime.NumGoroutine(),
+		HeapAlloc:   ms.HeapAlloc,
+		TotalAlloc:  ms.TotalAlloc,
+		NumGC:       ms.NumGC,
+		GCPauseTime: time.Duration(ms.PauseTotalNs),
+	}
+	if fds, err := openDescriptors(); err == nil {
+		r.OpenFiles = len(fds)
+	}
+	return r
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package graceful
+
+// peakRSS reports that the peak resident set size is not available.
+func peakRSS() uint64 {
+	return 0
+}
@@ -0,0 +1,37 @@
+package graceful
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestSnapshotResources 测试资源快照采集进程资源使用情况
+func TestSnapshotResources(t *testing.T) {
+	runtime.GC()
+	r := snapshotResources()
+
+	if r.Goroutines <= 0 || r.NumGC == 0 || r.TotalAlloc == 0 {
+		t.Errorf("快照内容不正确: %+v", r)
+	}
+	if runtime.GOOS == "linux" && (r.PeakRSS == 0 || r.OpenFiles <= 0) {
+		t.Errorf("Linux上应采集峰值RSS和打开的文件数: %+v", r)
+	}
+}
+
+// TestReportResources 测试关闭报告包含资源快照
+func TestReportResources(t *testing.T) {
+	store := FileReportStore(filepath.Join(t.TempDir(), "shutdown.json"))
+	m := New(WithTimeout(time.Second), WithReportStore(store))
+	m.Shutdown()
+
+	r, err := store.Load(context.Background())
+	if err != nil || r == nil {
+		t.Fatalf("应能加载报告: %v", err)
+	}
+	if r.Resources.Goroutines <= 0 || r.Resources.TotalAlloc == 0 {
+		t.Errorf("报告应包含资源快照: %+v", r.Resources)
+	}
+}
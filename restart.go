@@ -0,0 +1,196 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+)
+
+// EventRestarted is emitted after an in-process restart has drained the
+// previous generation of goroutines and before the start functions run again.
+const EventRestarted EventType = "restarted"
+
+// WithRestartSignals returns an Option that makes Run restart the application
+// in-process instead of exiting when one of the given signals is received.
+// A restart performs the graceful shutdown sequence for the components
+// started by the start functions — drain functions, handoffs, phases and the
+// drain strategy, goroutine cancellation, shutdown hooks and flush functions —
+// then calls the start functions passed to Run again with a fresh context.
+// What belongs to the process rather than to those components — whatever was
+// registered before Run, such as drain functions, hooks and write-behind
+// components, as well as telemetry providers, timers, child managers and
+// temporary files — is kept across restarts and only shut down when the
+// process finally exits; write-behind components are flushed at every
+// restart.
+//
+// This is meant for configuration changes that require restarting components
+// but not replacing the binary.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithRestartSignals(syscall.SIGHUP))
+func WithRestartSignals(signals ...os.Signal) Option {
+	return func(m *Manager) {
+		m.restartSignals = signals
+	}
+}
+
+// Restart tears down the components started by the start functions passed to
+// Run with the graceful shutdown sequence, and then calls the start functions
+// again with a fresh context. Readiness is false until they return. It
+// returns the first error returned by a start function, or
+// ErrShutdownInProgress while a rehearsal or another restart is running.
+//
+// The drain functions, shutdown hooks and servers registered once Run called
+// the start functions belong to the running generation: the restart runs and
+// drops them, and the start functions register them again. Those registered
+// before Run belong to the process and only run at the final shutdown.
+// Goroutines that do not exit within the timeout are abandoned; they keep
+// their canceled context and do not delay the new generation.
+//
+// Example:
+//
+//	if configChanged {
+//		if err := manager.Restart(); err != nil {
+//			manager.Shutdown()
+//		}
+//	}
+func (m *Manager) Restart() error {
+	if m.shutDown() {
+		return ErrAlreadyShutdown
+	}
+	if !m.draining.CompareAndSwap(false, true) {
+		return ErrShutdownInProgress
+	}
+	defer m.endDraining()
+	return m.traceRestart(func() error {
+		timeoutCtx, cancel := context.WithTimeout(withRestart(context.Background()), m.timeout)
+		m.stopGeneration(timeoutCtx, nil, nil)
+		cancel()
+		m.reopenStreams()
+		m.runFlushers()
+
+		return m.restartGeneration()
+	})
+}
+
+// generationMarks counts the drain functions, shutdown hooks, handoff
+// functions and write-behind components registered before Run first called
+// the start functions. Those belong to the process and only run at the final
+// shutdown; the ones registered after them belong to the running generation,
+// which a restart or rehearsal tears down before the start functions
+// register them again.
+type generationMarks struct {
+	drainers     int
+	hooks        int
+	handoffs     int
+	writeBehinds int
+}
+
+// beginGeneration assigns the registrations made so far to the process, the
+// first time it is called.
+func (m *Manager) beginGeneration() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.generation == nil {
+		m.generation = &generationMarks{
+			drainers:     len(m.drainers),
+			hooks:        len(m.hooks),
+			handoffs:     len(m.handoffs),
+			writeBehinds: len(m.writeBehinds),
+		}
+	}
+}
+
+// marks returns the process's registration counts, with every count -1
+// before a generation has begun. The caller must hold m.mu.
+func (m *Manager) marks() generationMarks {
+	if m.generation == nil {
+		return generationMarks{drainers: -1, hooks: -1, handoffs: -1, writeBehinds: -1}
+	}
+	return *m.generation
+}
+
+// takeRegistrations removes and returns the registrations in list to run: all
+// of them at the final shutdown, and at a restart those after the first
+// mark, which belong to the process. A negative mark, before a generation has
+// begun, leaves everything to the process. The caller must hold m.mu.
+func takeRegistrations[T any](list *[]T, mark int, restart bool) []T {
+	entries := *list
+	if !restart {
+		*list = nil
+		return entries
+	}
+	if mark < 0 || mark > len(entries) {
+		mark = len(entries)
+	}
+	*list = entries[:mark:mark]
+	return entries[mark:]
+}
+
+// restartKey is the context key marking the contexts of a restart or
+// rehearsal.
+type restartKey struct{}
+
+// withRestart returns a copy of ctx marked as belonging to a restart or
+// rehearsal.
+func withRestart(ctx context.Context) context.Context {
+	return context.WithValue(ctx, restartKey{}, true)
+}
+
+// restarting reports whether ctx belongs to a restart or rehearsal, which
+// only tear down the running generation.
+func restarting(ctx context.Context) bool {
+	restart, _ := ctx.Value(restartKey{}).(bool)
+	return restart
+}
+
+// restartGeneration starts a new generation of goroutines and calls the start
+// functions passed to Run again, returning the first error.
+func (m *Manager) restartGeneration() error {
+	m.beginGeneration()
+	m.mu.Lock()
+	m.ctx, m.cancelFunc = m.withShutdownCause(m.lifetime)
+	m.wg = &sync.WaitGroup{}
+	ctx := m.ctx
+	starts := append([]func(ctx context.Context) error(nil), m.starts...)
+	m.mu.Unlock()
+
+	m.emit(Event{Type: EventRestarted})
+
+	for _, f := range starts {
+		if err := f(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitShutdownSignal blocks until a shutdown signal is received on sigCh,
+// restarting the application in-process for every restart signal received
+// before that. It returns the shutdown signal, the error of a failed restart
+// wrapped with ErrStartupFailed, or the error of a stop request.
+func (m *Manager) waitShutdownSignal(sigCh <-chan os.Signal) (os.Signal, error) {
+	for {
+		sig, err := m.waitSignal(sigCh)
+		if err != nil || sig == nil || !containsSignal(m.restartSignals, sig) {
+			return sig, err
+		}
+		if err := m.Restart(); errors.Is(err, ErrShutdownInProgress) {
+			m.logf("ignoring %v: %v", sig, err)
+		} else if err != nil {
+			return sig, startupFailed(err)
+		}
+	}
+}
+
+// containsSignal reports whether sig is one of signals.
+func containsSignal(signals []os.Signal, sig os.Signal) bool {
+	for _, s := range signals {
+		if s == sig {
+			return true
+		}
+	}
+	return false
+}
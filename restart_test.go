@@ -0,0 +1,163 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestRestart 测试进程内重启会关闭旧goroutine并重新执行启动函数
+func TestRestart(t *testing.T) {
+	var events []EventType
+	m := New(WithTimeout(time.Second), WithEventHandler(func(e Event) {
+		events = append(events, e.Type)
+	}))
+
+	starts := 0
+	stopped := make(chan struct{}, 2)
+	m.starts = append(m.starts, func(ctx context.Context) error {
+		starts++
+		m.CtxGo(func(ctx context.Context) {
+			<-ctx.Done()
+			stopped <- struct{}{}
+		})
+		return nil
+	})
+
+	flushes := 0
+	telemetry := 0
+	m.OnFlush(func(ctx context.Context) error {
+		flushes++
+		return nil
+	})
+	m.ManageTelemetry(providerFunc(func(ctx context.Context) error {
+		telemetry++
+		return nil
+	}))
+
+	oldCtx := m.Context()
+	if err := m.Restart(); err != nil {
+		t.Fatalf("重启不应返回错误，实际为%v", err)
+	}
+
+	if oldCtx.Err() == nil {
+		t.Error("重启后旧的上下文应被取消")
+	}
+	if m.Context().Err() != nil {
+		t.Error("重启后新的上下文不应被取消")
+	}
+	if starts != 1 {
+		t.Errorf("重启后启动函数应执行1次，实际执行了%d次", starts)
+	}
+	if flushes != 1 || telemetry != 0 {
+		t.Errorf("重启时应执行flush函数但保留遥测provider，实际flush%d次、遥测%d次", flushes, telemetry)
+	}
+	if len(events) != 1 || events[0] != EventRestarted {
+		t.Errorf("重启时应发出restarted事件，实际为%v", events)
+	}
+
+	m.Shutdown()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Error("最终关闭时应停止重启后启动的goroutine")
+	}
+	if telemetry != 1 {
+		t.Errorf("最终关闭时应关闭遥测provider，实际关闭了%d次", telemetry)
+	}
+}
+
+// TestRestartStartFailure 测试重启时启动函数失败返回错误
+func TestRestartStartFailure(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	failure := errors.New("启动失败")
+	m.starts = append(m.starts, func(ctx context.Context) error {
+		return failure
+	})
+
+	if err := m.Restart(); err != failure {
+		t.Errorf("重启应返回启动函数的错误，实际为%v", err)
+	}
+	m.Shutdown()
+}
+
+// TestRestartTearsDownComponents 测试重启会像关闭一样运行当前一代的排空函数和关闭钩子并关闭其HTTP服务器，进程级的钩子保留到最终关闭
+func TestRestartTearsDownComponents(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var drains, hooks []int
+	var urls []string
+	var readyDuringDrain bool
+	generation := 0
+	m.starts = append(m.starts, func(ctx context.Context) error {
+		generation++
+		g := generation
+		m.OnDrain(func(ctx context.Context) error {
+			drains = append(drains, g)
+			readyDuringDrain = m.IsReady()
+			return nil
+		})
+		m.OnShutdown(func(ctx context.Context) error {
+			hooks = append(hooks, g)
+			return nil
+		})
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return err
+		}
+		m.HTTPServer(&http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})}, ln)
+		urls = append(urls, "http://"+ln.Addr().String())
+		return nil
+	})
+	processHooks := 0
+	m.OnShutdown(func(ctx context.Context) error {
+		processHooks++
+		return nil
+	})
+	m.beginGeneration()
+	if err := m.starts[0](m.Context()); err != nil {
+		t.Fatal(err)
+	}
+	m.markReady()
+
+	if err := m.Restart(); err != nil {
+		t.Fatalf("重启不应返回错误，实际为%v", err)
+	}
+	if len(drains) != 1 || len(hooks) != 1 {
+		t.Errorf("重启时应运行第一代的排空函数和钩子，实际为%v、%v", drains, hooks)
+	}
+	if processHooks != 0 {
+		t.Errorf("重启不应运行在Run之前注册的钩子，实际运行了%d次", processHooks)
+	}
+	if readyDuringDrain || !m.IsReady() {
+		t.Error("重启排空期间不应就绪，重启后应恢复就绪")
+	}
+	if _, err := http.Get(urls[0]); err == nil {
+		t.Error("重启应关闭旧的HTTP服务器")
+	}
+	resp, err := http.Get(urls[1])
+	if err != nil {
+		t.Fatalf("重启后应启动新的HTTP服务器: %v", err)
+	}
+	resp.Body.Close()
+
+	m.Shutdown()
+	if len(drains) != 2 || drains[1] != 2 || len(hooks) != 2 || hooks[1] != 2 {
+		t.Errorf("关闭时应只运行第二代的排空函数和钩子，实际为%v、%v", drains, hooks)
+	}
+	if processHooks != 1 {
+		t.Errorf("关闭时应运行在Run之前注册的钩子1次，实际运行了%d次", processHooks)
+	}
+	if _, err := http.Get(urls[1]); err == nil {
+		t.Error("关闭应关闭重启后启动的HTTP服务器")
+	}
+}
+
+type providerFunc func(ctx context.Context) error
+
+func (f providerFunc) Shutdown(ctx context.Context) error {
+	return f(ctx)
+}
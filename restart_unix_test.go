@@ -0,0 +1,46 @@
+//go:build unix
+
+package graceful
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestRunRestartSignal 测试Run收到重启信号时重新执行启动函数
+func TestRunRestartSignal(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	m := New(
+		WithTimeout(time.Second),
+		WithSignals(syscall.SIGUSR1),
+		WithRestartSignals(syscall.SIGUSR2),
+	)
+
+	starts := 0
+	m.Run(func(ctx context.Context) error {
+		starts++
+		first := starts == 1
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			if first {
+				_ = syscall.Kill(os.Getpid(), syscall.SIGUSR2)
+			} else {
+				_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+			}
+		}()
+		return nil
+	})
+
+	if starts != 2 {
+		t.Errorf("启动函数应执行2次，实际执行了%d次", starts)
+	}
+	if code != 0 {
+		t.Errorf("正常关闭时退出码应为0，实际为%d", code)
+	}
+}
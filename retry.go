@@ -0,0 +1,138 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Backoff returns the delay before the given retry. attempt is 1 for the
+// delay after the first failed attempt.
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff returns a Backoff that always waits d.
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff returns a Backoff that starts at initial and doubles
+// after every attempt, up to max.
+func ExponentialBackoff(initial, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := initial
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d
+	}
+}
+
+// RetryError is returned by RetryUntilDeadline when fn never succeeded. It
+// wraps every error returned by fn, so errors.Is and errors.As match any of
+// them.
+type RetryError struct {
+	Attempts int           // Number of times fn was called
+	Elapsed  time.Duration // Time spent retrying
+	Errs     []error       // Error returned by each attempt, in order
+	Reason   error         // Why retrying stopped: the context error, ErrBudgetExhausted, or nil for a permanent error
+}
+
+// ErrBudgetExhausted is the RetryError reason when the next attempt would
+// start after the context deadline.
+var ErrBudgetExhausted = errors.New("graceful: retry budget exhausted")
+
+func (e *RetryError) Error() string {
+	last := e.Errs[len(e.Errs)-1]
+	if e.Reason == nil {
+		return fmt.Sprintf("graceful: gave up after %d attempts in %v: %v", e.Attempts, e.Elapsed, last)
+	}
+	return fmt.Sprintf("graceful: gave up after %d attempts in %v (%v): %v", e.Attempts, e.Elapsed, e.Reason, last)
+}
+
+// Unwrap returns the errors of all attempts followed by the reason, if any.
+func (e *RetryError) Unwrap() []error {
+	if e.Reason == nil {
+		return e.Errs
+	}
+	return append(append([]error(nil), e.Errs...), e.Reason)
+}
+
+// permanentError marks an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that RetryUntilDeadline stops retrying immediately,
+// for failures that another attempt cannot fix, such as a 404 on
+// deregistration.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// RetryUntilDeadline calls fn until it succeeds, retrying with the delays
+// given by backoff for as long as the context allows. It is meant for
+// shutdown-time cleanup such as deregistration or final commits, where the
+// budget is whatever remains of the shutdown timeout: an attempt is only
+// started if its delay ends before the context deadline or, for a task
+// context once shutdown has begun, before the shutdown timeout expires (see
+// RemainingBudget).
+//
+// If fn never succeeds, the returned *RetryError describes every attempt.
+//
+// Example:
+//
+//	manager.OnShutdown(func(ctx context.Context) error {
+//		return graceful.RetryUntilDeadline(ctx, graceful.ExponentialBackoff(100*time.Millisecond, time.Second),
+//			func(ctx context.Context) error {
+//				return registry.Deregister(ctx, instanceID)
+//			})
+//	})
+func RetryUntilDeadline(ctx context.Context, backoff Backoff, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	result := &RetryError{}
+
+	for {
+		err := fn(ctx)
+		result.Attempts++
+		if err == nil {
+			return nil
+		}
+
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			result.Errs = append(result.Errs, permanent.err)
+			result.Elapsed = time.Since(start)
+			return result
+		}
+		result.Errs = append(result.Errs, err)
+
+		delay := backoff(result.Attempts)
+		if left, ok := RemainingBudget(ctx); ok && delay > left {
+			result.Reason = ErrBudgetExhausted
+			result.Elapsed = time.Since(start)
+			return result
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Reason = ctx.Err()
+			result.Elapsed = time.Since(start)
+			return result
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestRetryUntilDeadline 测试重试直到成功
+func TestRetryUntilDeadline(t *testing.T) {
+	attempts := 0
+	err := RetryUntilDeadline(context.Background(), ConstantBackoff(time.Millisecond), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("暂时失败")
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("第3次应成功，实际尝试%d次，错误为%v", attempts, err)
+	}
+}
+
+// TestRetryBudgetExhausted 测试剩余预算不足时停止重试
+func TestRetryBudgetExhausted(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	failure := errors.New("注销失败")
+	start := time.Now()
+	err := RetryUntilDeadline(ctx, ConstantBackoff(time.Millisecond*20), func(ctx context.Context) error {
+		return failure
+	})
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("应返回*RetryError，实际为%v", err)
+	}
+	if retryErr.Attempts < 2 || retryErr.Attempts > 3 {
+		t.Errorf("50ms预算内应尝试2到3次，实际为%d次", retryErr.Attempts)
+	}
+	if !errors.Is(err, failure) || !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("错误应包含每次尝试的错误和停止原因，实际为%v", err)
+	}
+	if time.Since(start) > time.Millisecond*50 {
+		t.Error("不应在截止时间之后才返回")
+	}
+}
+
+// TestRetryPermanent 测试永久错误立即停止
+func TestRetryPermanent(t *testing.T) {
+	notFound := errors.New("实例不存在")
+	attempts := 0
+	err := RetryUntilDeadline(context.Background(), ConstantBackoff(time.Millisecond), func(ctx context.Context) error {
+		attempts++
+		return Permanent(notFound)
+	})
+	if attempts != 1 || !errors.Is(err, notFound) {
+		t.Errorf("永久错误应只尝试1次，实际尝试%d次，错误为%v", attempts, err)
+	}
+}
+
+// TestExponentialBackoff 测试指数退避及上限
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(time.Millisecond*100, time.Millisecond*350)
+	expected := []time.Duration{100, 200, 350, 350}
+	for i, want := range expected {
+		if got := b(i + 1); got != want*time.Millisecond {
+			t.Errorf("第%d次重试延迟应为%v，实际为%v", i+1, want*time.Millisecond, got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// WithHookTimeout returns an Option that runs each shutdown hook in its own
+// goroutine with a context bounded by timeout, and stops waiting for a hook
+// that has not returned when its timeout expires. The abandoned hook keeps
+// running in the background while the remaining hooks run, and is recorded
+// in the shutdown report and logged. Without this option hooks are called
+// inline, so one hook that blocks can consume the whole remaining budget.
+//
+// A hook's wait is also bounded by the shutdown timeout, so hooks that run
+// after it has expired are abandoned at once instead of each extending the
+// shutdown by up to timeout. Hooks of a dependency class with a budget are
+// bounded by that budget instead.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithHookTimeout(2 * time.Second))
+func WithHookTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.hookTimeout = timeout
+	}
+}
+
+// runHook calls a shutdown hook, in a goroutine bounded by the hook timeout
+// if one is set.
+func (m *Manager) runHook(ctx context.Context, h hook) error {
+	if m.hookTimeout <= 0 {
+		return m.callHook(ctx, h)
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, m.hookTimeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- m.callHook(ctx, h) }()
+
+	// ctx ends at the hook timeout or at the shutdown timeout, if earlier
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	m.abandonHook(funcName(h.fn), time.Since(start).Round(time.Millisecond))
+	return ctx.Err()
+}
+
+// abandonHook logs and records a hook that was still running, or never got
+// to run, when its time ran out.
+func (m *Manager) abandonHook(name string, after time.Duration) {
+	m.logf("shutdown hook %s abandoned after %v", name, after)
+	m.mu.Lock()
+	m.abandonedHooks = append(m.abandonedHooks, name)
+	m.mu.Unlock()
+}
+
+// abandoned returns the names of the hooks abandoned so far.
+func (m *Manager) abandoned() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.abandonedHooks...)
+}
@@ -0,0 +1,81 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestWithHookTimeout 测试阻塞的关闭钩子被放弃后继续运行其余钩子
+func TestWithHookTimeout(t *testing.T) {
+	m := New(WithTimeout(time.Second*5), WithHookTimeout(time.Millisecond*50))
+
+	ran := false
+	m.OnShutdown(func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	release := make(chan struct{})
+	defer close(release)
+	m.OnShutdown(func(ctx context.Context) error {
+		// 忽略上下文的阻塞钩子
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	m.Shutdown()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("阻塞的钩子应在超时后被放弃，耗时%v", elapsed)
+	}
+	if !ran {
+		t.Error("放弃阻塞的钩子后应继续运行其余钩子")
+	}
+	if abandoned := m.abandoned(); len(abandoned) != 1 {
+		t.Errorf("应记录1个被放弃的钩子，实际为%v", abandoned)
+	}
+}
+
+// TestWithHookTimeoutContext 测试每个钩子的上下文受钩子超时限制
+func TestWithHookTimeoutContext(t *testing.T) {
+	m := New(WithTimeout(time.Second*5), WithHookTimeout(time.Millisecond*50))
+
+	var left time.Duration
+	m.OnShutdown(func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		left = time.Until(deadline)
+		return nil
+	})
+	m.Shutdown()
+
+	if left > time.Millisecond*50 {
+		t.Errorf("钩子上下文应受钩子超时限制，剩余%v", left)
+	}
+	if abandoned := m.abandoned(); len(abandoned) != 0 {
+		t.Errorf("按时返回的钩子不应被记录，实际为%v", abandoned)
+	}
+}
+
+// TestWithHookTimeoutAfterDeadline 测试关闭超时后运行的钩子不会再各自等待钩子超时
+func TestWithHookTimeoutAfterDeadline(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond*50), WithHookTimeout(time.Second))
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+	m.Go(func() { <-stuck })
+	for i := 0; i < 3; i++ {
+		m.OnShutdown(func(ctx context.Context) error {
+			<-stuck
+			return nil
+		})
+	}
+
+	start := time.Now()
+	m.Shutdown()
+	if elapsed := time.Since(start); elapsed > time.Millisecond*500 {
+		t.Errorf("关闭超时后钩子应立即被放弃，实际耗时%v", elapsed)
+	}
+	if n := len(m.abandoned()); n != 3 {
+		t.Errorf("应记录3个被放弃的钩子，实际为%d", n)
+	}
+}
@@ -0,0 +1,35 @@
+package graceful
+
+import (
+	"os"
+	"time"
+)
+
+// maxSignalRecords bounds the signal history kept for the audit.
+const maxSignalRecords = 256
+
+// SignalRecord describes one signal received by the process.
+type SignalRecord struct {
+	Signal    os.Signal // Signal received
+	Time      time.Time // When it was received
+	Delivered bool      // Whether it was acted on, rather than coalesced or dropped
+}
+
+// recordSignal appends a signal to the history, keeping the most recent
+// maxSignalRecords.
+func (m *Manager) recordSignal(sig os.Signal, at time.Time, delivered bool) {
+	m.signalLogMu.Lock()
+	defer m.signalLogMu.Unlock()
+	if len(m.signalLog) == maxSignalRecords {
+		copy(m.signalLog, m.signalLog[1:])
+		m.signalLog = m.signalLog[:maxSignalRecords-1]
+	}
+	m.signalLog = append(m.signalLog, SignalRecord{Signal: sig, Time: at, Delivered: delivered})
+}
+
+// signalHistory returns a copy of the signal history, oldest first.
+func (m *Manager) signalHistory() []SignalRecord {
+	m.signalLogMu.Lock()
+	defer m.signalLogMu.Unlock()
+	return append([]SignalRecord(nil), m.signalLog...)
+}
@@ -0,0 +1,24 @@
+package graceful
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestSignalHistoryBound 测试信号记录只保留最近的条目
+func TestSignalHistoryBound(t *testing.T) {
+	m := New()
+	base := time.Now()
+	for i := 0; i < maxSignalRecords+10; i++ {
+		m.recordSignal(os.Interrupt, base.Add(time.Duration(i)), true)
+	}
+
+	history := m.signalHistory()
+	if len(history) != maxSignalRecords {
+		t.Fatalf("应保留%d条记录，实际为%d", maxSignalRecords, len(history))
+	}
+	if !history[0].Time.Equal(base.Add(10)) {
+		t.Error("应丢弃最旧的记录")
+	}
+}
@@ -0,0 +1,191 @@
+package graceful
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"runtime"
+	"time"
+)
+
+// SignalStats counts the OS signals seen by Wait and Run through the default
+// trigger.
+type SignalStats struct {
+	Received  int // Signals received from the OS
+	Coalesced int // Duplicates dropped within the coalescing window
+	Dropped   int // Signals and trigger requests dropped because the signal buffer was full
+}
+
+// WithSignalBuffer returns an Option that sets the depth of the internal
+// signal channel. The default of 1 is enough for a single shutdown signal;
+// raise it if signals must not be lost while the manager is busy, for
+// example during an in-process restart. Values below 1 are ignored.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithSignalBuffer(4))
+func WithSignalBuffer(n int) Option {
+	return func(m *Manager) {
+		if n >= 1 {
+			m.signalBuffer = n
+		}
+	}
+}
+
+// WithSignalCoalescing returns an Option that drops a signal if the same
+// signal was delivered less than window ago. Some supervisors send several
+// SIGTERMs in quick succession; coalescing them keeps a burst from being
+// mistaken for a second, deliberate signal. Coalesced signals are still
+// counted in SignalStats.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithSignalCoalescing(500 * time.Millisecond))
+func WithSignalCoalescing(window time.Duration) Option {
+	return func(m *Manager) {
+		m.coalesceWindow = window
+	}
+}
+
+// WithIgnoredSignals returns an Option that makes the process ignore the
+// given signals when the manager is created, such as SIGPIPE from writes to
+// closed sockets, so that the process signal policy is configured in one
+// place instead of with signal.Ignore calls scattered across the code base.
+// Signals the manager monitors for shutdown or restart are handled again
+// once Wait or Run starts. The others stay ignored after shutdown, so that
+// final writes to a closed pipe cannot kill the process.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithIgnoredSignals(syscall.SIGPIPE))
+func WithIgnoredSignals(signals ...os.Signal) Option {
+	return func(m *Manager) {
+		m.ignoredSignals = append(m.ignoredSignals, signals...)
+	}
+}
+
+// WithResetSignals returns an Option that restores the default behavior of
+// the given signals when the manager is created, undoing signal.Notify calls
+// made by libraries.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithResetSignals(syscall.SIGQUIT))
+func WithResetSignals(signals ...os.Signal) Option {
+	return func(m *Manager) {
+		m.resetSignals = append(m.resetSignals, signals...)
+	}
+}
+
+// applySignalPolicy ignores and resets the configured signals.
+func (m *Manager) applySignalPolicy() {
+	if len(m.ignoredSignals) > 0 {
+		signal.Ignore(m.ignoredSignals...)
+	}
+	if len(m.resetSignals) > 0 {
+		signal.Reset(m.resetSignals...)
+	}
+}
+
+// SignalStats returns the number of signals received, coalesced and dropped
+// so far.
+func (m *Manager) SignalStats() SignalStats {
+	return SignalStats{
+		Received:  int(m.signalsReceived.Load()),
+		Coalesced: int(m.signalsCoalesced.Load()),
+		Dropped:   int(m.signalsDropped.Load()),
+	}
+}
+
+// notifySignals runs the configured triggers, by default the manager's signal
+// trigger for the given signals, and returns a channel that receives what
+// they fire. A request that arrives while the channel is full is dropped.
+// The returned function stops the triggers.
+func (m *Manager) notifySignals(signals []os.Signal) (<-chan os.Signal, func()) {
+	out := make(chan os.Signal, m.signalBuffer)
+	fire := func(sig os.Signal) {
+		now := time.Now()
+		select {
+		case out <- sig:
+			m.recordSignal(sig, now, true)
+		default:
+			m.signalsDropped.Add(1)
+			m.recordSignal(sig, now, false)
+		}
+	}
+
+	triggers := m.triggers
+	if triggers == nil {
+		triggers = []Trigger{DefaultTrigger()}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var signalTriggers []*signalTrigger
+	for _, t := range triggers {
+		if _, ok := t.(defaultTrigger); ok {
+			// Subscribe now, so that no signal is missed before Watch runs
+			st := m.newSignalTrigger(signals)
+			signalTriggers = append(signalTriggers, st)
+			t = st
+		}
+		go t.Watch(ctx, fire)
+	}
+	return out, func() {
+		for _, st := range signalTriggers {
+			signal.Stop(st.raw)
+		}
+		cancel()
+	}
+}
+
+// signalTrigger is the default trigger bound to a manager: it relays OS
+// signals, skipping those taken over by HandleSignal and those this platform
+// cannot deliver, counts them in SignalStats and coalesces duplicates.
+type signalTrigger struct {
+	m   *Manager
+	raw chan os.Signal
+}
+
+// newSignalTrigger subscribes to the given signals.
+func (m *Manager) newSignalTrigger(signals []os.Signal) *signalTrigger {
+	t := &signalTrigger{m: m, raw: make(chan os.Signal, m.signalBuffer)}
+	if supported := m.supportedSignals(m.unhandledSignals(signals)); len(supported) > 0 {
+		signal.Notify(t.raw, supported...)
+	}
+	return t
+}
+
+// Watch implements Trigger.
+func (t *signalTrigger) Watch(ctx context.Context, fire func(sig os.Signal)) {
+	var last os.Signal
+	var lastAt time.Time
+	for {
+		select {
+		case sig := <-t.raw:
+			t.m.signalsReceived.Add(1)
+			now := time.Now()
+			if t.m.coalesceWindow > 0 && sig == last && now.Sub(lastAt) < t.m.coalesceWindow {
+				t.m.signalsCoalesced.Add(1)
+				t.m.recordSignal(sig, now, false)
+				continue
+			}
+			last, lastAt = sig, now
+			fire(sig)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// supportedSignals returns the signals that can be delivered on this
+// platform, logging the others.
+func (m *Manager) supportedSignals(signals []os.Signal) []os.Signal {
+	supported := make([]os.Signal, 0, len(signals))
+	for _, sig := range signals {
+		if signalSupported(sig) {
+			supported = append(supported, sig)
+		} else {
+			m.logf("signal %v is not supported on %s, ignoring it", sig, runtime.GOOS)
+		}
+	}
+	return supported
+}
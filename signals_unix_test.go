@@ -0,0 +1,119 @@
+//go:build unix
+
+package graceful
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestSignalCoalescing 测试窗口内重复的信号被合并
+func TestSignalCoalescing(t *testing.T) {
+	m := New(WithSignalBuffer(4), WithSignalCoalescing(time.Second))
+	sigCh, stop := m.notifySignals([]os.Signal{syscall.SIGUSR1})
+	defer stop()
+
+	for i := 0; i < 3; i++ {
+		_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	select {
+	case <-sigCh:
+	case <-time.After(time.Second):
+		t.Fatal("应收到第一个信号")
+	}
+	select {
+	case <-sigCh:
+		t.Error("窗口内重复的信号应被合并")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	stats := m.SignalStats()
+	if stats.Received != 3 {
+		t.Errorf("应收到3个信号，实际为%d", stats.Received)
+	}
+	if stats.Coalesced != 2 {
+		t.Errorf("应合并2个信号，实际为%d", stats.Coalesced)
+	}
+
+	history := m.Stats().Signals
+	if len(history) != 3 {
+		t.Fatalf("应记录3个信号，实际为%d", len(history))
+	}
+	if !history[0].Delivered || history[1].Delivered || history[2].Delivered {
+		t.Error("只有第一个信号应被标记为已送达")
+	}
+	if history[0].Signal != syscall.SIGUSR1 || history[0].Time.IsZero() {
+		t.Errorf("信号记录不正确: %+v", history[0])
+	}
+}
+
+// TestSupportedSignals 测试Unix平台支持所有配置的信号
+func TestSupportedSignals(t *testing.T) {
+	m := New()
+	signals := []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+	if got := m.supportedSignals(signals); len(got) != len(signals) {
+		t.Errorf("应支持%d个信号，实际为%d", len(signals), len(got))
+	}
+}
+
+// TestSignalPolicy 测试忽略和重置信号
+func TestSignalPolicy(t *testing.T) {
+	defer signal.Reset(syscall.SIGPIPE)
+
+	New(WithIgnoredSignals(syscall.SIGPIPE))
+	if !signal.Ignored(syscall.SIGPIPE) {
+		t.Fatal("信号应被忽略")
+	}
+
+	// SIGWINCH的默认行为是忽略，重置后向自身发送是安全的
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGWINCH)
+	New(WithResetSignals(syscall.SIGWINCH))
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGWINCH); err != nil {
+		t.Fatalf("发送信号失败: %v", err)
+	}
+	select {
+	case <-sigCh:
+		t.Error("重置后不应再收到信号")
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+// TestTriggersReplaceSignals 测试WithTriggers替换默认的信号触发器，加入DefaultTrigger后仍处理信号
+func TestTriggersReplaceSignals(t *testing.T) {
+	// 自行接收SIGUSR1，避免未被管理器处理的信号终止测试进程
+	guard := make(chan os.Signal, 4)
+	signal.Notify(guard, syscall.SIGUSR1)
+	defer signal.Stop(guard)
+
+	m := New(WithSignals(syscall.SIGUSR1), WithTriggers(NewHTTPTrigger()))
+	sigCh, stop := m.notifySignals(m.signals)
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case sig := <-sigCh:
+		t.Errorf("替换默认触发器后不应处理信号，实际收到%v", sig)
+	case <-time.After(time.Millisecond * 100):
+	}
+	stop()
+
+	m = New(WithSignals(syscall.SIGUSR1), WithTriggers(DefaultTrigger(), NewHTTPTrigger()))
+	sigCh, stop = m.notifySignals(m.signals)
+	defer stop()
+	_ = syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case sig := <-sigCh:
+		if sig != syscall.SIGUSR1 {
+			t.Errorf("应收到SIGUSR1，实际为%v", sig)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("加入DefaultTrigger后应处理信号")
+	}
+	if n := m.SignalStats().Received; n != 1 {
+		t.Errorf("默认触发器应统计收到的信号，实际为%d", n)
+	}
+}
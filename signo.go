@@ -0,0 +1,19 @@
+//go:build !plan9
+
+package graceful
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalNumber returns the numeric value of sig, if it has one.
+func signalNumber(sig os.Signal) (int, bool) {
+	n, ok := sig.(syscall.Signal)
+	return int(n), ok
+}
+
+// defaultSignals returns the signals monitored when WithSignals is not used.
+func defaultSignals() []os.Signal {
+	return []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+}
@@ -0,0 +1,19 @@
+package graceful
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalNumber reports false: Plan 9 notes are strings without a number.
+func signalNumber(sig os.Signal) (int, bool) {
+	return 0, false
+}
+
+// defaultSignals returns the notes monitored when WithSignals is not used.
+// Plan 9 has no SIGTERM: syscall.SIGTERM is the same "interrupt" note as
+// syscall.SIGINT, and processes are asked to terminate with "hangup", for
+// example when their window is deleted.
+func defaultSignals() []os.Signal {
+	return []os.Signal{syscall.Note("interrupt"), syscall.Note("hangup")}
+}
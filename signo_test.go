@@ -0,0 +1,14 @@
+package graceful
+
+import "testing"
+
+// TestDefaultSignals 测试默认监听的信号互不重复
+func TestDefaultSignals(t *testing.T) {
+	signals := defaultSignals()
+	if len(signals) != 2 {
+		t.Fatalf("应默认监听2个信号，实际为%d", len(signals))
+	}
+	if signals[0] == signals[1] {
+		t.Errorf("默认信号不应重复，实际为%v", signals)
+	}
+}
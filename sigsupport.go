@@ -0,0 +1,10 @@
+//go:build !windows && !js && !wasip1
+
+package graceful
+
+import "os"
+
+// signalSupported reports whether sig can be delivered on this platform.
+func signalSupported(sig os.Signal) bool {
+	return true
+}
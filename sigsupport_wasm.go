@@ -0,0 +1,11 @@
+//go:build js || wasip1
+
+package graceful
+
+import "os"
+
+// signalSupported reports whether sig can be delivered on this platform.
+// WebAssembly hosts do not deliver signals.
+func signalSupported(sig os.Signal) bool {
+	return false
+}
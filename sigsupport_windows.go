@@ -0,0 +1,14 @@
+//go:build windows
+
+package graceful
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalSupported reports whether sig can be delivered on this platform.
+// Windows console events are only translated into SIGINT and SIGTERM.
+func signalSupported(sig os.Signal) bool {
+	return sig == os.Interrupt || sig == syscall.SIGTERM
+}
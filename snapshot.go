@@ -0,0 +1,95 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// Snapshot is the configuration of a manager, taken with Manager.Snapshot,
+// from which fresh managers can be created. It lets table-driven tests run
+// many shutdown scenarios against identical wiring without rebuilding it.
+type Snapshot struct {
+	options      []Option
+	hooks        []hook
+	drainers     []func(ctx context.Context) error
+	flushers     []func(ctx context.Context) error
+	telemetry    []TelemetryProvider
+	starts       []func(ctx context.Context) error
+	startHooks   []func(ctx context.Context) error
+	handoffs     []func(ctx context.Context, h Handoff) error
+	writeBehinds []*writeBehind
+	deregisters  []func(ctx context.Context) error
+	finals       []func(code int)
+	outputs      []OutputFlusher
+	tempPaths    []string
+	drainTimes   map[string]time.Duration
+}
+
+// Snapshot captures the options the manager was created with and the
+// functions registered with it so far: shutdown, drain, flush, start,
+// handoff and exit hooks, DNS deregistration delays, flushers, telemetry
+// providers, outputs registered with RegisterOutput, tracked temporary paths
+// and declared drain times. Goroutines, tasks and child managers are not part
+// of a snapshot, and functions registered by components, such as listeners
+// and dialers, keep referring to those components in every copy.
+//
+// Example:
+//
+//	snap := base.Snapshot()
+//	for _, tt := range tests {
+//		m := snap.New(graceful.WithTimeout(tt.timeout))
+//		tt.run(m)
+//	}
+func (m *Manager) Snapshot() *Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := &Snapshot{
+		options:      append([]Option(nil), m.options...),
+		hooks:        append([]hook(nil), m.hooks...),
+		drainers:     append([]func(ctx context.Context) error(nil), m.drainers...),
+		flushers:     append([]func(ctx context.Context) error(nil), m.flushers...),
+		telemetry:    append([]TelemetryProvider(nil), m.telemetry...),
+		starts:       append([]func(ctx context.Context) error(nil), m.starts...),
+		startHooks:   append([]func(ctx context.Context) error(nil), m.startHooks...),
+		handoffs:     append([]func(ctx context.Context, h Handoff) error(nil), m.handoffs...),
+		writeBehinds: append([]*writeBehind(nil), m.writeBehinds...),
+		deregisters:  append([]func(ctx context.Context) error(nil), m.deregistrations...),
+		finals:       append(([]func(code int))(nil), m.finals...),
+		outputs:      append([]OutputFlusher(nil), m.outputs...),
+		tempPaths:    append([]string(nil), m.tempPaths...),
+		drainTimes:   make(map[string]time.Duration, len(m.drainTimes)),
+	}
+	for name, d := range m.drainTimes {
+		s.drainTimes[name] = d
+	}
+	return s
+}
+
+// New creates a manager with the snapshot's options followed by the given
+// ones, and the snapshot's registrations.
+func (s *Snapshot) New(options ...Option) *Manager {
+	m := New(append(append([]Option(nil), s.options...), options...)...)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, s.hooks...)
+	m.drainers = append(m.drainers, s.drainers...)
+	m.flushers = append(m.flushers, s.flushers...)
+	m.telemetry = append(m.telemetry, s.telemetry...)
+	m.starts = append(m.starts, s.starts...)
+	m.startHooks = append(m.startHooks, s.startHooks...)
+	m.handoffs = append(m.handoffs, s.handoffs...)
+	m.writeBehinds = append(m.writeBehinds, s.writeBehinds...)
+	m.deregistrations = append(m.deregistrations, s.deregisters...)
+	m.finals = append(m.finals, s.finals...)
+	m.outputs = append(m.outputs, s.outputs...)
+	m.tempPaths = append(m.tempPaths, s.tempPaths...)
+	if len(s.drainTimes) > 0 && m.drainTimes == nil {
+		m.drainTimes = make(map[string]time.Duration, len(s.drainTimes))
+	}
+	for name, d := range s.drainTimes {
+		m.drainTimes[name] = d
+	}
+	return m
+}
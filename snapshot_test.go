@@ -0,0 +1,83 @@
+package graceful
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestSnapshot 测试从快照创建的管理器拥有相同的配置
+func TestSnapshot(t *testing.T) {
+	base := New(WithTimeout(time.Millisecond * 50))
+	var hooks int
+	base.OnShutdown(func(ctx context.Context) error {
+		hooks++
+		return nil
+	})
+	base.NeedsDrainTime("db", time.Millisecond*10)
+	snap := base.Snapshot()
+
+	tests := []struct {
+		name    string
+		options []Option
+		block   bool
+		want    error
+	}{
+		{"正常关闭", nil, false, nil},
+		{"超时关闭", nil, true, ErrTimeout},
+		{"覆盖选项", []Option{WithTimeout(time.Second)}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := snap.New(tt.options...)
+			if m.CheckDrainBudget() != nil {
+				t.Error("快照应保留声明的drain时间")
+			}
+			stuck := make(chan struct{})
+			defer close(stuck)
+			if tt.block {
+				m.Go(func() { <-stuck })
+			}
+			before := hooks
+			if err := m.Shutdown(); !errors.Is(err, tt.want) {
+				t.Errorf("应返回%v，实际为%v", tt.want, err)
+			}
+			if hooks != before+1 {
+				t.Error("每个副本都应运行快照中的关闭钩子")
+			}
+		})
+	}
+
+	if m := snap.New(); m.timeout != time.Millisecond*50 {
+		t.Errorf("副本应使用快照的选项，超时为%v", m.timeout)
+	}
+}
+
+// TestSnapshotOutputsAndTempPaths 测试快照副本会刷新注册的输出并删除跟踪的临时文件
+func TestSnapshotOutputsAndTempPaths(t *testing.T) {
+	base := New(WithTimeout(time.Second))
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	base.RegisterOutput(w)
+	path := filepath.Join(t.TempDir(), "scratch")
+	base.TrackTempFile(path)
+	snap := base.Snapshot()
+
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.WriteString("日志")
+	snap.New().Shutdown()
+
+	if out.String() != "日志" {
+		t.Errorf("副本关闭时应刷新注册的输出，实际为%q", out.String())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("副本关闭时应删除跟踪的临时文件，实际为%v", err)
+	}
+}
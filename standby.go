@@ -0,0 +1,298 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrFailover is the cancellation cause of an active task demoted by
+// StandbyPair.Failover.
+var ErrFailover = errors.New("graceful: active task failed over")
+
+// standbyRestartDelay is how long a pair waits before starting a task to fill
+// an empty slot, so a function that fails immediately does not spin.
+var standbyRestartDelay = 100 * time.Millisecond
+
+// standbyKey is the context key under which a pair member stores its role.
+type standbyKey struct{}
+
+// standbyMember is one task of a standby pair.
+type standbyMember struct {
+	task     *Task
+	promoted chan struct{} // Closed once the member is the active task
+	err      error         // Returned by the task function, set before task.Done is closed
+	canceled bool          // Task context was canceled when the function returned
+	returned chan struct{} // Closed once err and canceled are set
+}
+
+// StandbyPair is a handle to an active/standby task pair started with
+// GoStandby.
+type StandbyPair struct {
+	m    *Manager
+	name string
+	f    func(ctx context.Context) error
+	opts []TaskOption
+	done chan struct{} // Closed when the supervisor has stopped
+
+	mu         sync.Mutex
+	active     *standbyMember // Nil while waiting to start a replacement
+	standby    *standbyMember // Nil while waiting to start a replacement
+	promotions int
+}
+
+// GoStandby starts two instances of f as an active/standby pair for fast
+// in-process failover of critical loops. Both run as managed tasks; the
+// standby can connect and warm up, then blocks in AwaitPromotion (or checks
+// IsActive to do reduced work) until it is promoted.
+//
+// A supervisor promotes the standby as soon as the active task returns an
+// error or has its context canceled, for example through Failover,
+// Task.Cancel or a drain strategy stopping it at shutdown, without waiting
+// for the canceled task to finish its current work. Outside shutdown it then
+// starts a fresh standby, and a standby that exits before promotion is
+// replaced the same way. When the active task returns nil on its own, the
+// pair is finished and the standby is canceled. When the manager's context
+// is canceled both tasks stop like any managed goroutine.
+//
+// The tasks are named name and name+"/standby"; a promoted standby keeps its
+// name, so use StandbyPair.Active rather than Manager.Task to find the
+// current active task.
+//
+// Example:
+//
+//	manager.GoStandby("leader-loop", func(ctx context.Context) error {
+//		conn, err := connect(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		if err := graceful.AwaitPromotion(ctx); err != nil {
+//			return err
+//		}
+//		return lead(ctx, conn)
+//	})
+func (m *Manager) GoStandby(name string, f func(ctx context.Context) error, opts ...TaskOption) *StandbyPair {
+	p := &StandbyPair{m: m, name: name, f: f, opts: opts, done: make(chan struct{})}
+	p.active = p.start(true)
+	p.standby = p.start(false)
+	m.Go(p.supervise)
+	return p
+}
+
+// AwaitPromotion blocks until the pair member owning ctx is the active task,
+// and returns ctx.Err() if ctx is canceled first. It returns nil immediately
+// for active tasks and for contexts that do not belong to a standby pair.
+func AwaitPromotion(ctx context.Context) error {
+	member, ok := ctx.Value(standbyKey{}).(*standbyMember)
+	if !ok {
+		return nil
+	}
+	select {
+	case <-member.promoted:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsActive reports whether the pair member owning ctx is the active task. It
+// returns true for contexts that do not belong to a standby pair.
+func IsActive(ctx context.Context) bool {
+	member, ok := ctx.Value(standbyKey{}).(*standbyMember)
+	if !ok {
+		return true
+	}
+	select {
+	case <-member.promoted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Active returns the current active task, or nil while a replacement is
+// being started.
+func (p *StandbyPair) Active() *Task {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return memberTask(p.active)
+}
+
+// Standby returns the current standby task, or nil while a replacement is
+// being started.
+func (p *StandbyPair) Standby() *Task {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return memberTask(p.standby)
+}
+
+// Promotions returns how many times a standby has been promoted.
+func (p *StandbyPair) Promotions() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.promotions
+}
+
+// Failover cancels the active task with ErrFailover, which makes the
+// supervisor promote the standby. It is meant for demoting an active task
+// that is healthy but should hand over, for example before draining the
+// resource it works on.
+func (p *StandbyPair) Failover() {
+	if t := p.Active(); t != nil {
+		t.Cancel(ErrFailover)
+	}
+}
+
+// Done returns a channel that is closed once the pair has finished, either
+// because the active task returned nil or because the manager shut down.
+func (p *StandbyPair) Done() <-chan struct{} {
+	return p.done
+}
+
+// memberTask returns the task of member, or nil.
+func memberTask(member *standbyMember) *Task {
+	if member == nil {
+		return nil
+	}
+	return member.task
+}
+
+// memberDone returns the done channel of member's task, or nil so that a
+// select on it blocks.
+func memberDone(member *standbyMember) <-chan struct{} {
+	if member == nil {
+		return nil
+	}
+	return member.task.Done()
+}
+
+// memberCanceled returns a channel that is closed once the context of
+// member's task is canceled, or nil so that a select on it blocks.
+func memberCanceled(member *standbyMember) <-chan struct{} {
+	if member == nil {
+		return nil
+	}
+	return member.task.Context().Done()
+}
+
+// start starts a pair member, promoted from the start if active is true.
+func (p *StandbyPair) start(active bool) *standbyMember {
+	member := &standbyMember{promoted: make(chan struct{}), returned: make(chan struct{})}
+	name := p.name
+	if active {
+		close(member.promoted)
+	} else {
+		name += "/standby"
+	}
+	opts := append(p.opts[:len(p.opts):len(p.opts)], WithName(name))
+	member.task = p.m.CtxGo(func(ctx context.Context) {
+		member.err = p.f(context.WithValue(ctx, standbyKey{}, member))
+		member.canceled = ctx.Err() != nil
+		close(member.returned)
+	}, opts...)
+	return member
+}
+
+// supervise watches the pair, promoting the standby when the active task
+// fails or is canceled and starting replacements for empty slots, until the
+// pair finishes or the manager shuts down.
+func (p *StandbyPair) supervise() {
+	defer close(p.done)
+	ctx := p.m.Context()
+	var retired []*standbyMember // Demoted active tasks that may still be running
+	defer func() { p.wait(retired...) }()
+
+	for {
+		p.mu.Lock()
+		active, standby := p.active, p.standby
+		p.mu.Unlock()
+
+		// No replacements are started once shutdown has begun
+		stopping := ctx.Err() != nil || p.m.ShuttingDown()
+		if stopping && active == nil && standby == nil {
+			return
+		}
+		var timer *time.Timer
+		var restart <-chan time.Time
+		if !stopping && (active == nil || standby == nil) {
+			timer = time.NewTimer(standbyRestartDelay)
+			restart = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			p.wait(active, standby)
+			return
+		case <-memberCanceled(active):
+			select {
+			case <-active.returned:
+				if active.err == nil && !active.canceled {
+					// The work is finished; the standby has nothing to take over
+					if standby != nil {
+						standby.task.Cancel(nil)
+					}
+					p.wait(active, standby)
+					return
+				}
+			default:
+				// The active task is being drained and may still finish
+				// its current work while the standby takes over
+			}
+			retired = append(retired, active)
+			p.promote(active, standby, stopping)
+		case <-memberDone(standby):
+			if !stopping {
+				p.m.logf("standby for %s exited, restarting in %v: %v", p.name, standbyRestartDelay, standby.err)
+			}
+			p.mu.Lock()
+			p.standby = nil
+			p.mu.Unlock()
+		case <-restart:
+			p.mu.Lock()
+			if p.active == nil {
+				p.active = p.start(true)
+			} else {
+				p.standby = p.start(false)
+			}
+			p.mu.Unlock()
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// promote makes standby the active task after active has failed or been
+// canceled, leaving the standby slot empty for a replacement.
+func (p *StandbyPair) promote(active, standby *standbyMember, stopping bool) {
+	reason := context.Cause(active.task.Context())
+	select {
+	case <-active.returned:
+		if active.err != nil {
+			reason = active.err
+		}
+	default:
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active, p.standby = standby, nil
+	if standby == nil {
+		if !stopping {
+			p.m.logf("active %s stopped with no standby, restarting in %v: %v", p.name, standbyRestartDelay, reason)
+		}
+		return
+	}
+	p.promotions++
+	close(standby.promoted)
+	p.m.logf("active %s stopped, promoted standby: %v", p.name, reason)
+}
+
+// wait waits for the tasks of the given members to return.
+func (p *StandbyPair) wait(members ...*standbyMember) {
+	for _, member := range members {
+		if member != nil {
+			<-member.task.Done()
+		}
+	}
+}
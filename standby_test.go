@@ -0,0 +1,197 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestStandbyPromotion 测试活动任务失败时自动提升备用任务并启动新的备用任务
+func TestStandbyPromotion(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	var started atomic.Int32
+	roles := make(chan bool, 3)
+	fail := make(chan struct{})
+	pair := m.GoStandby("loop", func(ctx context.Context) error {
+		started.Add(1)
+		roles <- IsActive(ctx)
+		if err := AwaitPromotion(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-fail:
+			return errors.New("连接断开")
+		case <-ctx.Done():
+			return nil
+		}
+	})
+
+	first, standby := pair.Active(), pair.Standby()
+	if first == nil || standby == nil {
+		t.Fatal("启动后应同时存在活动任务和备用任务")
+	}
+	if active := <-roles; active == <-roles {
+		t.Error("任务对中应恰好有一个活动任务")
+	}
+
+	fail <- struct{}{}
+	waitFor(t, func() bool { return pair.Active() == standby }, "活动任务失败后应提升备用任务")
+	if pair.Promotions() != 1 {
+		t.Errorf("应提升1次，实际为%d", pair.Promotions())
+	}
+	waitFor(t, func() bool { return pair.Standby() != nil && started.Load() == 3 }, "提升后应启动新的备用任务")
+	if active := <-roles; active {
+		t.Error("新启动的任务应为备用任务")
+	}
+
+	m.Shutdown()
+	select {
+	case <-pair.Done():
+	case <-time.After(time.Second):
+		t.Fatal("关闭后任务对应结束")
+	}
+}
+
+// TestStandbyFailover 测试Failover会取消健康的活动任务并提升备用任务
+func TestStandbyFailover(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	causes := make(chan error, 2)
+	promoted := make(chan bool, 2)
+	started := make(chan struct{}, 2)
+	pair := m.GoStandby("loop", func(ctx context.Context) error {
+		wasActive := IsActive(ctx)
+		started <- struct{}{}
+		if err := AwaitPromotion(ctx); err != nil {
+			return err
+		}
+		if !wasActive {
+			promoted <- IsActive(ctx)
+		}
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return nil
+	})
+
+	standby := pair.Standby()
+	<-started
+	<-started
+	pair.Failover()
+	if cause := <-causes; !errors.Is(cause, ErrFailover) {
+		t.Errorf("被降级任务的取消原因应为ErrFailover，实际为%v", cause)
+	}
+	waitFor(t, func() bool { return pair.Active() == standby }, "Failover后应提升备用任务")
+	select {
+	case active := <-promoted:
+		if !active {
+			t.Error("被提升的任务应处于活动状态")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("备用任务应从AwaitPromotion返回")
+	}
+}
+
+// TestStandbyPromotedOnDrain 测试活动任务在关闭排空时被取消后立即提升备用任务，无需等待其退出
+func TestStandbyPromotedOnDrain(t *testing.T) {
+	finish := make(chan struct{})
+	promoted := make(chan struct{})
+	// 按启动顺序逐个停止任务，先停止活动任务
+	strategy := DrainStrategyFunc(func(ctx context.Context, tasks []*Task) {
+		for _, task := range tasks {
+			task.Cancel(nil)
+			if task.Name() == "loop" {
+				select {
+				case <-promoted:
+				case <-ctx.Done():
+					return
+				}
+				close(finish)
+			}
+			waitTask(ctx, task, 0)
+		}
+	})
+	m := New(WithTimeout(time.Second), WithDrainStrategy(strategy))
+
+	started := make(chan struct{}, 2)
+	pair := m.GoStandby("loop", func(ctx context.Context) error {
+		active := IsActive(ctx)
+		started <- struct{}{}
+		if active {
+			<-ctx.Done()
+			// 排空中的活动任务仍在完成当前工作
+			<-finish
+			return nil
+		}
+		if err := AwaitPromotion(ctx); err != nil {
+			return err
+		}
+		close(promoted)
+		<-ctx.Done()
+		return nil
+	})
+
+	<-started
+	<-started
+	if err := m.Shutdown(); err != nil {
+		t.Fatalf("关闭不应返回错误，实际为%v", err)
+	}
+	if pair.Promotions() != 1 {
+		t.Errorf("排空活动任务时应提升1次，实际为%d", pair.Promotions())
+	}
+	select {
+	case <-pair.Done():
+	default:
+		t.Error("关闭完成后任务对应已结束")
+	}
+}
+
+// TestStandbyFinished 测试活动任务正常返回后备用任务被取消且不再提升
+func TestStandbyFinished(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	pair := m.GoStandby("job", func(ctx context.Context) error {
+		return AwaitPromotion(ctx)
+	})
+	standby := pair.Standby()
+
+	select {
+	case <-pair.Done():
+	case <-time.After(time.Second):
+		t.Fatal("活动任务正常返回后任务对应结束")
+	}
+	if pair.Promotions() != 0 {
+		t.Errorf("不应提升备用任务，实际提升%d次", pair.Promotions())
+	}
+	select {
+	case <-standby.Done():
+	default:
+		t.Error("任务对结束时备用任务应已退出")
+	}
+}
+
+// TestStandbyRestartsStandby 测试提升前退出的备用任务会被重新启动
+func TestStandbyRestartsStandby(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	var standbys atomic.Int32
+	pair := m.GoStandby("loop", func(ctx context.Context) error {
+		if !IsActive(ctx) && standbys.Add(1) == 1 {
+			return errors.New("预热失败")
+		}
+		<-ctx.Done()
+		return nil
+	})
+
+	first := pair.Standby()
+	waitFor(t, func() bool { s := pair.Standby(); return s != nil && s != first }, "退出的备用任务应被替换")
+	if pair.Promotions() != 0 {
+		t.Errorf("备用任务退出不应触发提升，实际提升%d次", pair.Promotions())
+	}
+}
@@ -0,0 +1,80 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+)
+
+// Start runs the hooks registered with OnStart and the startup checks, waits
+// for the warm-up tasks, and returns once the manager is ready, without
+// waiting for a signal. It lets tests and frameworks that embed the manager
+// drive the lifecycle phases themselves: Start, then Wait or Stop. If ctx is
+// done before startup completes, Start returns its error.
+//
+// If Start returns an error, which wraps ErrStartupFailed, call Stop to undo
+// whatever was already started. Start returns ErrAlreadyStarted if it has
+// already been called, even if that call is still running or failed, and
+// ErrAlreadyShutdown once shutdown has begun.
+//
+// Example:
+//
+//	if err := manager.Start(ctx); err != nil {
+//		manager.Stop()
+//		return err
+//	}
+//	defer manager.Stop()
+func (m *Manager) Start(ctx context.Context) error {
+	if m.shutDown() {
+		return ErrAlreadyShutdown
+	}
+	if !m.startCalled.CompareAndSwap(false, true) {
+		return ErrAlreadyStarted
+	}
+	if err := m.runStartHooks(); err != nil {
+		return startupFailed(err)
+	}
+	if _, err := m.startup(ctx, nil); err != nil {
+		return startupFailed(err)
+	}
+	m.startedUp.Store(true)
+	return nil
+}
+
+// Stop shuts the manager down gracefully and returns once shutdown has
+// completed. If Wait is blocked in another goroutine, Stop makes it shut down
+// and waits for it to return; otherwise it shuts down itself. Later calls to
+// Wait return without waiting for a signal. Stop may be called more than
+// once; it returns ErrTimeout if the shutdown timed out, joined with the
+// errors of failed GoErr tasks.
+//
+// Example:
+//
+//	go manager.Wait()
+//	...
+//	manager.Stop()
+func (m *Manager) Stop() error {
+	m.requestStop(nil)
+
+	m.waitingMu.Lock()
+	done := m.waitDone
+	m.waitingMu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+	var err error
+	if m.shutdownTimedOut() {
+		err = m.timeoutErr()
+	}
+	return errors.Join(append([]error{err}, m.failures()...)...)
+}
+
+// beginWait records that Wait is running and returns the channel to close when
+// it returns.
+func (m *Manager) beginWait() chan struct{} {
+	done := make(chan struct{})
+	m.waitingMu.Lock()
+	m.waitDone = done
+	m.waitingMu.Unlock()
+	return done
+}
@@ -0,0 +1,94 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestStartStop 测试显式启动后由Stop结束阻塞的Wait
+func TestStartStop(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var hookRan bool
+	m.OnStart(func(ctx context.Context) error {
+		hookRan = true
+		return nil
+	})
+	m.GoWarmup(func(ctx context.Context) error { return nil })
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("启动失败: %v", err)
+	}
+	if !hookRan || !m.IsReady() {
+		t.Fatal("Start返回时应已运行启动钩子并就绪")
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(waited)
+	}()
+	time.Sleep(time.Millisecond * 20)
+
+	m.Stop()
+	select {
+	case <-waited:
+	default:
+		t.Error("Stop返回时Wait应已返回")
+	}
+	if m.Context().Err() == nil {
+		t.Error("Stop后上下文应已取消")
+	}
+}
+
+// TestStartContext 测试启动期间上下文取消时Start返回错误
+func TestStartContext(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.GoWarmup(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	if err := m.Start(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("应返回上下文错误，实际为%v", err)
+	}
+	if m.IsReady() {
+		t.Error("启动未完成时不应就绪")
+	}
+	m.Stop()
+}
+
+// TestStartConcurrent 测试并发调用Start时启动钩子只运行一次
+func TestStartConcurrent(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Stop()
+
+	var hooks atomic.Int32
+	m.OnStart(func(ctx context.Context) error {
+		hooks.Add(1)
+		time.Sleep(time.Millisecond * 20)
+		return nil
+	})
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- m.Start(context.Background()) }()
+	}
+	var already int
+	for i := 0; i < 2; i++ {
+		if err := <-errs; errors.Is(err, ErrAlreadyStarted) {
+			already++
+		} else if err != nil {
+			t.Errorf("启动不应失败，实际为%v", err)
+		}
+	}
+	if hooks.Load() != 1 || already != 1 {
+		t.Errorf("启动钩子应只运行1次且另一次调用返回ErrAlreadyStarted，实际运行%d次，%d次ErrAlreadyStarted", hooks.Load(), already)
+	}
+}
@@ -0,0 +1,59 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// WithStartTimeout returns an Option that sets the budget for the hooks
+// registered with OnStart. The default is 30 seconds.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithStartTimeout(2 * time.Minute))
+func WithStartTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.startTimeout = timeout
+	}
+}
+
+// OnStart registers a function that Run calls before the start functions,
+// mirroring OnShutdown: migrations, registrations and other one-off startup
+// steps belong here. Start hooks run once, in registration order, with a
+// context bounded by the start timeout. If one returns an error, the
+// remaining hooks are skipped, Run shuts down through the regular shutdown
+// sequence, so hooks that succeeded can be undone with OnShutdown, and the
+// process exits with the StartupFailure code.
+//
+// Example:
+//
+//	manager.OnStart(func(ctx context.Context) error {
+//		return db.Migrate(ctx)
+//	})
+func (m *Manager) OnStart(f func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.startHooks = append(m.startHooks, f)
+}
+
+// runStartHooks runs the start hooks in order and clears them, returning the
+// first error.
+func (m *Manager) runStartHooks() error {
+	m.mu.Lock()
+	hooks := m.startHooks
+	m.startHooks = nil
+	m.mu.Unlock()
+
+	if len(hooks) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(m.Context(), m.startTimeout)
+	defer cancel()
+	for _, f := range hooks {
+		if err := f(ctx); err != nil {
+			m.logf("start hook failed: %v", err)
+			return err
+		}
+	}
+	return nil
+}
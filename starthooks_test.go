@@ -0,0 +1,79 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestOnStart 测试启动钩子按顺序在启动函数之前运行
+func TestOnStart(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	m := New(WithTimeout(time.Second), WithTriggers(ContextTrigger(cancelledContext())))
+	var order []string
+	m.OnStart(func(ctx context.Context) error {
+		order = append(order, "migrate")
+		return nil
+	})
+	m.OnStart(func(ctx context.Context) error {
+		order = append(order, "register")
+		return nil
+	})
+	m.Run(func(ctx context.Context) error {
+		order = append(order, "start")
+		m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+		return nil
+	})
+
+	if len(order) != 3 || order[0] != "migrate" || order[1] != "register" || order[2] != "start" {
+		t.Errorf("执行顺序不正确: %v", order)
+	}
+	if code != DefaultExitCodes().Clean {
+		t.Errorf("退出码应为%d，实际为%d", DefaultExitCodes().Clean, code)
+	}
+}
+
+// TestOnStartFailure 测试启动钩子失败时回滚并以启动失败退出
+func TestOnStartFailure(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	m := New(WithTimeout(time.Second))
+	rolledBack := false
+	m.OnStart(func(ctx context.Context) error {
+		m.OnShutdown(func(ctx context.Context) error {
+			rolledBack = true
+			return nil
+		})
+		return nil
+	})
+	m.OnStart(func(ctx context.Context) error { return errors.New("迁移失败") })
+	m.OnStart(func(ctx context.Context) error {
+		t.Error("失败之后的启动钩子不应运行")
+		return nil
+	})
+	m.Run(func(ctx context.Context) error {
+		t.Error("启动钩子失败时不应调用启动函数")
+		return nil
+	})
+
+	if code != DefaultExitCodes().StartupFailure {
+		t.Errorf("退出码应为%d，实际为%d", DefaultExitCodes().StartupFailure, code)
+	}
+	if !rolledBack {
+		t.Error("应通过关闭钩子回滚已成功的启动钩子")
+	}
+}
+
+// cancelledContext 返回一个已取消的上下文
+func cancelledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
@@ -0,0 +1,139 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrAlreadyStarted is returned by Start when startup has already completed.
+var ErrAlreadyStarted = errors.New("graceful: already started")
+
+// State is a lifecycle milestone of a manager. A manager moves through the
+// states in order and never returns to an earlier one, though it may skip
+// StateRunning: a manager can be shut down without ever being started, for
+// example in tests or in library code paths, and Shutdown then runs the same
+// sequence and stops the signal handlers, monitors and child reaper New
+// started. The process-wide policy of WithIgnoredSignals and
+// WithResetSignals is left in place.
+//
+// Methods that only make sense in some states return typed errors instead of
+// hanging or repeating work: Start returns ErrAlreadyStarted once startup
+// has completed, and Start, Restart and Rehearse return ErrAlreadyShutdown
+// once shutdown has begun, while Wait and Shutdown return it immediately.
+type State int
+
+const (
+	// StateStarting is the state of a new manager until startup completes.
+	StateStarting State = iota
+	// StateRunning is entered once Start, Run or Wait has completed startup:
+	// start hooks, startup checks and warm-up tasks have finished and
+	// readiness has turned true.
+	StateRunning
+	// StateDraining is entered when shutdown begins.
+	StateDraining
+	// StateStopped is entered when the shutdown sequence has completed.
+	StateStopped
+)
+
+func (s State) String() string {
+	switch s {
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateDraining:
+		return "draining"
+	case StateStopped:
+		return "stopped"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// WaitFor blocks until the manager reaches state, and returns nil
+// immediately if it already has. It returns ctx's error if ctx is done
+// first. Waiting for StateRunning returns ErrAlreadyShutdown if shutdown
+// began before startup completed, since the state will never be reached.
+//
+// Example:
+//
+//	go manager.Run(start)
+//	if err := manager.WaitFor(ctx, graceful.StateRunning); err != nil {
+//		return err
+//	}
+func (m *Manager) WaitFor(ctx context.Context, state State) error {
+	for {
+		m.stateMu.Lock()
+		current, reached, changed := m.state, m.hasReached(state), m.stateChanged
+		m.stateMu.Unlock()
+
+		if reached {
+			return nil
+		}
+		if current > state {
+			return ErrAlreadyShutdown
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// hasReached reports whether the manager has been in state. It must be
+// called with stateMu held.
+func (m *Manager) hasReached(state State) bool {
+	if state < StateStarting || state > StateStopped {
+		return false
+	}
+	return state == StateStarting || m.reached[state]
+}
+
+// State returns the state the manager is in.
+func (m *Manager) State() State {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	return m.state
+}
+
+// ShuttingDown reports whether shutdown has begun, for health checks and
+// middleware that are not managed goroutines but must know the manager is
+// draining, for example to answer 503. Rehearsals are not reported.
+//
+// Example:
+//
+//	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+//		if manager.ShuttingDown() {
+//			w.WriteHeader(http.StatusServiceUnavailable)
+//		}
+//	})
+func (m *Manager) ShuttingDown() bool {
+	return m.shutDown()
+}
+
+// Done returns a channel that is closed once the shutdown sequence has
+// completed, not merely begun: goroutines have exited or the timeout
+// expired, and the hooks and the flush phase have run.
+func (m *Manager) Done() <-chan struct{} {
+	return m.done
+}
+
+// shutDown reports whether shutdown has begun.
+func (m *Manager) shutDown() bool {
+	return m.State() >= StateDraining
+}
+
+// setState moves the manager to state and wakes WaitFor callers. Moving to
+// an earlier state or the current one has no effect.
+func (m *Manager) setState(state State) {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+	if state <= m.state {
+		return
+	}
+	m.state = state
+	m.reached[state] = true
+	close(m.stateChanged)
+	m.stateChanged = make(chan struct{})
+}
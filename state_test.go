@@ -0,0 +1,162 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestWaitFor 测试等待生命周期各阶段
+func TestWaitFor(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := m.WaitFor(ctx, StateStarting); err != nil {
+		t.Fatalf("新管理器应已处于starting，实际为%v", err)
+	}
+
+	reached := make(chan State, 3)
+	for _, s := range []State{StateRunning, StateDraining, StateStopped} {
+		s := s
+		go func() {
+			if err := m.WaitFor(ctx, s); err != nil {
+				t.Errorf("等待%v失败: %v", s, err)
+			}
+			reached <- s
+		}()
+	}
+
+	if err := m.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if s := <-reached; s != StateRunning {
+		t.Errorf("应先到达running，实际为%v", s)
+	}
+	m.Shutdown()
+	if s := <-reached; s != StateDraining && s != StateStopped {
+		t.Errorf("关闭后应到达draining或stopped，实际为%v", s)
+	}
+	<-reached
+}
+
+// TestWaitForUnreachable 测试启动完成前关闭时等待running返回错误
+func TestWaitForUnreachable(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.Shutdown()
+
+	if err := m.WaitFor(context.Background(), StateRunning); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+	if err := m.WaitFor(context.Background(), StateStopped); err != nil {
+		t.Errorf("应已到达stopped，实际为%v", err)
+	}
+}
+
+// TestWaitForContext 测试上下文结束时返回其错误
+func TestWaitForContext(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	if err := m.WaitFor(ctx, StateDraining); err != context.DeadlineExceeded {
+		t.Errorf("应返回context.DeadlineExceeded，实际为%v", err)
+	}
+}
+
+// TestNeverStarted 测试未启动的管理器可以直接关闭并释放资源
+func TestNeverStarted(t *testing.T) {
+	before := runtime.NumGoroutine()
+	m := New(WithTimeout(time.Second), WithStatusSignal(io.Discard), WithGoroutineBudget(1000, time.Millisecond*10))
+
+	var hookRan bool
+	m.OnShutdown(func(ctx context.Context) error {
+		hookRan = true
+		return nil
+	})
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+
+	if err := m.Shutdown(); err != nil {
+		t.Fatalf("关闭未启动的管理器不应返回错误，实际为%v", err)
+	}
+	if !hookRan {
+		t.Error("关闭钩子应已运行")
+	}
+	waitFor(t, func() bool { return runtime.NumGoroutine() <= before }, "New启动的goroutine应已退出")
+
+	if err := m.WaitFor(context.Background(), StateRunning); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("未启动即关闭时等待运行状态应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+}
+
+// TestStateMisuse 测试在错误的状态下调用方法返回类型化错误
+func TestStateMisuse(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.Go(func() {})
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("启动失败: %v", err)
+	}
+	if err := m.Start(context.Background()); !errors.Is(err, ErrAlreadyStarted) {
+		t.Errorf("重复启动应返回ErrAlreadyStarted，实际为%v", err)
+	}
+
+	m.Shutdown()
+	if err := m.Start(context.Background()); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("关闭后启动应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+	if err := m.Restart(); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("关闭后重启应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+	if _, err := m.Rehearse(); !errors.Is(err, ErrAlreadyShutdown) {
+		t.Errorf("关闭后演练应返回ErrAlreadyShutdown，实际为%v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- m.Wait() }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrAlreadyShutdown) {
+			t.Errorf("关闭后Wait应返回ErrAlreadyShutdown，实际为%v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("关闭后Wait不应阻塞")
+	}
+}
+
+// TestShutdownObservers 测试外部观察者可以查询关闭状态和等待关闭完成
+func TestShutdownObservers(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	if m.ShuttingDown() || m.State() != StateStarting {
+		t.Errorf("新建的管理器不应处于关闭中，状态为%v", m.State())
+	}
+
+	release := make(chan struct{})
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		<-release
+	})
+	go m.Shutdown()
+
+	waitFor(t, m.ShuttingDown, "关闭开始后ShuttingDown应返回true")
+	if m.State() != StateDraining {
+		t.Errorf("排空期间状态应为draining，实际为%v", m.State())
+	}
+	select {
+	case <-m.Done():
+		t.Fatal("关闭完成前Done不应关闭")
+	default:
+	}
+
+	close(release)
+	select {
+	case <-m.Done():
+	case <-time.After(time.Second):
+		t.Fatal("关闭完成后Done应关闭")
+	}
+	if m.State() != StateStopped {
+		t.Errorf("关闭完成后状态应为stopped，实际为%v", m.State())
+	}
+}
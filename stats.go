@@ -0,0 +1,142 @@
+package graceful
+
+import (
+	"context"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"time"
+)
+
+// allocsMetric is the runtime/metrics name of cumulative heap allocations.
+const allocsMetric = "/gc/heap/allocs:bytes"
+
+// Stats is a snapshot of the manager's runtime state.
+type Stats struct {
+	Goroutines       int             // Goroutines in the process
+	Managed          int             // Goroutines started through the manager still running
+	AttachedContexts int             // Contexts from AttachContext not yet done
+	Signals          []SignalRecord  // Most recent signals received, oldest first
+	Listeners        []ListenerStats // Connections of listeners created with Listen
+	Tasks            []TaskStats     // Per-task accounting, if enabled with WithTaskAccounting
+}
+
+// TaskStats describes the resources observed while tasks with one name ran.
+// Unnamed tasks are identified by their function name.
+//
+// Both deltas are process-wide samples taken at the start and end of each
+// task, so they attribute growth reliably only to long-lived tasks, or when
+// comparing tasks against each other.
+type TaskStats struct {
+	Name           string        // Task name, or function name for unnamed tasks
+	Running        int           // Tasks with this name still running
+	Runs           int           // Tasks with this name started so far
+	Runtime        time.Duration // Total runtime, including running tasks
+	GoroutineDelta int           // Change in the goroutine count while the tasks ran
+	AllocBytes     uint64        // Bytes allocated on the heap while the tasks ran
+}
+
+// taskAccount holds the samples taken when a task started.
+type taskAccount struct {
+	name       string
+	start      time.Time
+	goroutines int
+	allocs     uint64
+}
+
+// WithTaskAccounting returns an Option that samples the goroutine count and
+// heap allocations around each task started with CtxGo and reports them in
+// Stats, to help identify which component is responsible for resource growth.
+// Sampling adds a small cost to every task start and exit.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithTaskAccounting())
+func WithTaskAccounting() Option {
+	return func(m *Manager) {
+		m.accounting = true
+		m.running = make(map[*taskAccount]struct{})
+		m.finished = make(map[string]*TaskStats)
+	}
+}
+
+// Stats returns a snapshot of the manager's runtime state.
+func (m *Manager) Stats() Stats {
+	s := Stats{
+		Goroutines:       runtime.NumGoroutine(),
+		Managed:          int(m.managed.Load()),
+		AttachedContexts: m.attachedContexts(),
+		Signals:          m.signalHistory(),
+		Listeners:        m.listenerStats(),
+	}
+	if !m.accounting {
+		return s
+	}
+
+	now, goroutines, allocs := time.Now(), runtime.NumGoroutine(), readAllocs()
+	m.statsMu.Lock()
+	byName := make(map[string]*TaskStats, len(m.finished))
+	for name, ts := range m.finished {
+		copied := *ts
+		byName[name] = &copied
+	}
+	for a := range m.running {
+		ts := byName[a.name]
+		if ts == nil {
+			ts = &TaskStats{Name: a.name}
+			byName[a.name] = ts
+		}
+		ts.Running++
+		ts.Runs++
+		a.addTo(ts, now, goroutines, allocs)
+	}
+	m.statsMu.Unlock()
+
+	for _, ts := range byName {
+		s.Tasks = append(s.Tasks, *ts)
+	}
+	sort.Slice(s.Tasks, func(i, j int) bool { return s.Tasks[i].Name < s.Tasks[j].Name })
+	return s
+}
+
+// startAccount takes the samples for a task that is about to run f.
+func (m *Manager) startAccount(t *Task, f func(ctx context.Context)) *taskAccount {
+	a := &taskAccount{name: taskName(t, f), start: time.Now(), goroutines: runtime.NumGoroutine(), allocs: readAllocs()}
+	m.statsMu.Lock()
+	m.running[a] = struct{}{}
+	m.statsMu.Unlock()
+	return a
+}
+
+// finishAccount records the resources observed while a task ran.
+func (m *Manager) finishAccount(a *taskAccount) {
+	now, goroutines, allocs := time.Now(), runtime.NumGoroutine(), readAllocs()
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+	delete(m.running, a)
+	ts := m.finished[a.name]
+	if ts == nil {
+		ts = &TaskStats{Name: a.name}
+		m.finished[a.name] = ts
+	}
+	ts.Runs++
+	a.addTo(ts, now, goroutines, allocs)
+}
+
+// addTo adds the difference between the task's start samples and the given
+// ones to ts.
+func (a *taskAccount) addTo(ts *TaskStats, now time.Time, goroutines int, allocs uint64) {
+	ts.Runtime += now.Sub(a.start)
+	ts.GoroutineDelta += goroutines - a.goroutines
+	ts.AllocBytes += allocs - a.allocs
+}
+
+// readAllocs returns the cumulative number of bytes allocated on the heap.
+func readAllocs() uint64 {
+	sample := []metrics.Sample{{Name: allocsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
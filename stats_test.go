@@ -0,0 +1,58 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestStatsTaskAccounting 测试按任务名称统计运行时间和内存分配
+func TestStatsTaskAccounting(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithTaskAccounting())
+
+	var sink [][]byte
+	m.CtxGo(func(ctx context.Context) {
+		for i := 0; i < 10; i++ {
+			sink = append(sink, make([]byte, 1<<16))
+		}
+		time.Sleep(time.Millisecond * 20)
+	}, WithName("allocator"))
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() }, WithName("idle"))
+
+	time.Sleep(time.Millisecond * 50)
+	stats := m.Stats()
+	if len(stats.Tasks) != 2 {
+		t.Fatalf("应统计2个任务，实际为%d", len(stats.Tasks))
+	}
+
+	alloc, idle := stats.Tasks[0], stats.Tasks[1]
+	if alloc.Name != "allocator" || idle.Name != "idle" {
+		t.Fatalf("任务应按名称排序，实际为%q和%q", alloc.Name, idle.Name)
+	}
+	if alloc.Running != 0 || alloc.Runs != 1 {
+		t.Errorf("allocator应已结束一次，实际运行中%d，运行次数%d", alloc.Running, alloc.Runs)
+	}
+	if alloc.AllocBytes < 10<<16 {
+		t.Errorf("allocator应至少分配%d字节，实际为%d", 10<<16, alloc.AllocBytes)
+	}
+	if alloc.Runtime < time.Millisecond*20 {
+		t.Errorf("allocator运行时间应至少20ms，实际为%v", alloc.Runtime)
+	}
+	if idle.Running != 1 {
+		t.Errorf("idle应仍在运行，实际为%d", idle.Running)
+	}
+
+	m.Shutdown()
+	_ = sink
+}
+
+// TestStatsWithoutAccounting 测试未启用统计时不报告任务
+func TestStatsWithoutAccounting(t *testing.T) {
+	m := New()
+	m.CtxGo(func(ctx context.Context) {})
+	m.Shutdown()
+
+	if stats := m.Stats(); len(stats.Tasks) != 0 || stats.Goroutines == 0 {
+		t.Errorf("未启用统计时不应报告任务，实际为%+v", stats)
+	}
+}
@@ -0,0 +1,98 @@
+package graceful
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WithStatusSignal returns an Option that writes a status report to w, or to
+// standard error if w is nil, whenever the platform's status signal is
+// received. The status signal is SIGINFO (Ctrl+T in a terminal) on macOS and
+// the BSDs; on other platforms the option has no effect. Unlike SIGQUIT, which
+// prints a similar goroutine dump, SIGINFO leaves the process running.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithStatusSignal(os.Stderr))
+func WithStatusSignal(w io.Writer) Option {
+	return func(m *Manager) {
+		if w == nil {
+			w = os.Stderr
+		}
+		m.statusWriter = w
+	}
+}
+
+// WriteStatus writes a human-readable status report to w: the lifecycle
+// summary, the connections of managed listeners, the names of running tasks,
+// and the stacks of all goroutines.
+func (m *Manager) WriteStatus(w io.Writer) error {
+	s := m.Summary()
+
+	m.mu.Lock()
+	names := make([]string, 0, len(m.tasks))
+	for name := range m.tasks {
+		names = append(names, name)
+	}
+	m.mu.Unlock()
+	sort.Strings(names)
+
+	ready := "not ready"
+	if m.IsReady() {
+		ready = "ready"
+	}
+
+	var listeners strings.Builder
+	for _, ls := range m.listenerStats() {
+		fmt.Fprintf(&listeners, "graceful: listener %s: %d open connections, %d in flight, oldest %v\n",
+			ls.Address, ls.OpenConns, ls.InFlight, ls.OldestConn.Round(time.Millisecond))
+	}
+
+	var signals strings.Builder
+	for _, r := range m.signalHistory() {
+		action := "delivered"
+		if !r.Delivered {
+			action = "dropped"
+		}
+		fmt.Fprintf(&signals, "graceful: signal %v at %s, %s\n", r.Signal, r.Time.Format(time.RFC3339Nano), action)
+	}
+
+	buf := make([]byte, 1<<20)
+	buf = buf[:runtime.Stack(buf, true)]
+
+	_, err := fmt.Fprintf(w, "graceful: %s, %d services, %d tasks started, listening on %v\n"+
+		"%sgraceful: named tasks: %v\n"+
+		"%sgraceful: %d goroutines, %d attached contexts\n\n%s\n",
+		ready, s.Services, s.Tasks, s.Addresses, listeners.String(), names, signals.String(),
+		runtime.NumGoroutine(), m.attachedContexts(), buf)
+	return err
+}
+
+// handleStatusSignal writes a status report for every status signal received.
+// It returns when shutdown begins.
+func (m *Manager) handleStatusSignal() {
+	if len(statusSignals) == 0 {
+		return
+	}
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, statusSignals...)
+
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-m.lifetime.Done():
+				return
+			case sig := <-sigCh:
+				m.recordSignal(sig, time.Now(), true)
+				_ = m.WriteStatus(m.statusWriter)
+			}
+		}
+	}()
+}
@@ -0,0 +1,11 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package graceful
+
+import (
+	"os"
+	"syscall"
+)
+
+// statusSignals are the signals that trigger a status report.
+var statusSignals = []os.Signal{syscall.SIGINFO}
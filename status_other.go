@@ -0,0 +1,9 @@
+//go:build !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package graceful
+
+import "os"
+
+// statusSignals are the signals that trigger a status report. This platform
+// has no dedicated status signal.
+var statusSignals []os.Signal
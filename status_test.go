@@ -0,0 +1,30 @@
+package graceful
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWriteStatus 测试状态报告包含任务名称和goroutine堆栈
+func TestWriteStatus(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() }, WithName("indexer"))
+	defer m.Shutdown()
+
+	var b strings.Builder
+	if err := m.WriteStatus(&b); err != nil {
+		t.Fatalf("写入状态报告失败: %v", err)
+	}
+	out := b.String()
+	if !strings.Contains(out, "indexer") {
+		t.Errorf("状态报告应包含任务名称，实际为: %s", out)
+	}
+	if !strings.Contains(out, "goroutine ") {
+		t.Error("状态报告应包含goroutine堆栈")
+	}
+	if !strings.Contains(out, "not ready") {
+		t.Error("启动完成前状态报告应为not ready")
+	}
+}
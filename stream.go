@@ -0,0 +1,104 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// stream is a long-lived stream registered with RegisterStream.
+type stream struct {
+	maxDuration time.Duration             // Time finish may take
+	finish      func(ctx context.Context) // Ends the stream cleanly
+}
+
+// RegisterStream registers a graceful finish callback for a long-lived
+// stream, such as a server-streaming RPC. When shutdown begins, before drain
+// functions run, the manager calls the finish callbacks of all open streams
+// concurrently, each with a context bounded by its declared maxDuration and
+// the shutdown timeout, so that handlers can send trailers or end-of-stream
+// messages instead of being cut off by context cancellation.
+//
+// Call the returned function when the stream ends. Streams registered after
+// shutdown has begun are finished right away.
+//
+// Example:
+//
+//	func (s *server) Watch(req *pb.WatchRequest, stream pb.Service_WatchServer) error {
+//		finished := make(chan struct{})
+//		done := manager.RegisterStream(2*time.Second, func(ctx context.Context) {
+//			_ = stream.Send(&pb.Event{EndOfStream: true})
+//			close(finished)
+//		})
+//		defer done()
+//		for {
+//			select {
+//			case ev := <-s.events:
+//				if err := stream.Send(ev); err != nil {
+//					return err
+//				}
+//			case <-finished:
+//				return nil
+//			}
+//		}
+//	}
+func (m *Manager) RegisterStream(maxDuration time.Duration, finish func(ctx context.Context)) (done func()) {
+	s := &stream{maxDuration: maxDuration, finish: finish}
+
+	m.streamsMu.Lock()
+	finishing := m.streamsFinishing
+	if !finishing {
+		if m.streams == nil {
+			m.streams = make(map[*stream]struct{})
+		}
+		m.streams[s] = struct{}{}
+	}
+	m.streamsMu.Unlock()
+
+	if finishing {
+		m.Go(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), maxDuration)
+			defer cancel()
+			finish(ctx)
+		})
+		return func() {}
+	}
+	return func() {
+		m.streamsMu.Lock()
+		delete(m.streams, s)
+		m.streamsMu.Unlock()
+	}
+}
+
+// reopenStreams lets new streams open normally again after finishStreams, for
+// a rehearsal or restart.
+func (m *Manager) reopenStreams() {
+	m.streamsMu.Lock()
+	m.streamsFinishing = false
+	m.streamsMu.Unlock()
+}
+
+// finishStreams calls the finish callbacks of the open streams and waits for
+// them, each bounded by its own maximum duration and by ctx.
+func (m *Manager) finishStreams(ctx context.Context) {
+	m.streamsMu.Lock()
+	m.streamsFinishing = true
+	streams := make([]*stream, 0, len(m.streams))
+	for s := range m.streams {
+		streams = append(streams, s)
+	}
+	m.streams = nil
+	m.streamsMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range streams {
+		wg.Add(1)
+		go func(s *stream) {
+			defer wg.Done()
+			sctx, cancel := context.WithTimeout(ctx, s.maxDuration)
+			defer cancel()
+			runBounded(sctx, func() { s.finish(sctx) })
+		}(s)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,46 @@
+package graceful
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestRegisterStream 测试关闭开始时调用流的优雅结束回调
+func TestRegisterStream(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var finished, drainedAfter atomic.Bool
+	m.OnDrain(func(ctx context.Context) error {
+		drainedAfter.Store(finished.Load())
+		return nil
+	})
+	m.RegisterStream(time.Second, func(ctx context.Context) { finished.Store(true) })
+
+	closed := m.RegisterStream(time.Second, func(ctx context.Context) { t.Error("已结束的流不应被回调") })
+	closed()
+
+	// 超出声明时长的回调不应拖延关闭
+	m.RegisterStream(time.Millisecond*50, func(ctx context.Context) { time.Sleep(time.Hour) })
+
+	begin := time.Now()
+	m.Shutdown()
+	if !finished.Load() {
+		t.Error("关闭时应调用结束回调")
+	}
+	if !drainedAfter.Load() {
+		t.Error("结束回调应在排空函数之前运行")
+	}
+	if elapsed := time.Since(begin); elapsed > time.Millisecond*500 {
+		t.Errorf("回调应受声明时长限制，实际耗时%v", elapsed)
+	}
+
+	late := make(chan struct{})
+	m.RegisterStream(time.Second, func(ctx context.Context) { close(late) })
+	select {
+	case <-late:
+	case <-time.After(time.Second):
+		t.Error("关闭后注册的流应立即结束")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build linux
+
+package graceful
+
+import "syscall"
+
+// prSetChildSubreaper is the prctl option that makes the calling process
+// adopt orphaned descendants.
+const prSetChildSubreaper = 36
+
+// setSubreaper registers the process as a child subreaper.
+func setSubreaper() error {
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetChildSubreaper, 1, 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build unix && !linux
+
+package graceful
+
+// setSubreaper does nothing; only Linux supports child subreapers, and
+// orphans are adopted by PID 1 elsewhere.
+func setSubreaper() error {
+	return nil
+}
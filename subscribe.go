@@ -0,0 +1,97 @@
+package graceful
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Source connects to an update stream, such as a config or feature-flag
+// watch, and calls deliver for every update until the stream fails or ctx is
+// canceled. It returns the error that ended the stream.
+type Source[T any] func(ctx context.Context, deliver func(update T)) error
+
+// SubscribeOption configures a subscription started with Subscribe.
+type SubscribeOption func(*subscribeConfig)
+
+// subscribeConfig holds the settings applied by SubscribeOption values.
+type subscribeConfig struct {
+	backoff Backoff // Delay before each reconnection
+}
+
+// WithReconnectBackoff returns a SubscribeOption that sets the delay before
+// each reconnection. The default backs off exponentially from 100ms to 30s.
+// The attempt count starts over once a connection has delivered an update.
+//
+// Example:
+//
+//	graceful.Subscribe(manager, flags.Watch, apply,
+//		graceful.WithReconnectBackoff(graceful.ConstantBackoff(time.Second)))
+func WithReconnectBackoff(backoff Backoff) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.backoff = backoff
+	}
+}
+
+// Subscribe keeps a subscription to source open in a managed goroutine,
+// calling onUpdate for every update it delivers and reconnecting with
+// backoff whenever the stream ends. No connection is opened while the
+// manager is paused. When shutdown begins, the stream's context is canceled
+// with ErrDraining, updates stop being applied, and a drain function waits
+// for the subscription to close, so components that depend on the stream
+// stay up until it is gone and no update arrives while they shut down.
+//
+// Example:
+//
+//	graceful.Subscribe(manager, func(ctx context.Context, deliver func(Flags)) error {
+//		return flagClient.Watch(ctx, deliver)
+//	}, func(f Flags) {
+//		currentFlags.Store(&f)
+//	})
+func Subscribe[T any](m *Manager, source Source[T], onUpdate func(update T), opts ...SubscribeOption) *Task {
+	cfg := subscribeConfig{backoff: ExponentialBackoff(100*time.Millisecond, 30*time.Second)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	task := m.CtxGo(func(ctx context.Context) {
+		// Close the stream as soon as shutdown begins
+		ctx = m.AttachContext(ctx)
+		for attempt := 1; ; attempt++ {
+			if m.Paused() && m.WaitResumed(ctx) != nil {
+				return
+			}
+
+			var delivered atomic.Bool
+			err := source(ctx, func(update T) {
+				if ctx.Err() != nil {
+					return
+				}
+				delivered.Store(true)
+				onUpdate(update)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if delivered.Load() {
+				attempt = 1
+			}
+
+			delay := cfg.backoff(attempt)
+			m.logf("subscription ended, reconnecting in %v: %v", delay, err)
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	})
+
+	m.OnDrain(func(ctx context.Context) error {
+		waitTask(ctx, task, 0)
+		return nil
+	})
+	return task
+}
@@ -0,0 +1,102 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSubscribeReconnect 测试订阅断开后按退避重连并继续应用更新
+func TestSubscribeReconnect(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var connects int32
+	var mu sync.Mutex
+	var applied []int
+	Subscribe(m, func(ctx context.Context, deliver func(int)) error {
+		n := atomic.AddInt32(&connects, 1)
+		deliver(int(n))
+		if n < 3 {
+			return errors.New("stream reset")
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}, func(v int) {
+		mu.Lock()
+		defer mu.Unlock()
+		applied = append(applied, v)
+	}, WithReconnectBackoff(ConstantBackoff(time.Millisecond)))
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(applied) == 3
+	}, "断开后应重连并应用每次连接的更新")
+	m.Shutdown()
+
+	if n := atomic.LoadInt32(&connects); n != 3 {
+		t.Errorf("应连接3次，实际为%d次", n)
+	}
+}
+
+// TestSubscribeClosedBeforeHooks 测试订阅在关闭钩子运行前已关闭
+func TestSubscribeClosedBeforeHooks(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	connected := make(chan struct{})
+	var closed, lateUpdate atomic.Bool
+	Subscribe(m, func(ctx context.Context, deliver func(string)) error {
+		close(connected)
+		<-ctx.Done()
+		if !errors.Is(context.Cause(ctx), ErrDraining) {
+			t.Errorf("订阅上下文应以ErrDraining取消，实际为%v", context.Cause(ctx))
+		}
+		// 排空开始后送达的更新不应被应用
+		deliver("late")
+		time.Sleep(time.Millisecond * 20)
+		closed.Store(true)
+		return ctx.Err()
+	}, func(string) {
+		lateUpdate.Store(true)
+	})
+
+	var closedAtHook bool
+	m.OnShutdown(func(ctx context.Context) error {
+		closedAtHook = closed.Load()
+		return nil
+	})
+
+	<-connected
+	m.Shutdown()
+
+	if !closedAtHook {
+		t.Error("关闭钩子运行前订阅应已关闭")
+	}
+	if lateUpdate.Load() {
+		t.Error("排空开始后不应再应用更新")
+	}
+}
+
+// TestSubscribePaused 测试暂停期间不建立连接
+func TestSubscribePaused(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	m.Pause()
+
+	var connects int32
+	Subscribe(m, func(ctx context.Context, deliver func(int)) error {
+		atomic.AddInt32(&connects, 1)
+		<-ctx.Done()
+		return ctx.Err()
+	}, func(int) {})
+
+	time.Sleep(time.Millisecond * 30)
+	if n := atomic.LoadInt32(&connects); n != 0 {
+		t.Errorf("暂停期间不应连接，实际连接了%d次", n)
+	}
+	m.Resume()
+	waitFor(t, func() bool { return atomic.LoadInt32(&connects) == 1 }, "恢复后应建立连接")
+	m.Shutdown()
+}
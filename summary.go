@@ -0,0 +1,73 @@
+package graceful
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// EventStarted is emitted once startup has completed, with the startup summary
+// in Event.Summary.
+const EventStarted EventType = "started"
+
+// Summary describes how the manager is configured once startup has completed,
+// so operators can check at a glance that the lifecycle is set up as intended.
+type Summary struct {
+	Services         int           // Start functions run by Run
+	Tasks            int           // Goroutines started so far
+	Addresses        []string      // Listen addresses registered with Listen or AddAddress
+	DrainFunctions   int           // Functions registered with OnDrain
+	ShutdownHooks    int           // Hooks registered with OnShutdown
+	FlushFunctions   int           // Functions registered with OnFlush
+	Timeout          time.Duration // Shutdown timeout
+	FlushTimeout     time.Duration // Flush phase budget
+	TelemetryTimeout time.Duration // Telemetry shutdown budget
+}
+
+// Summary returns the current lifecycle configuration.
+func (m *Manager) Summary() Summary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return Summary{
+		Services:         len(m.starts),
+		Tasks:            m.started,
+		Addresses:        append([]string(nil), m.addresses...),
+		DrainFunctions:   len(m.drainers),
+		ShutdownHooks:    len(m.hooks),
+		FlushFunctions:   len(m.flushers),
+		Timeout:          m.timeout,
+		FlushTimeout:     m.flushTimeout,
+		TelemetryTimeout: m.telemetryTimeout,
+	}
+}
+
+// startup validates the configuration, waits for warm-up tasks, opens the
+// readiness gate and announces the startup summary. It returns the signal
+// received on sigCh if one arrives while warm-up tasks are still running, or
+// an error if the caller should shut down instead of waiting for a signal,
+// including the error of ctx if it is done first.
+func (m *Manager) startup(ctx context.Context, sigCh <-chan os.Signal) (os.Signal, error) {
+	if err := m.checkRegistered(); err != nil {
+		return nil, err
+	}
+	if err := m.checkBudget(); err != nil {
+		return nil, err
+	}
+	if sig, err := m.waitWarmups(ctx, sigCh); sig != nil || err != nil {
+		return sig, err
+	}
+	m.markReady()
+	m.setState(StateRunning)
+	m.announceStartup()
+	m.logPreviousReport()
+	return nil, nil
+}
+
+// announceStartup logs the startup summary and emits EventStarted.
+func (m *Manager) announceStartup() {
+	s := m.Summary()
+	m.logf("started %d services and %d tasks, listening on %v, %d drain functions, %d shutdown hooks, %d flush functions, timeout %v, flush timeout %v, telemetry timeout %v",
+		s.Services, s.Tasks, s.Addresses, s.DrainFunctions, s.ShutdownHooks, s.FlushFunctions,
+		s.Timeout, s.FlushTimeout, s.TelemetryTimeout)
+	m.emit(Event{Type: EventStarted, Summary: &s})
+}
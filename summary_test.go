@@ -0,0 +1,48 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestStartupSummary 测试启动摘要包含配置信息
+func TestStartupSummary(t *testing.T) {
+	var summary *Summary
+	logger := &recordingLogger{}
+	m := New(
+		WithTimeout(time.Second*3),
+		WithLogger(logger),
+		WithEventHandler(func(e Event) {
+			if e.Type == EventStarted {
+				summary = e.Summary
+			}
+		}),
+	)
+	defer m.Shutdown()
+
+	m.starts = append(m.starts, func(ctx context.Context) error { return nil })
+	m.OnShutdown(func(ctx context.Context) error { return nil })
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+	if _, err := m.Listen("tcp", "127.0.0.1:0"); err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+
+	m.announceStartup()
+
+	if summary == nil {
+		t.Fatal("启动完成后应发出started事件")
+	}
+	if summary.Services != 1 || summary.Tasks != 1 || summary.ShutdownHooks != 1 {
+		t.Errorf("摘要统计不正确: %+v", summary)
+	}
+	if len(summary.Addresses) != 1 || summary.DrainFunctions != 1 {
+		t.Errorf("摘要应包含监听地址: %+v", summary)
+	}
+	if summary.Timeout != time.Second*3 {
+		t.Errorf("摘要中的超时时间应为3s，实际为%v", summary.Timeout)
+	}
+	if len(logger.lines) != 1 {
+		t.Errorf("应记录1条启动日志，实际为%d条", len(logger.lines))
+	}
+}
@@ -0,0 +1,159 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Task is a handle to a goroutine started with CtxGo. Each task runs with its
+// own context derived from the manager's context, so it can be canceled or
+// given a deadline without affecting other tasks.
+type Task struct {
+	name      string                  // Name given with WithName, if any
+	seq       uint64                  // Start order, assigned by register
+	ctx       context.Context         // Context passed to the task function
+	cancel    context.CancelCauseFunc // Cancels ctx with a cause
+	done      chan struct{}           // Closed when the task function returns
+	ready     chan struct{}           // Closed when the task reports readiness
+	readyOnce sync.Once               // Ensures ready is closed once
+	phase     *int                    // Shutdown phase given with WithPhase, if any
+}
+
+// taskKey is the context key under which a task stores itself.
+type taskKey struct{}
+
+// TaskOption defines a function type for configuring individual tasks.
+type TaskOption func(*taskConfig)
+
+// taskConfig holds the settings applied by TaskOption values.
+type taskConfig struct {
+	timeout time.Duration // Deadline for the task context; zero means none
+	name    string        // Name used to look the task up in the registry
+	phase   *int          // Shutdown phase; nil means none
+}
+
+// WithName returns a TaskOption that names the task. Named tasks are kept in
+// the manager's registry while they run, so they can be looked up with
+// Manager.Task and replaced with Manager.Replace. Starting another task with
+// the same name makes the registry point to the newer task.
+//
+// Example:
+//
+//	manager.CtxGo(consume, graceful.WithName("consumer"))
+func WithName(name string) TaskOption {
+	return func(c *taskConfig) {
+		c.name = name
+	}
+}
+
+// WithTaskTimeout returns a TaskOption that cancels the task's context after
+// the given duration, independently of the manager's shutdown. context.Cause
+// reports context.DeadlineExceeded for tasks canceled this way.
+//
+// Example:
+//
+//	manager.CtxGo(func(ctx context.Context) {
+//		syncOnce(ctx)
+//	}, graceful.WithTaskTimeout(time.Minute))
+func WithTaskTimeout(timeout time.Duration) TaskOption {
+	return func(c *taskConfig) {
+		c.timeout = timeout
+	}
+}
+
+// newTask creates a task whose context derives from the manager's context.
+func (m *Manager) newTask(cfg taskConfig) *Task {
+	ctx, cancel := context.WithCancelCause(m.Context())
+	t := &Task{
+		name:   cfg.name,
+		phase:  cfg.phase,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+		ready:  make(chan struct{}),
+	}
+	if cfg.timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		t.ctx, cancelTimeout = context.WithTimeout(ctx, cfg.timeout)
+		t.cancel = func(cause error) {
+			cancel(cause)
+			cancelTimeout()
+		}
+	}
+	t.ctx = context.WithValue(t.ctx, taskKey{}, t)
+	return t
+}
+
+// run executes f as a managed goroutine on behalf of t.
+func (m *Manager) run(t *Task, f func(ctx context.Context)) {
+	m.Go(func() {
+		if m.accounting {
+			defer m.finishAccount(m.startAccount(t, f))
+		}
+		defer close(t.done)
+		defer m.unregister(t)
+		defer t.cancel(nil)
+		run := m.wrapTask(t, f)
+		if m.tracer != nil {
+			m.traceTask(t, taskName(t, f), run)
+			return
+		}
+		run(t.ctx)
+	})
+}
+
+// taskName returns the name of t, or the name of its function f for unnamed
+// tasks.
+func taskName(t *Task, f func(ctx context.Context)) string {
+	if t.name != "" {
+		return t.name
+	}
+	return funcName(f)
+}
+
+// Name returns the name given with WithName, or an empty string.
+func (t *Task) Name() string {
+	return t.name
+}
+
+// Ready returns a channel that is closed once the task has called the
+// package-level Ready function with its context.
+func (t *Task) Ready() <-chan struct{} {
+	return t.ready
+}
+
+// Ready marks the task owning ctx as ready, for example once a replacement
+// worker has finished connecting and can take over from its predecessor.
+// Calling it more than once, or with a context that does not belong to a
+// task, has no effect.
+//
+// Example:
+//
+//	manager.CtxGo(func(ctx context.Context) {
+//		conn := connect(ctx)
+//		graceful.Ready(ctx)
+//		consume(ctx, conn)
+//	})
+func Ready(ctx context.Context) {
+	if t, ok := ctx.Value(taskKey{}).(*Task); ok {
+		t.readyOnce.Do(func() { close(t.ready) })
+	}
+}
+
+// Context returns the context passed to the task function.
+func (t *Task) Context() context.Context {
+	return t.ctx
+}
+
+// Cancel cancels the task's context with the given cause, which can be
+// retrieved inside the task with context.Cause. A nil cause is reported as
+// context.Canceled. Other tasks are not affected.
+func (t *Task) Cancel(cause error) {
+	t.cancel(cause)
+}
+
+// Done returns a channel that is closed when the task function returns.
+func (t *Task) Done() <-chan struct{} {
+	return t.done
+}
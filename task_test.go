@@ -0,0 +1,119 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTaskCancel 测试取消单个任务不影响其他任务
+func TestTaskCancel(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	cause := errors.New("不再需要")
+	causeCh := make(chan error, 1)
+	task := m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		causeCh <- context.Cause(ctx)
+	})
+	other := m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+
+	task.Cancel(cause)
+
+	select {
+	case got := <-causeCh:
+		if got != cause {
+			t.Errorf("任务取消原因应为%v，实际为%v", cause, got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("任务未收到取消信号")
+	}
+
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Error("任务退出后Done应关闭")
+	}
+	if other.Context().Err() != nil {
+		t.Error("取消单个任务不应影响其他任务")
+	}
+
+	m.Shutdown()
+	if other.Context().Err() == nil {
+		t.Error("关闭时所有任务的上下文都应被取消")
+	}
+}
+
+// TestTaskTimeout 测试任务级超时
+func TestTaskTimeout(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	causeCh := make(chan error, 1)
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		causeCh <- context.Cause(ctx)
+	}, WithTaskTimeout(time.Millisecond*20))
+
+	select {
+	case got := <-causeCh:
+		if got != context.DeadlineExceeded {
+			t.Errorf("任务超时原因应为DeadlineExceeded，实际为%v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("任务未按超时时间取消")
+	}
+
+	if m.Context().Err() != nil {
+		t.Error("任务超时不应取消Manager的上下文")
+	}
+}
+
+// TestGoNamedKeepsOptions 测试GoNamed不会写入调用方选项切片的剩余容量
+func TestGoNamedKeepsOptions(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+
+	opts := make([]TaskOption, 0, 1)
+	a := m.GoNamed("a", func(ctx context.Context) { <-ctx.Done() }, opts...)
+	if opts[:1][0] != nil {
+		t.Error("GoNamed不应修改调用方的选项切片")
+	}
+	b := m.GoNamed("b", func(ctx context.Context) { <-ctx.Done() }, opts...)
+	if a.Name() != "a" || b.Name() != "b" {
+		t.Errorf("任务名称应为a和b，实际为%s和%s", a.Name(), b.Name())
+	}
+}
+
+// TestGoNamedTimeout 测试关闭超时时记录未退出任务的名称
+func TestGoNamedTimeout(t *testing.T) {
+	logger := &recordingLogger{}
+	m := New(WithTimeout(time.Millisecond*50), WithLogger(logger))
+	stuck := make(chan struct{})
+	defer close(stuck)
+
+	task := m.GoNamed("order-consumer", func(ctx context.Context) { <-stuck })
+	if task.Name() != "order-consumer" || m.Task("order-consumer") != task {
+		t.Error("GoNamed应为任务命名并登记")
+	}
+	m.GoNamed("cache-warmer", func(ctx context.Context) {})
+
+	if err := m.Shutdown(); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("超时时应返回ErrTimeout，实际为%v", err)
+	}
+	found := false
+	for _, line := range logger.lines {
+		if strings.Contains(line, "order-consumer") {
+			found = true
+			if strings.Contains(line, "cache-warmer") {
+				t.Errorf("已退出的任务不应出现在日志中: %s", line)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("应记录未退出任务的名称，实际日志为%v", logger.lines)
+	}
+}
@@ -0,0 +1,46 @@
+package graceful
+
+import (
+	"context"
+	"time"
+)
+
+// TelemetryProvider is the subset of the OpenTelemetry SDK provider API used
+// by the manager. *trace.TracerProvider, *metric.MeterProvider and
+// *log.LoggerProvider from go.opentelemetry.io/otel/sdk all satisfy it, so
+// the package does not need to depend on the SDK itself.
+type TelemetryProvider interface {
+	Shutdown(ctx context.Context) error
+}
+
+// WithTelemetryTimeout returns an Option that sets the budget for shutting
+// down the providers registered with ManageTelemetry. It is separate from the
+// flush timeout so that slow flush functions cannot starve telemetry export.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithTelemetryTimeout(time.Second))
+func WithTelemetryTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.telemetryTimeout = timeout
+	}
+}
+
+// ManageTelemetry registers OpenTelemetry SDK providers so that they are shut
+// down as the very last step of the flush phase. Providers are shut down in
+// the order given; pass the tracer provider first so that spans produced by
+// the meter and logger exporters are still recorded.
+//
+// Because the providers outlive every managed goroutine and flush function,
+// spans recorded while the application drains are exported as well.
+//
+// Example:
+//
+//	tp := sdktrace.NewTracerProvider(...)
+//	mp := sdkmetric.NewMeterProvider(...)
+//	manager.ManageTelemetry(tp, mp)
+func (m *Manager) ManageTelemetry(providers ...TelemetryProvider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.telemetry = append(m.telemetry, providers...)
+}
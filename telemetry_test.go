@@ -0,0 +1,42 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	name  string
+	order *[]string
+}
+
+func (p *fakeProvider) Shutdown(ctx context.Context) error {
+	*p.order = append(*p.order, p.name)
+	return nil
+}
+
+// TestManageTelemetry 测试遥测provider在flush阶段最后关闭
+func TestManageTelemetry(t *testing.T) {
+	m := New(WithTimeout(time.Second), WithTelemetryTimeout(time.Second))
+
+	var order []string
+	m.ManageTelemetry(&fakeProvider{"tracer", &order}, &fakeProvider{"meter", &order})
+	m.OnFlush(func(ctx context.Context) error {
+		order = append(order, "flush")
+		return nil
+	})
+
+	m.Shutdown()
+
+	expected := []string{"flush", "tracer", "meter"}
+	if len(order) != len(expected) {
+		t.Fatalf("关闭顺序应为%v，实际为%v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("关闭顺序应为%v，实际为%v", expected, order)
+			break
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package graceful
+
+import "os"
+
+// TempDir creates a new temporary directory, as os.MkdirTemp does in the
+// default temporary directory, and registers it for removal during shutdown.
+//
+// Example:
+//
+//	dir, err := manager.TempDir("uploads-*")
+func (m *Manager) TempDir(pattern string) (string, error) {
+	dir, err := os.MkdirTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	m.TrackTempFile(dir)
+	return dir, nil
+}
+
+// TrackTempFile registers a file or directory to be removed, recursively,
+// during shutdown. Tracked paths are removed after the shutdown hooks have
+// run, including when the shutdown timeout has expired, so services that
+// restart often do not accumulate temporary data.
+//
+// Example:
+//
+//	f, _ := os.CreateTemp("", "report-*.csv")
+//	manager.TrackTempFile(f.Name())
+func (m *Manager) TrackTempFile(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tempPaths = append(m.tempPaths, path)
+}
+
+// removeTempPaths removes every tracked path and forgets them.
+func (m *Manager) removeTempPaths() {
+	m.mu.Lock()
+	paths := m.tempPaths
+	m.tempPaths = nil
+	m.mu.Unlock()
+
+	for _, p := range paths {
+		_ = os.RemoveAll(p)
+	}
+}
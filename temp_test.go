@@ -0,0 +1,41 @@
+package graceful
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestTempPaths 测试临时目录和文件在关闭时被删除
+func TestTempPaths(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond * 20))
+
+	dir, err := m.TempDir("graceful-test-*")
+	if err != nil {
+		t.Fatalf("创建临时目录失败: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "data"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.CreateTemp("", "graceful-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	m.TrackTempFile(f.Name())
+
+	// 模拟超时的goroutine，临时文件仍应被删除
+	m.Go(func() {
+		time.Sleep(time.Millisecond * 200)
+	})
+
+	m.Shutdown()
+
+	for _, p := range []string{dir, f.Name()} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("关闭后%s应被删除", p)
+		}
+	}
+}
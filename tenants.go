@@ -0,0 +1,223 @@
+package graceful
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tenants scopes work to tenants and drains them progressively at shutdown,
+// in batches, so that shared downstream systems see the load of one batch of
+// tenants at a time instead of every tenant's work being canceled at once.
+type Tenants struct {
+	m        *Manager
+	priority func(tenant string) int // Drain order; lower drains first
+	batch    int                     // Tenants drained together
+	pause    time.Duration           // Longest wait for a batch before the next starts
+
+	mu       sync.Mutex
+	tenants  map[string]*tenant
+	draining bool
+}
+
+// tenant is the work of one tenant. It is dropped once its last task has
+// returned and no context returned by Context is reachable.
+type tenant struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	tasks   map[*Task]struct{}
+	holders int // Contexts returned by Context that are still reachable
+}
+
+// tenantContext is the context returned by Tenants.Context. A finalizer
+// releases the tenant's hold once the caller no longer refers to it.
+type tenantContext struct {
+	context.Context
+}
+
+// TenantOption configures a Tenants created with Manager.Tenants.
+type TenantOption func(*Tenants)
+
+// WithTenantPriority returns a TenantOption that sets the drain order:
+// tenants for which priority returns lower values are drained first, and
+// tenants with equal values in name order.
+//
+// Example:
+//
+//	// Drain free-tier tenants before paying ones
+//	graceful.WithTenantPriority(func(tenant string) int { return plans[tenant].Tier })
+func WithTenantPriority(priority func(tenant string) int) TenantOption {
+	return func(ts *Tenants) {
+		ts.priority = priority
+	}
+}
+
+// WithTenantBatch returns a TenantOption that drains size tenants at a time.
+// The default is one.
+func WithTenantBatch(size int) TenantOption {
+	return func(ts *Tenants) {
+		ts.batch = size
+	}
+}
+
+// WithTenantPause returns a TenantOption that limits how long a batch may
+// take to return before the next batch is canceled. Without it, each batch
+// may wait until the shutdown timeout.
+func WithTenantPause(d time.Duration) TenantOption {
+	return func(ts *Tenants) {
+		ts.pause = d
+	}
+}
+
+// Tenants returns a Tenants whose tenants are drained when shutdown begins,
+// before managed goroutines are canceled, batch by batch: the contexts and
+// tasks of a batch are canceled with ErrDraining, and the next batch starts
+// once they have returned. Tenants still running when the shutdown timeout
+// expires are canceled with the other goroutines.
+//
+// Example:
+//
+//	tenants := manager.Tenants(graceful.WithTenantBatch(10), graceful.WithTenantPause(time.Second))
+//	for _, id := range tenantIDs {
+//		tenants.Go(id, func(ctx context.Context) { runTenant(ctx, id) })
+//	}
+func (m *Manager) Tenants(opts ...TenantOption) *Tenants {
+	ts := &Tenants{m: m, batch: 1, tenants: make(map[string]*tenant)}
+	for _, opt := range opts {
+		opt(ts)
+	}
+	if ts.batch < 1 {
+		ts.batch = 1
+	}
+	m.OnDrain(ts.drain)
+	return ts
+}
+
+// Context returns the context of tenant, which is canceled with ErrDraining
+// when the tenant is drained, and with the manager's context otherwise.
+// The tenant is kept while the returned context is reachable.
+func (ts *Tenants) Context(name string) context.Context {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	tn := ts.get(name)
+	tn.holders++
+	ctx := &tenantContext{Context: tn.ctx}
+	runtime.SetFinalizer(ctx, func(*tenantContext) {
+		ts.mu.Lock()
+		defer ts.mu.Unlock()
+		tn.holders--
+		ts.drop(name, tn)
+	})
+	return ctx
+}
+
+// Go starts f as a managed task belonging to tenant, like CtxGo. Its
+// context is canceled with ErrDraining when the tenant is drained.
+func (ts *Tenants) Go(name string, f func(ctx context.Context), opts ...TaskOption) *Task {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	tn := ts.get(name)
+	// Hold the task back until it is recorded, so it can remove itself
+	var t *Task
+	started := make(chan struct{})
+	t = ts.m.CtxGo(func(ctx context.Context) {
+		<-started
+		defer func() {
+			ts.mu.Lock()
+			delete(tn.tasks, t)
+			ts.drop(name, tn)
+			ts.mu.Unlock()
+		}()
+		f(ctx)
+	}, opts...)
+	tn.tasks[t] = struct{}{}
+	if ts.draining || tn.ctx.Err() != nil {
+		t.Cancel(ErrDraining)
+	}
+	close(started)
+	return t
+}
+
+// get returns the tenant called name, creating it if needed. It must be
+// called with mu held.
+func (ts *Tenants) get(name string) *tenant {
+	tn, ok := ts.tenants[name]
+	if !ok {
+		ctx, cancel := context.WithCancelCause(ts.m.Context())
+		tn = &tenant{ctx: ctx, cancel: cancel, tasks: make(map[*Task]struct{})}
+		ts.tenants[name] = tn
+	}
+	return tn
+}
+
+// drop removes tn, called name, once it has no tasks and no holders, so that
+// tenants that come and go do not accumulate. It must be called with mu held.
+func (ts *Tenants) drop(name string, tn *tenant) {
+	if len(tn.tasks) > 0 || tn.holders > 0 || ts.tenants[name] != tn {
+		return
+	}
+	delete(ts.tenants, name)
+	tn.cancel(context.Canceled)
+}
+
+// drain cancels the tenants batch by batch.
+func (ts *Tenants) drain(ctx context.Context) error {
+	ts.mu.Lock()
+	ts.draining = true
+	names := make([]string, 0, len(ts.tenants))
+	for name := range ts.tenants {
+		names = append(names, name)
+	}
+	ts.mu.Unlock()
+
+	sort.Slice(names, func(i, j int) bool {
+		if ts.priority != nil {
+			if pi, pj := ts.priority(names[i]), ts.priority(names[j]); pi != pj {
+				return pi < pj
+			}
+		}
+		return names[i] < names[j]
+	})
+
+	for start := 0; start < len(names); start += ts.batch {
+		end := start + ts.batch
+		if end > len(names) {
+			end = len(names)
+		}
+
+		var tasks []*Task
+		ts.mu.Lock()
+		for _, name := range names[start:end] {
+			tn, ok := ts.tenants[name]
+			if !ok {
+				// Dropped since the names were collected
+				continue
+			}
+			tn.cancel(ErrDraining)
+			for t := range tn.tasks {
+				t.Cancel(ErrDraining)
+				tasks = append(tasks, t)
+			}
+		}
+		ts.mu.Unlock()
+
+		bctx, cancel := ctx, context.CancelFunc(func() {})
+		if ts.pause > 0 {
+			bctx, cancel = context.WithTimeout(ctx, ts.pause)
+		}
+		for _, t := range tasks {
+			if !waitTask(bctx, t, 0) {
+				break
+			}
+		}
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
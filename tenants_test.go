@@ -0,0 +1,95 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestTenantsDrainOrder 测试租户按优先级分批排空
+func TestTenantsDrainOrder(t *testing.T) {
+	m := New(WithTimeout(time.Second * 5))
+	priorities := map[string]int{"free-a": 0, "free-b": 0, "paid": 1}
+	tenants := m.Tenants(WithTenantBatch(2), WithTenantPriority(func(tenant string) int {
+		return priorities[tenant]
+	}))
+
+	var mu sync.Mutex
+	var order []string
+	for _, name := range []string{"paid", "free-b", "free-a"} {
+		name := name
+		tenants.Go(name, func(ctx context.Context) {
+			<-ctx.Done()
+			if !errors.Is(context.Cause(ctx), ErrDraining) {
+				t.Errorf("租户%s应以ErrDraining取消，实际为%v", name, context.Cause(ctx))
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			time.Sleep(time.Millisecond * 20)
+		})
+	}
+	ctx := tenants.Context("paid")
+	m.Shutdown()
+
+	if len(order) != 3 || order[2] != "paid" {
+		t.Errorf("付费租户应在免费租户之后排空，实际顺序为%v", order)
+	}
+	if !errors.Is(context.Cause(ctx), ErrDraining) {
+		t.Errorf("租户上下文应以ErrDraining取消，实际为%v", context.Cause(ctx))
+	}
+}
+
+// TestTenantsPause 测试每批等待时间受限
+func TestTenantsPause(t *testing.T) {
+	m := New(WithTimeout(time.Second * 5))
+	tenants := m.Tenants(WithTenantPause(time.Millisecond * 20))
+
+	release := make(chan struct{})
+	tenants.Go("stuck", func(ctx context.Context) { <-release })
+	var secondAt time.Time
+	tenants.Go("z", func(ctx context.Context) {
+		<-ctx.Done()
+		secondAt = time.Now()
+	})
+
+	start := time.Now()
+	go func() {
+		time.Sleep(time.Millisecond * 200)
+		close(release)
+	}()
+	m.Shutdown()
+	if d := secondAt.Sub(start); d > time.Millisecond*150 {
+		t.Errorf("下一批应在暂停时间后开始排空，实际为%v", d)
+	}
+}
+
+// TestTenantsDropped 测试租户的最后一个任务返回且上下文不再被引用后租户被移除
+func TestTenantsDropped(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	defer m.Shutdown()
+	tenants := m.Tenants()
+	count := func() int {
+		tenants.mu.Lock()
+		defer tenants.mu.Unlock()
+		return len(tenants.tenants)
+	}
+
+	<-tenants.Go("a", func(ctx context.Context) {}).Done()
+	waitFor(t, func() bool { return count() == 0 }, "最后一个任务返回后租户应被移除")
+
+	ctx := tenants.Context("b")
+	<-tenants.Go("b", func(ctx context.Context) {}).Done()
+	time.Sleep(time.Millisecond * 20)
+	if count() != 1 || ctx.Err() != nil {
+		t.Fatalf("上下文仍被引用时租户应保留，实际剩余%d个", count())
+	}
+	ctx = nil
+	waitFor(t, func() bool {
+		runtime.GC()
+		return count() == 0
+	}, "上下文不再被引用后租户应被移除")
+}
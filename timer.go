@@ -0,0 +1,137 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Timer is a pending call scheduled with AfterFunc.
+type Timer struct {
+	m       *Manager
+	f       func(ctx context.Context)
+	fire    bool        // Whether to run f immediately at shutdown
+	timer   *time.Timer // Underlying timer; nil once shutdown has begun
+	mu      sync.Mutex
+	claimed bool // Set once f has been started or the timer stopped
+}
+
+// TimerOption defines a function type for configuring timers created with
+// AfterFunc.
+type TimerOption func(*Timer)
+
+// WithFireOnShutdown returns a TimerOption that runs the callback immediately
+// when shutdown begins instead of discarding it, for timers that flush or
+// report something that must not be lost.
+//
+// Example:
+//
+//	manager.AfterFunc(time.Minute, flushBatch, graceful.WithFireOnShutdown())
+func WithFireOnShutdown() TimerOption {
+	return func(t *Timer) {
+		t.fire = true
+	}
+}
+
+// AfterFunc waits for the duration to elapse and then runs f as a managed
+// task, like time.AfterFunc. Timers still pending when shutdown begins are
+// stopped, so their callbacks never run against torn-down dependencies;
+// with WithFireOnShutdown they run right away instead, and shutdown waits for
+// them like for any managed goroutine.
+//
+// Example:
+//
+//	t := manager.AfterFunc(30*time.Second, func(ctx context.Context) {
+//		expireSession(ctx, id)
+//	})
+//	defer t.Stop()
+func (m *Manager) AfterFunc(d time.Duration, f func(ctx context.Context), opts ...TimerOption) *Timer {
+	t := &Timer{m: m, f: f}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	m.timersMu.Lock()
+	stopped := m.timersStopped
+	if !stopped {
+		if m.timers == nil {
+			m.timers = make(map[*Timer]struct{})
+		}
+		m.timers[t] = struct{}{}
+		t.timer = time.AfterFunc(d, t.run)
+	}
+	m.timersMu.Unlock()
+
+	if stopped {
+		// Shutdown has already begun
+		t.shutdown()
+	}
+	return t
+}
+
+// Stop prevents the callback from running. It returns false if the callback
+// has already been started or the timer was stopped before.
+func (t *Timer) Stop() bool {
+	if !t.claim() {
+		return false
+	}
+	t.m.timersMu.Lock()
+	delete(t.m.timers, t)
+	t.m.timersMu.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	return true
+}
+
+// claim reports whether the caller is the first to start or stop the timer.
+func (t *Timer) claim() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.claimed {
+		return false
+	}
+	t.claimed = true
+	return true
+}
+
+// run starts the callback when the timer expires.
+func (t *Timer) run() {
+	if !t.claim() {
+		return
+	}
+	t.m.timersMu.Lock()
+	delete(t.m.timers, t)
+	t.m.timersMu.Unlock()
+	t.m.CtxGo(t.f)
+}
+
+// shutdown stops the timer, running the callback if WithFireOnShutdown was
+// given.
+func (t *Timer) shutdown() {
+	if !t.claim() {
+		return
+	}
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	if t.fire {
+		t.m.CtxGo(t.f)
+	}
+}
+
+// stopTimers stops the pending timers and rejects new ones.
+func (m *Manager) stopTimers() {
+	m.timersMu.Lock()
+	m.timersStopped = true
+	timers := make([]*Timer, 0, len(m.timers))
+	for t := range m.timers {
+		timers = append(timers, t)
+	}
+	m.timers = nil
+	m.timersMu.Unlock()
+
+	for _, t := range timers {
+		t.shutdown()
+	}
+}
@@ -0,0 +1,54 @@
+package graceful
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestAfterFunc 测试定时回调作为受管理任务运行
+func TestAfterFunc(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	fired := make(chan struct{})
+	m.AfterFunc(time.Millisecond*10, func(ctx context.Context) { close(fired) })
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("定时回调应运行")
+	}
+
+	stopped := m.AfterFunc(time.Millisecond*10, func(ctx context.Context) { t.Error("已停止的定时器不应运行") })
+	if !stopped.Stop() {
+		t.Error("首次停止应返回true")
+	}
+	if stopped.Stop() {
+		t.Error("再次停止应返回false")
+	}
+	time.Sleep(time.Millisecond * 30)
+	m.Shutdown()
+}
+
+// TestAfterFuncShutdown 测试关闭时取消或立即触发待定的定时器
+func TestAfterFuncShutdown(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var discarded, flushed atomic.Bool
+	m.AfterFunc(time.Hour, func(ctx context.Context) { discarded.Store(true) })
+	m.AfterFunc(time.Hour, func(ctx context.Context) { flushed.Store(true) }, WithFireOnShutdown())
+
+	m.Shutdown()
+	if discarded.Load() {
+		t.Error("关闭时待定的定时器不应运行")
+	}
+	if !flushed.Load() {
+		t.Error("WithFireOnShutdown的定时器应在关闭时运行并被等待")
+	}
+
+	late := m.AfterFunc(time.Millisecond, func(ctx context.Context) { t.Error("关闭后创建的定时器不应运行") })
+	time.Sleep(time.Millisecond * 20)
+	if late.Stop() {
+		t.Error("关闭后创建的定时器应已停止")
+	}
+}
@@ -0,0 +1,134 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Tracer starts the spans recorded by the manager when tracing is enabled with
+// WithTracer. The package does not depend on OpenTelemetry; an adapter over a
+// trace.Tracer takes a few lines.
+//
+// Example:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) StartSpan(ctx context.Context, name string) (context.Context, graceful.Span) {
+//		ctx, span := t.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) Finish(o graceful.SpanOutcome) {
+//		if o.Shutdown != nil {
+//			s.AddLink(trace.LinkFromContext(o.Shutdown))
+//		}
+//		if o.Err != nil {
+//			s.RecordError(o.Err)
+//			s.SetStatus(codes.Error, o.Err.Error())
+//		}
+//		s.End()
+//	}
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	Finish(outcome SpanOutcome)
+}
+
+// SpanOutcome describes how the operation covered by a span ended.
+type SpanOutcome struct {
+	Duration time.Duration // Time between the start and the end of the span
+	Err      error         // Error of the operation, if any
+	Panic    any           // Value the task panicked with, if it did
+
+	// Shutdown is the context of the shutdown span when the task ended
+	// because shutdown canceled it, for linking the two spans; nil otherwise.
+	Shutdown context.Context
+}
+
+// WithTracer returns an Option that records a span for each task run started
+// with CtxGo, named "graceful.task <name>", for each in-process restart
+// ("graceful.restart") and for the shutdown itself ("graceful.shutdown").
+// The span context is passed to the task function, so spans it starts become
+// children of the task span. Task spans record panics without recovering from
+// them and link to the shutdown span when shutdown canceled the task.
+//
+// The shutdown span ends before the flush phase, so telemetry providers
+// registered with ManageTelemetry still export it.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithTracer(otelTracer{otel.Tracer("app")}))
+func WithTracer(tracer Tracer) Option {
+	return func(m *Manager) {
+		m.tracer = tracer
+	}
+}
+
+// traceTask runs f with a span named after the task, started from the task's
+// context.
+func (m *Manager) traceTask(t *Task, name string, f func(ctx context.Context)) {
+	ctx := t.ctx
+	spanCtx, span := m.tracer.StartSpan(ctx, "graceful.task "+name)
+	start := time.Now()
+	defer func() {
+		outcome := SpanOutcome{Duration: time.Since(start)}
+		if r := recover(); r != nil {
+			outcome.Panic = r
+			outcome.Err = fmt.Errorf("graceful: task panicked: %v", r)
+			span.Finish(outcome)
+			panic(r)
+		}
+		if ctx.Err() != nil && m.draining.Load() {
+			outcome.Err = context.Cause(ctx)
+			outcome.Shutdown = m.shutdownSpanContext()
+		}
+		span.Finish(outcome)
+	}()
+	f(spanCtx)
+}
+
+// startShutdownSpan starts the shutdown span, if tracing is enabled, and
+// returns the function that ends it.
+func (m *Manager) startShutdownSpan() (end func(timedOut bool)) {
+	if m.tracer == nil {
+		return func(bool) {}
+	}
+	ctx, span := m.tracer.StartSpan(context.Background(), "graceful.shutdown")
+	m.mu.Lock()
+	m.shutdownSpan = ctx
+	m.mu.Unlock()
+
+	start := time.Now()
+	return func(timedOut bool) {
+		outcome := SpanOutcome{Duration: time.Since(start)}
+		if timedOut {
+			outcome.Err = context.DeadlineExceeded
+		}
+		span.Finish(outcome)
+	}
+}
+
+// shutdownSpanContext returns the context of the shutdown span, or nil.
+func (m *Manager) shutdownSpanContext() context.Context {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.shutdownSpan
+}
+
+// traceRestart runs restart within a restart span, if tracing is enabled.
+func (m *Manager) traceRestart(restart func() error) error {
+	if m.tracer == nil {
+		return restart()
+	}
+	_, span := m.tracer.StartSpan(context.Background(), "graceful.restart")
+	start := time.Now()
+	err := restart()
+	span.Finish(SpanOutcome{Duration: time.Since(start), Err: err})
+	return err
+}
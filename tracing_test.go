@@ -0,0 +1,86 @@
+package graceful
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingTracer 记录结束的span
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans map[string]SpanOutcome
+}
+
+type spanKey struct{}
+
+func (r *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	return context.WithValue(ctx, spanKey{}, name), &recordingSpan{tracer: r, name: name}
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	name   string
+}
+
+func (s *recordingSpan) Finish(o SpanOutcome) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.spans[s.name] = o
+}
+
+// TestTracer 测试任务和关闭的span
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{spans: make(map[string]SpanOutcome)}
+	m := New(WithTimeout(time.Second), WithTracer(tracer))
+
+	var spanName any
+	m.CtxGo(func(ctx context.Context) {
+		spanName = ctx.Value(spanKey{})
+		<-ctx.Done()
+	}, WithName("worker"))
+	m.CtxGo(func(ctx context.Context) {}, WithName("quick"))
+	time.Sleep(time.Millisecond * 20)
+	m.Shutdown()
+
+	if spanName != "graceful.task worker" {
+		t.Errorf("任务函数应收到span上下文，实际为%v", spanName)
+	}
+	worker, ok := tracer.spans["graceful.task worker"]
+	if !ok {
+		t.Fatal("应记录任务span")
+	}
+	if worker.Shutdown == nil || worker.Shutdown.Value(spanKey{}) != "graceful.shutdown" {
+		t.Error("被关闭取消的任务应关联关闭span")
+	}
+	if worker.Err == nil {
+		t.Error("被关闭取消的任务应记录取消原因")
+	}
+	if quick := tracer.spans["graceful.task quick"]; quick.Shutdown != nil || quick.Err != nil {
+		t.Error("自行结束的任务不应关联关闭span")
+	}
+	if _, ok := tracer.spans["graceful.shutdown"]; !ok {
+		t.Error("应记录关闭span")
+	}
+}
+
+// TestTracerPanic 测试任务panic被记录后继续传播
+func TestTracerPanic(t *testing.T) {
+	tracer := &recordingTracer{spans: make(map[string]SpanOutcome)}
+	m := New(WithTracer(tracer))
+
+	recovered := make(chan any, 1)
+	task := m.newTask(taskConfig{name: "panics"})
+	func() {
+		defer func() { recovered <- recover() }()
+		m.traceTask(task, "panics", func(ctx context.Context) { panic("boom") })
+	}()
+
+	if r := <-recovered; r != "boom" {
+		t.Errorf("panic应继续传播，实际为%v", r)
+	}
+	if o := tracer.spans["graceful.task panics"]; o.Panic != "boom" || o.Err == nil {
+		t.Errorf("span应记录panic，实际为%+v", o)
+	}
+}
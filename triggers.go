@@ -0,0 +1,193 @@
+package graceful
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// Trigger is a source of shutdown requests. Watch blocks until ctx is done,
+// calling fire whenever the source asks the application to shut down. The
+// signal passed to fire is reported like a received OS signal: it selects
+// the exit code used by Run, restarts the application if it is one of the
+// restart signals, and is recorded in the signal history. Sources that are
+// not OS signals pass a TriggerSignal.
+//
+// OS signals are a trigger as well: without WithTriggers, the manager runs
+// only DefaultTrigger, which watches the signals set with WithSignals.
+type Trigger interface {
+	Watch(ctx context.Context, fire func(sig os.Signal))
+}
+
+// TriggerFunc adapts a function to the Trigger interface.
+type TriggerFunc func(ctx context.Context, fire func(sig os.Signal))
+
+// Watch calls f(ctx, fire).
+func (f TriggerFunc) Watch(ctx context.Context, fire func(sig os.Signal)) {
+	f(ctx, fire)
+}
+
+// TriggerSignal is the os.Signal reported for shutdowns started by triggers
+// that are not OS signals. It has no signal number, so Run exits with the
+// Clean code unless ExitCodes.Signals maps it to another one.
+type TriggerSignal string
+
+// String returns the name of the trigger.
+func (s TriggerSignal) String() string { return string(s) }
+
+// Signal implements os.Signal.
+func (s TriggerSignal) Signal() {}
+
+// WithTriggers returns an Option that sets the shutdown sources watched by
+// Wait and Run, replacing DefaultTrigger. Include DefaultTrigger to keep
+// handling the signals set with WithSignals; without it, the manager does
+// not react to OS signals at all, which suits embedding it in an
+// application that owns signal handling.
+//
+// Example:
+//
+//	manager := graceful.New(graceful.WithTriggers(
+//		graceful.DefaultTrigger(),
+//		graceful.FileTrigger("/run/app/shutdown", time.Second),
+//		graceful.ParentDeathTrigger(time.Second),
+//	))
+func WithTriggers(triggers ...Trigger) Option {
+	return func(m *Manager) {
+		m.triggers = append(m.triggers, triggers...)
+	}
+}
+
+// DefaultTrigger returns the trigger a manager runs when WithTriggers is not
+// given. It watches the OS signals the manager monitors: those set with
+// WithSignals, plus the restart signals in Run and the forwarded signals in
+// RunInit, except the signals taken over by HandleSignal. They are counted in
+// SignalStats and coalesced as set with WithSignalCoalescing. Outside a
+// manager its Watch does nothing.
+func DefaultTrigger() Trigger {
+	return defaultTrigger{}
+}
+
+// defaultTrigger stands for the manager's own signal trigger, which
+// notifySignals creates with the signals of the call.
+type defaultTrigger struct{}
+
+// Watch does nothing; managers replace the default trigger with their
+// signal handling.
+func (defaultTrigger) Watch(ctx context.Context, fire func(sig os.Signal)) {}
+
+// SignalTrigger returns a trigger that fires for each of the given OS
+// signals. Unlike the signals watched by DefaultTrigger, they are not counted
+// in SignalStats or coalesced, and HandleSignal does not take them over.
+func SignalTrigger(signals ...os.Signal) Trigger {
+	return TriggerFunc(func(ctx context.Context, fire func(sig os.Signal)) {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, signals...)
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case sig := <-sigCh:
+				fire(sig)
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// ContextTrigger returns a trigger that fires with TriggerSignal("context")
+// when ctx is done, for embedding the manager in a larger application that
+// owns the lifecycle.
+func ContextTrigger(ctx context.Context) Trigger {
+	return TriggerFunc(func(watch context.Context, fire func(sig os.Signal)) {
+		select {
+		case <-ctx.Done():
+			fire(TriggerSignal("context"))
+		case <-watch.Done():
+		}
+	})
+}
+
+// FileTrigger returns a trigger that fires with TriggerSignal("file") once
+// the file at path exists, checking every interval. It suits environments
+// where sending signals is awkward, such as `touch /run/app/shutdown`.
+func FileTrigger(path string, interval time.Duration) Trigger {
+	return pollTrigger(interval, TriggerSignal("file"), func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	})
+}
+
+// ParentDeathTrigger returns a trigger that fires with
+// TriggerSignal("parent-death") when the parent process exits, checking every
+// interval, so that helpers spawned by a supervisor do not outlive it.
+func ParentDeathTrigger(interval time.Duration) Trigger {
+	parent := os.Getppid()
+	return pollTrigger(interval, TriggerSignal("parent-death"), func() bool {
+		return os.Getppid() != parent
+	})
+}
+
+// pollTrigger fires sig once cond reports true, checking every interval.
+func pollTrigger(interval time.Duration, sig os.Signal, cond func() bool) Trigger {
+	return TriggerFunc(func(ctx context.Context, fire func(sig os.Signal)) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if cond() {
+				fire(sig)
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+}
+
+// HTTPTrigger is a trigger that fires with TriggerSignal("http") when it
+// receives a POST request. Mount it on an admin-only listener.
+type HTTPTrigger struct {
+	requests chan struct{}
+}
+
+// NewHTTPTrigger creates an HTTP trigger.
+//
+// Example:
+//
+//	trigger := graceful.NewHTTPTrigger()
+//	manager := graceful.New(graceful.WithTriggers(trigger))
+//	admin.Handle("/admin/shutdown", trigger)
+func NewHTTPTrigger() *HTTPTrigger {
+	return &HTTPTrigger{requests: make(chan struct{}, 1)}
+}
+
+// ServeHTTP requests a shutdown on POST and answers 202 Accepted.
+func (t *HTTPTrigger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	select {
+	case t.requests <- struct{}{}:
+	default:
+		// A shutdown request is already pending
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Watch implements Trigger.
+func (t *HTTPTrigger) Watch(ctx context.Context, fire func(sig os.Signal)) {
+	for {
+		select {
+		case <-t.requests:
+			fire(TriggerSignal("http"))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package graceful
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitReturns 在后台调用Wait并返回一个在Wait返回时关闭的通道
+func waitReturns(m *Manager) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		m.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// TestContextTrigger 测试上下文结束时触发关闭
+func TestContextTrigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := New(WithTimeout(time.Second), WithTriggers(ContextTrigger(ctx)))
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+
+	done := waitReturns(m)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("上下文结束后Wait应返回")
+	}
+}
+
+// TestFileTrigger 测试文件出现时触发关闭
+func TestFileTrigger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shutdown")
+	m := New(WithTimeout(time.Second), WithTriggers(FileTrigger(path, time.Millisecond*10)))
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+
+	done := waitReturns(m)
+	select {
+	case <-done:
+		t.Fatal("文件出现前Wait不应返回")
+	case <-time.After(time.Millisecond * 50):
+	}
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatalf("创建文件失败: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("文件出现后Wait应返回")
+	}
+}
+
+// TestHTTPTrigger 测试HTTP请求触发关闭并按映射的退出码退出
+func TestHTTPTrigger(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	trigger := NewHTTPTrigger()
+	codes := DefaultExitCodes()
+	codes.Signals = map[os.Signal]int{TriggerSignal("http"): 7}
+	m := New(WithTimeout(time.Second), WithTriggers(trigger), WithExitCodes(codes))
+
+	m.Run(func(ctx context.Context) error {
+		go func() {
+			time.Sleep(time.Millisecond * 20)
+			rec := httptest.NewRecorder()
+			trigger.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+		}()
+		return nil
+	})
+	if code != 7 {
+		t.Errorf("退出码应为7，实际为%d", code)
+	}
+}
@@ -0,0 +1,62 @@
+package graceful
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// ErrSocketInUse is returned by ListenUnix when another process is accepting
+// connections on the socket file.
+var ErrSocketInUse = errors.New("graceful: unix socket is in use")
+
+// ListenUnix listens on the Unix domain socket at path like Listen, taking
+// care of the socket file. A file left behind by a process that did not shut
+// down cleanly is removed first, after checking that nothing accepts
+// connections on it; a live socket makes ListenUnix fail with ErrSocketInUse
+// instead. During shutdown the socket file is unlinked together with the
+// tracked temporary files, even if the shutdown timeout expires, so the next
+// start does not trip over it.
+//
+// Example:
+//
+//	ln, err := manager.ListenUnix("/run/app/api.sock")
+//	if err != nil {
+//		return err
+//	}
+//	go srv.Serve(ln)
+func (m *Manager) ListenUnix(path string) (net.Listener, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	ln, err := m.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	m.TrackTempFile(path)
+	return ln, nil
+}
+
+// removeStaleSocket removes the socket file at path unless a process is
+// accepting connections on it.
+func removeStaleSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("graceful: %s exists and is not a socket", path)
+	}
+
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%w: %s", ErrSocketInUse, path)
+	}
+	return os.Remove(path)
+}
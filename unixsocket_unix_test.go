@@ -0,0 +1,55 @@
+//go:build unix
+
+package graceful
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestListenUnix 测试清理残留的套接字文件并在关闭时删除
+func TestListenUnix(t *testing.T) {
+	dir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "api.sock")
+
+	// 制造一个残留的套接字文件
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	m := New(WithTimeout(time.Second))
+	ln, err := m.ListenUnix(path)
+	if err != nil {
+		t.Fatalf("残留的套接字文件应被清理，实际错误: %v", err)
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	other := New()
+	if _, err := other.ListenUnix(path); !errors.Is(err, ErrSocketInUse) {
+		t.Errorf("正在使用的套接字应返回ErrSocketInUse，实际为%v", err)
+	}
+
+	m.Shutdown()
+	if _, err := os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("关闭后套接字文件应被删除，实际为%v", err)
+	}
+}
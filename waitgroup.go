@@ -0,0 +1,56 @@
+package graceful
+
+import "sync"
+
+// WaitGroup has the Add, Done and Wait methods of sync.WaitGroup, but every
+// goroutine it counts is also tracked by the manager, so shutdown waits for
+// it like for a goroutine started with Go.
+//
+// Use it to migrate code built around a raw sync.WaitGroup by swapping one
+// variable instead of rewriting every go statement:
+//
+//	wg := manager.WaitGroup() // was: var wg sync.WaitGroup
+//	for _, job := range jobs {
+//		wg.Add(1)
+//		go func(job Job) {
+//			defer wg.Done()
+//			job.Run()
+//		}(job)
+//	}
+//	wg.Wait()
+type WaitGroup struct {
+	local   sync.WaitGroup
+	tracked *sync.WaitGroup
+	m       *Manager
+}
+
+// WaitGroup returns a new WaitGroup tracked by the manager.
+func (m *Manager) WaitGroup() *WaitGroup {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &WaitGroup{tracked: m.wg, m: m}
+}
+
+// Add adds delta, which may be negative, to the counter, like
+// sync.WaitGroup.Add.
+func (wg *WaitGroup) Add(delta int) {
+	if delta > 0 {
+		wg.m.mu.Lock()
+		wg.m.started += delta
+		wg.m.mu.Unlock()
+	}
+	wg.tracked.Add(delta)
+	wg.local.Add(delta)
+}
+
+// Done decrements the counter by one.
+func (wg *WaitGroup) Done() {
+	wg.local.Done()
+	wg.tracked.Done()
+}
+
+// Wait blocks until the counter is zero. It only waits for the goroutines
+// counted by this WaitGroup, not for every goroutine of the manager.
+func (wg *WaitGroup) Wait() {
+	wg.local.Wait()
+}
@@ -0,0 +1,38 @@
+package graceful
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWaitGroup 测试WaitGroup的计数同时被管理器跟踪
+func TestWaitGroup(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+	wg := m.WaitGroup()
+
+	var finished atomic.Int32
+	release := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-release
+			finished.Add(1)
+		}()
+	}
+
+	if got := m.Summary().Tasks; got != 3 {
+		t.Errorf("管理器应跟踪3个任务，实际为%d", got)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond * 50)
+		close(release)
+	}()
+	m.Shutdown()
+	if finished.Load() != 3 {
+		t.Errorf("Shutdown应等待WaitGroup计数的goroutine，完成数为%d", finished.Load())
+	}
+	wg.Wait()
+}
@@ -0,0 +1,89 @@
+package graceful
+
+import (
+	"context"
+	"os"
+)
+
+// GoWarmup starts a managed goroutine for startup-only work, such as priming
+// caches or checking schemas, that must finish before the application is
+// ready. Wait and Run keep the readiness gate closed until every warm-up task
+// has returned; if shutdown is requested in the meantime, the tasks' context
+// is canceled like that of any other managed goroutine.
+//
+// If a warm-up task returns an error, the application shuts down without
+// ever becoming ready, and Run exits with the StartupFailure code.
+//
+// Example:
+//
+//	manager.GoWarmup(func(ctx context.Context) error {
+//		return cache.Prime(ctx)
+//	})
+//	manager.Wait()
+func (m *Manager) GoWarmup(f func(ctx context.Context) error) {
+	m.warmups.Add(1)
+	m.CtxGo(func(ctx context.Context) {
+		defer m.warmups.Done()
+		if err := f(ctx); err != nil {
+			m.requestStop(&TaskError{Name: funcName(f), Err: err})
+		}
+	})
+}
+
+// Ready returns a channel that is closed once startup has completed: the
+// startup checks passed and every warm-up task has finished.
+func (m *Manager) Ready() <-chan struct{} {
+	return m.ready
+}
+
+// IsReady reports whether the application should receive traffic: startup
+// has completed and shutdown has not begun. Readiness probes should use it.
+//
+// Example:
+//
+//	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+//		if !manager.IsReady() {
+//			w.WriteHeader(http.StatusServiceUnavailable)
+//		}
+//	})
+func (m *Manager) IsReady() bool {
+	select {
+	case <-m.ready:
+		return !m.draining.Load()
+	default:
+		return false
+	}
+}
+
+// markReady opens the readiness gate.
+func (m *Manager) markReady() {
+	m.readyOnce.Do(func() { close(m.ready) })
+}
+
+// waitWarmups blocks until all warm-up tasks have returned. It returns early
+// with the signal received on sigCh, with the error of a failed warm-up task,
+// or with the error of ctx.
+func (m *Manager) waitWarmups(ctx context.Context, sigCh <-chan os.Signal) (os.Signal, error) {
+	done := make(chan struct{})
+	go func() {
+		m.warmups.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		// A task may have failed just before the last one returned
+		select {
+		case <-m.stopRequested:
+			return nil, m.stopErr
+		default:
+			return nil, nil
+		}
+	case sig := <-sigCh:
+		return sig, nil
+	case <-m.stopRequested:
+		return nil, m.stopErr
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
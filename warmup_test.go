@@ -0,0 +1,86 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestGoWarmup 测试预热任务完成后才进入就绪状态
+func TestGoWarmup(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	release := make(chan struct{})
+	m.GoWarmup(func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	m.CtxGo(func(ctx context.Context) { <-ctx.Done() })
+
+	go m.Wait()
+
+	time.Sleep(time.Millisecond * 30)
+	if m.IsReady() {
+		t.Error("预热任务完成前不应就绪")
+	}
+
+	close(release)
+	select {
+	case <-m.Ready():
+	case <-time.After(time.Second):
+		t.Fatal("预热任务完成后应就绪")
+	}
+	if !m.IsReady() {
+		t.Error("预热任务完成后IsReady应为true")
+	}
+
+	m.Shutdown()
+	if m.IsReady() {
+		t.Error("关闭开始后不应就绪")
+	}
+}
+
+// TestGoWarmupFailure 测试预热任务失败时以启动失败退出
+func TestGoWarmupFailure(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	m := New(WithTimeout(time.Second))
+	m.Run(func(ctx context.Context) error {
+		m.GoWarmup(func(ctx context.Context) error {
+			return errors.New("缓存预热失败")
+		})
+		return nil
+	})
+
+	if code != DefaultExitCodes().StartupFailure {
+		t.Errorf("退出码应为%d，实际为%d", DefaultExitCodes().StartupFailure, code)
+	}
+	select {
+	case <-m.Ready():
+		t.Error("预热失败时不应就绪")
+	default:
+	}
+}
+
+// TestGoWarmupCanceled 测试启动期间关闭会取消预热任务
+func TestGoWarmupCanceled(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	canceled := make(chan struct{})
+	m.GoWarmup(func(ctx context.Context) error {
+		<-ctx.Done()
+		close(canceled)
+		return ctx.Err()
+	})
+
+	m.Shutdown()
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("关闭时应取消预热任务")
+	}
+}
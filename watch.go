@@ -0,0 +1,168 @@
+package graceful
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchOp describes how a watched path changed.
+type WatchOp string
+
+const (
+	// WatchCreate means the path did not exist and now does.
+	WatchCreate WatchOp = "create"
+	// WatchWrite means the file's size or modification time changed.
+	WatchWrite WatchOp = "write"
+	// WatchRemove means the path existed and no longer does.
+	WatchRemove WatchOp = "remove"
+)
+
+// WatchEvent reports a change to one of the paths passed to Watch.
+type WatchEvent struct {
+	Path string
+	Op   WatchOp
+}
+
+// WatchOption defines a function type for configuring Watch.
+type WatchOption func(*watchConfig)
+
+// watchConfig holds the settings applied by WatchOption values.
+type watchConfig struct {
+	interval time.Duration // How often paths are checked
+	debounce time.Duration // Quiet period required before reporting a change
+}
+
+// WithWatchInterval returns a WatchOption that sets how often the watched
+// paths are checked. The default is one second.
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		c.interval = interval
+	}
+}
+
+// WithWatchDebounce returns a WatchOption that sets how long a path must stay
+// unchanged before its change is reported, so that a file being rewritten in
+// several steps produces a single event. The default is 100 milliseconds.
+func WithWatchDebounce(debounce time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		c.debounce = debounce
+	}
+}
+
+// Watch starts a managed goroutine that calls f whenever one of the given paths
+// is created, written or removed, until the manager shuts down. Changes are
+// debounced per path. The watcher is stopped with the other managed
+// goroutines, so it cannot leak.
+//
+// Paths are checked by polling their size and modification time rather than
+// through inotify or kqueue. This keeps the package free of dependencies and
+// also catches the atomic symlink swaps used to update Kubernetes ConfigMap
+// and Secret volumes, which event-based watchers frequently miss.
+//
+// Example:
+//
+//	manager.Watch([]string{"/etc/app/config.yaml"}, func(ctx context.Context, e graceful.WatchEvent) {
+//		reloadConfig(ctx, e.Path)
+//	})
+func (m *Manager) Watch(paths []string, f func(ctx context.Context, event WatchEvent), opts ...WatchOption) *Task {
+	cfg := watchConfig{
+		interval: time.Second,
+		debounce: time.Millisecond * 100,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// Take the baseline now so that changes made right after Watch are seen
+	w := newWatcher(paths)
+	return m.CtxGo(func(ctx context.Context) {
+		ticker := time.NewTicker(cfg.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, e := range w.poll(now, cfg.debounce) {
+					f(ctx, e)
+				}
+			}
+		}
+	})
+}
+
+// fileState is the part of a file's metadata compared between polls.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// watchedPath tracks a single path between polls.
+type watchedPath struct {
+	path      string
+	reported  fileState // State at the last reported event
+	seen      fileState // State at the last poll
+	changedAt time.Time // When seen last changed
+	pending   bool      // seen differs from reported
+}
+
+// watcher holds the state of all watched paths.
+type watcher struct {
+	paths []*watchedPath
+}
+
+// newWatcher records the current state of paths as the baseline.
+func newWatcher(paths []string) *watcher {
+	w := &watcher{}
+	for _, p := range paths {
+		state := statFile(p)
+		w.paths = append(w.paths, &watchedPath{path: p, reported: state, seen: state})
+	}
+	return w
+}
+
+// poll checks every path and returns the changes that have been stable for at
+// least the debounce period.
+func (w *watcher) poll(now time.Time, debounce time.Duration) []WatchEvent {
+	var events []WatchEvent
+	for _, p := range w.paths {
+		state := statFile(p.path)
+		if state != p.seen {
+			p.seen = state
+			p.changedAt = now
+			p.pending = true
+		}
+		if !p.pending || now.Sub(p.changedAt) < debounce {
+			continue
+		}
+
+		p.pending = false
+		if state == p.reported {
+			// Changed and changed back within the debounce period
+			continue
+		}
+
+		op := WatchWrite
+		switch {
+		case !p.reported.exists:
+			op = WatchCreate
+		case !state.exists:
+			op = WatchRemove
+		}
+		p.reported = state
+		events = append(events, WatchEvent{Path: p.path, Op: op})
+	}
+	return events
+}
+
+// statFile returns the current state of path, following symlinks.
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
@@ -0,0 +1,78 @@
+package graceful
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWatcherPoll 测试轮询检测文件创建、修改、删除并去抖
+func TestWatcherPoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	w := newWatcher([]string{path})
+	now := time.Now()
+
+	if err := os.WriteFile(path, []byte("a"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if events := w.poll(now, time.Second); len(events) != 0 {
+		t.Errorf("去抖期间不应报告事件，实际为%v", events)
+	}
+	events := w.poll(now.Add(time.Second), time.Second)
+	if len(events) != 1 || events[0].Op != WatchCreate {
+		t.Fatalf("应报告一次创建事件，实际为%v", events)
+	}
+
+	if err := os.WriteFile(path, []byte("abc"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	events = w.poll(now.Add(time.Second*2), 0)
+	if len(events) != 1 || events[0].Op != WatchWrite {
+		t.Fatalf("应报告一次修改事件，实际为%v", events)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	events = w.poll(now.Add(time.Second*3), 0)
+	if len(events) != 1 || events[0].Op != WatchRemove {
+		t.Fatalf("应报告一次删除事件，实际为%v", events)
+	}
+
+	if events := w.poll(now.Add(time.Second*4), 0); len(events) != 0 {
+		t.Errorf("没有变化时不应报告事件，实际为%v", events)
+	}
+}
+
+// TestWatch 测试文件监听任务在关闭时退出
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	m := New(WithTimeout(time.Second))
+
+	events := make(chan WatchEvent, 1)
+	task := m.Watch([]string{path}, func(ctx context.Context, e WatchEvent) {
+		events <- e
+	}, WithWatchInterval(time.Millisecond*10), WithWatchDebounce(time.Millisecond*20))
+
+	if err := os.WriteFile(path, []byte("cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-events:
+		if e.Path != path || e.Op != WatchCreate {
+			t.Errorf("应报告%s的创建事件，实际为%v", path, e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("未报告文件变化")
+	}
+
+	m.Shutdown()
+	select {
+	case <-task.Done():
+	default:
+		t.Error("关闭后监听任务应已退出")
+	}
+}
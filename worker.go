@@ -0,0 +1,41 @@
+package graceful
+
+import "context"
+
+// Worker is implemented by polling workers that start in the background and
+// block in Stop until the work they are executing has finished, such as
+// worker.Worker from the Temporal Go SDK.
+type Worker interface {
+	Start() error
+	Stop()
+}
+
+// ManageWorker starts w and registers it to be stopped when shutdown begins,
+// before managed goroutines are canceled, so it stops polling for new
+// workflow and activity tasks while its dependencies are still available.
+// Stop is then given the remaining shutdown timeout to wait for executing
+// activities; if it does not return in time, shutdown moves on and leaves it
+// running in the background.
+//
+// Stop should be configured to return before the manager's timeout, for
+// Temporal via worker.Options.WorkerStopTimeout.
+//
+// Example:
+//
+//	w := worker.New(client, "orders", worker.Options{WorkerStopTimeout: 20 * time.Second})
+//	w.RegisterActivity(ChargeCard)
+//	if err := manager.ManageWorker(w); err != nil {
+//		log.Fatal(err)
+//	}
+func (m *Manager) ManageWorker(w Worker) error {
+	if err := w.Start(); err != nil {
+		return err
+	}
+	m.OnDrain(func(ctx context.Context) error {
+		if !runBounded(ctx, w.Stop) {
+			return ctx.Err()
+		}
+		return nil
+	})
+	return nil
+}
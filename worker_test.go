@@ -0,0 +1,75 @@
+package graceful
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeWorker struct {
+	startErr error
+	started  bool
+	stopped  chan struct{}
+	stopTime time.Duration
+}
+
+func (w *fakeWorker) Start() error {
+	w.started = true
+	return w.startErr
+}
+
+func (w *fakeWorker) Stop() {
+	time.Sleep(w.stopTime)
+	close(w.stopped)
+}
+
+// TestManageWorker 测试worker在goroutine取消前停止
+func TestManageWorker(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	task := m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+
+	w := &fakeWorker{stopped: make(chan struct{}), stopTime: time.Millisecond * 20}
+	if err := m.ManageWorker(w); err != nil {
+		t.Fatalf("启动worker不应返回错误，实际为%v", err)
+	}
+	if !w.started {
+		t.Error("ManageWorker应启动worker")
+	}
+
+	m.OnDrain(func(ctx context.Context) error {
+		select {
+		case <-w.stopped:
+		default:
+			t.Error("worker应在后续drain函数之前停止")
+		}
+		if task.Context().Err() != nil {
+			t.Error("worker停止时goroutine不应已被取消")
+		}
+		return nil
+	})
+
+	m.Shutdown()
+}
+
+// TestManageWorkerStopTimeout 测试worker停止超时时不阻塞关闭
+func TestManageWorkerStopTimeout(t *testing.T) {
+	m := New(WithTimeout(time.Millisecond * 50))
+
+	w := &fakeWorker{stopped: make(chan struct{}), stopTime: time.Second}
+	_ = m.ManageWorker(w)
+
+	start := time.Now()
+	m.Shutdown()
+	if d := time.Since(start); d > time.Millisecond*300 {
+		t.Errorf("worker停止超时后应继续关闭，实际等待了%v", d)
+	}
+
+	failing := &fakeWorker{startErr: errors.New("启动失败")}
+	if err := New().ManageWorker(failing); err == nil {
+		t.Error("worker启动失败时应返回错误")
+	}
+}
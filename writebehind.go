@@ -0,0 +1,96 @@
+package graceful
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Flusher is implemented by write-behind caches and other buffered components
+// that hold writes not yet persisted to their backing store.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// FlusherOption configures a flusher registered with RegisterFlusher.
+type FlusherOption func(*writeBehind)
+
+// WithFlusherBudget returns a FlusherOption that limits how long the flusher
+// may take. Without it, a flusher may use whatever remains of the shutdown
+// timeout.
+//
+// Example:
+//
+//	manager.RegisterFlusher(cache, graceful.WithFlusherBudget(3*time.Second))
+func WithFlusherBudget(budget time.Duration) FlusherOption {
+	return func(w *writeBehind) {
+		w.budget = budget
+	}
+}
+
+// writeBehind is a flusher registered with RegisterFlusher.
+type writeBehind struct {
+	flusher Flusher
+	budget  time.Duration // Time limit of the flush; zero means the shutdown timeout
+}
+
+// RegisterFlusher registers a write-behind component to be flushed during
+// shutdown, after managed goroutines have exited, so that its producers no
+// longer write to it, and before the shutdown hooks, so that the stores it
+// writes to are still open. Flushers run in registration order, each within
+// its budget, and an error is logged without stopping the others.
+//
+// This is distinct from OnFlush, whose functions run after the shutdown hooks
+// to deliver logs and reports about the shutdown.
+//
+// Example:
+//
+//	cache := newWriteBehindCache(db)
+//	manager.RegisterFlusher(cache)
+//	manager.OnShutdown(func(ctx context.Context) error { return db.Close() })
+func (m *Manager) RegisterFlusher(f Flusher, opts ...FlusherOption) {
+	w := &writeBehind{flusher: f}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writeBehinds = append(m.writeBehinds, w)
+}
+
+// flushWriteBehinds flushes the registered write-behind components. A
+// restart flushes them all but only drops those of the running generation,
+// so components registered before Run are flushed again at the final
+// shutdown.
+func (m *Manager) flushWriteBehinds(ctx context.Context) {
+	m.mu.Lock()
+	writeBehinds := m.writeBehinds
+	takeRegistrations(&m.writeBehinds, m.marks().writeBehinds, restarting(ctx))
+	m.mu.Unlock()
+
+	for _, w := range writeBehinds {
+		fctx, cancel := ctx, context.CancelFunc(func() {})
+		if w.budget > 0 {
+			fctx, cancel = context.WithTimeout(ctx, w.budget)
+		}
+		if err := w.flusher.Flush(fctx); err != nil {
+			m.logf("flushing %T failed: %v", w.flusher, err)
+		}
+		cancel()
+	}
+}
+
+// writeBehindSteps returns the dry-run steps of the registered flushers.
+// The caller must hold m.mu.
+func (m *Manager) writeBehindSteps() []PlanStep {
+	steps := make([]PlanStep, 0, len(m.writeBehinds))
+	for _, w := range m.writeBehinds {
+		budget := w.budget
+		if budget <= 0 || budget > m.timeout {
+			budget = m.timeout
+		}
+		steps = append(steps, PlanStep{Phase: "write-behind", Name: fmt.Sprintf("%T", w.flusher), Budget: budget})
+	}
+	return steps
+}
@@ -0,0 +1,67 @@
+package graceful
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// testFlusher 测试用的函数适配器
+type testFlusher func(ctx context.Context) error
+
+func (f testFlusher) Flush(ctx context.Context) error { return f(ctx) }
+
+// TestRegisterFlusher 测试写回缓存在任务结束后、关闭钩子前刷新
+func TestRegisterFlusher(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	var order []string
+	m.CtxGo(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 10)
+		order = append(order, "producer")
+	})
+	var deadline time.Time
+	m.RegisterFlusher(testFlusher(func(ctx context.Context) error {
+		deadline, _ = ctx.Deadline()
+		order = append(order, "flush")
+		return nil
+	}), WithFlusherBudget(time.Millisecond*100))
+	m.OnShutdown(func(ctx context.Context) error {
+		order = append(order, "store")
+		return nil
+	})
+	start := time.Now()
+	m.Shutdown()
+
+	want := []string{"producer", "flush", "store"}
+	if len(order) != len(want) || order[0] != want[0] || order[1] != want[1] || order[2] != want[2] {
+		t.Errorf("执行顺序应为%v，实际为%v", want, order)
+	}
+	if deadline.Sub(start) > time.Millisecond*500 {
+		t.Error("刷新应受自身预算限制")
+	}
+}
+
+// TestRegisterFlusherAcrossRestart 测试在Run之前注册的写回组件在重启时刷新后仍保留到最终关闭
+func TestRegisterFlusherAcrossRestart(t *testing.T) {
+	m := New(WithTimeout(time.Second))
+
+	flushes := 0
+	m.RegisterFlusher(testFlusher(func(ctx context.Context) error {
+		flushes++
+		return nil
+	}))
+	m.beginGeneration()
+
+	if err := m.Restart(); err != nil {
+		t.Fatalf("重启不应返回错误，实际为%v", err)
+	}
+	if flushes != 1 {
+		t.Errorf("重启时应刷新写回组件1次，实际为%d", flushes)
+	}
+	m.Shutdown()
+	if flushes != 2 {
+		t.Errorf("最终关闭时应再次刷新写回组件，实际共刷新%d次", flushes)
+	}
+}